@@ -4,28 +4,40 @@ import (
 	"errors"
 	"gocooking-backend/model"
 	"gocooking-backend/utils"
+	"time"
 )
 
 type Alimento struct {
-	Id                string           `json:"id"`
-	Nombre            string           `json:"nombre"`
-	Tipo              utils.TipoComida `json:"tipo"`
-	MomentosDeConsumo []utils.Momento  `json:"momentos_de_consumo"`
-	PrecioUnitario    float64          `json:"precio_unitario"`
-	CantidadActual    float64          `json:"cantidad_actual"`
-	CantidadMinima    float64          `json:"cantidad_minima"`
-	UsuarioID         string           `json:"usuario_id"`
+	Id                string                   `json:"id"`
+	Nombre            string                   `json:"nombre"`
+	CategoriaId       string                   `json:"categoria_id"`
+	MomentosDeConsumo []utils.Momento          `json:"momentos_de_consumo"`
+	Atributos         []utils.AtributoAlimento `json:"atributos"`
+	PrecioUnitario    float64                  `json:"precio_unitario"`
+	CantidadActual    float64                  `json:"cantidad_actual"`
+	CantidadMinima    float64                  `json:"cantidad_minima"`
+	Calorias          float64                  `json:"calorias,omitempty"`
+	FechaVencimiento  *time.Time               `json:"fecha_vencimiento,omitempty"`
+	UsuarioID         string                   `json:"usuario_id"`
 }
 
 func NewAlimento(alimento model.Alimento) *Alimento {
+	// Los alimentos anteriores a las categorías que no se pudieron migrar quedan sin categoría
+	categoriaID := ""
+	if !alimento.CategoriaID.IsZero() {
+		categoriaID = utils.GetStringIDFromObjectID(alimento.CategoriaID)
+	}
 	return &Alimento{
 		Id:                utils.GetStringIDFromObjectID(alimento.Id),
 		Nombre:            alimento.Nombre,
-		Tipo:              alimento.Tipo,
+		CategoriaId:       categoriaID,
 		MomentosDeConsumo: alimento.MomentosDeConsumo,
+		Atributos:         alimento.Atributos,
 		PrecioUnitario:    alimento.PrecioUnitario,
 		CantidadActual:    alimento.CantidadActual,
 		CantidadMinima:    alimento.CantidadMinima,
+		Calorias:          alimento.Calorias,
+		FechaVencimiento:  alimento.FechaVencimiento,
 		UsuarioID:         alimento.UsuarioID,
 	}
 }
@@ -34,11 +46,14 @@ func (alimento Alimento) GetModel() model.Alimento {
 	return model.Alimento{
 		Id:                utils.GetObjectIDFromStringID(alimento.Id),
 		Nombre:            alimento.Nombre,
-		Tipo:              alimento.Tipo,
+		CategoriaID:       utils.GetObjectIDFromStringID(alimento.CategoriaId),
 		MomentosDeConsumo: alimento.MomentosDeConsumo,
+		Atributos:         alimento.Atributos,
 		PrecioUnitario:    alimento.PrecioUnitario,
 		CantidadActual:    alimento.CantidadActual,
 		CantidadMinima:    alimento.CantidadMinima,
+		Calorias:          alimento.Calorias,
+		FechaVencimiento:  alimento.FechaVencimiento,
 		UsuarioID:         alimento.UsuarioID,
 	}
 }
@@ -56,11 +71,24 @@ func (alimento Alimento) Validate() error {
 	if alimento.PrecioUnitario <= 0 {
 		return errors.New("el precio unitario del alimento debe ser mayor a cero")
 	}
-	if alimento.Tipo < 1 || alimento.Tipo > utils.Fruta { // que el rango sea correcto
-		return errors.New("tipo de comida inválido")
+	if alimento.Calorias < 0 {
+		return errors.New("las calorías del alimento no pueden ser negativas")
+	}
+	if utils.GetObjectIDFromStringID(alimento.CategoriaId).IsZero() { // que la categoría exista se verifica al guardar
+		return errors.New("categoría inválida")
 	}
 	if len(alimento.MomentosDeConsumo) == 0 {
 		return errors.New("debe haber al menos un momento de consumo definido")
 	}
+	for _, momento := range alimento.MomentosDeConsumo {
+		if !momento.EsPredefinido() && !momento.EsPersonalizado() {
+			return errors.New("momento de consumo inválido")
+		}
+	}
+	for _, atributo := range alimento.Atributos {
+		if !atributo.EsValido() {
+			return errors.New("atributo del alimento inválido: " + string(atributo))
+		}
+	}
 	return nil
 }
@@ -0,0 +1,48 @@
+package dto
+
+import (
+	"errors"
+	"strings"
+)
+
+// Cantidad de sugerencias que devuelve el autocompletado si no se indica otra
+const LimiteAutocompletadoDefault = 10
+
+// Origen de una sugerencia de autocompletado
+const (
+	OrigenDespensa = "despensa"
+	OrigenCatalogo = "catalogo"
+)
+
+type ParametrosAutocompletado struct {
+	Texto    string `form:"q"`
+	Limite   int    `form:"limite"`
+	Catalogo bool   `form:"catalogo"` // Incluir los ingredientes de las recetas del catálogo público
+}
+
+func (parametros ParametrosAutocompletado) Validate() error {
+	if strings.TrimSpace(parametros.Texto) == "" {
+		return errors.New("debe indicar el texto a completar")
+	}
+	if parametros.Limite < 0 || parametros.Limite > 50 {
+		return errors.New("el límite debe estar entre 1 y 50")
+	}
+	return nil
+}
+
+// GetLimite devuelve el límite pedido o el valor por defecto
+func (parametros ParametrosAutocompletado) GetLimite() int {
+	if parametros.Limite == 0 {
+		return LimiteAutocompletadoDefault
+	}
+	return parametros.Limite
+}
+
+// SugerenciaAlimento es un nombre propuesto por el autocompletado. Las que vienen del catálogo no tienen id
+// porque todavía no son alimentos del usuario.
+type SugerenciaAlimento struct {
+	Id      string  `json:"id,omitempty"`
+	Nombre  string  `json:"nombre"`
+	Origen  string  `json:"origen"`
+	Puntaje float64 `json:"puntaje"`
+}
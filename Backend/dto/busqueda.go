@@ -0,0 +1,27 @@
+package dto
+
+import "gocooking-backend/model"
+
+type RecetaEncontrada struct {
+	Receta
+	Relevancia float64 `json:"relevancia"`
+}
+
+func NewRecetaEncontrada(encontrada model.RecetaEncontrada) *RecetaEncontrada {
+	return &RecetaEncontrada{
+		Receta:     *NewReceta(encontrada.Receta),
+		Relevancia: encontrada.Relevancia,
+	}
+}
+
+type AlimentoEncontrado struct {
+	Alimento
+	Relevancia float64 `json:"relevancia"`
+}
+
+func NewAlimentoEncontrado(encontrado model.AlimentoEncontrado) *AlimentoEncontrado {
+	return &AlimentoEncontrado{
+		Alimento:   *NewAlimento(encontrado.Alimento),
+		Relevancia: encontrado.Relevancia,
+	}
+}
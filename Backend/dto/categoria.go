@@ -0,0 +1,52 @@
+package dto
+
+import (
+	"errors"
+	"gocooking-backend/model"
+	"gocooking-backend/utils"
+	"strings"
+)
+
+type Categoria struct {
+	Id        string `json:"id"`
+	Nombre    string `json:"nombre"`
+	PadreId   string `json:"padre_id,omitempty"`
+	Sistema   bool   `json:"sistema"` // Las del sistema no se pueden eliminar
+	UsuarioID string `json:"usuario_id,omitempty"`
+}
+
+func NewCategoria(categoria model.Categoria) *Categoria {
+	categoriaDTO := &Categoria{
+		Id:        utils.GetStringIDFromObjectID(categoria.Id),
+		Nombre:    categoria.Nombre,
+		Sistema:   categoria.EsDelSistema(),
+		UsuarioID: categoria.UsuarioID,
+	}
+	if categoria.PadreID != nil {
+		categoriaDTO.PadreId = utils.GetStringIDFromObjectID(*categoria.PadreID)
+	}
+	return categoriaDTO
+}
+
+func (categoria Categoria) GetModel() model.Categoria {
+	categoriaModel := model.Categoria{
+		Id:        utils.GetObjectIDFromStringID(categoria.Id),
+		Nombre:    strings.TrimSpace(categoria.Nombre),
+		UsuarioID: categoria.UsuarioID,
+	}
+	if categoria.PadreId != "" {
+		padreID := utils.GetObjectIDFromStringID(categoria.PadreId)
+		categoriaModel.PadreID = &padreID
+	}
+	return categoriaModel
+}
+
+func (categoria Categoria) Validate() error {
+	if strings.TrimSpace(categoria.Nombre) == "" {
+		return errors.New("el nombre de la categoría es obligatorio")
+	}
+	if categoria.PadreId != "" && utils.GetObjectIDFromStringID(categoria.PadreId).IsZero() {
+		return errors.New("el ID de la categoría padre no es válido")
+	}
+	return nil
+}
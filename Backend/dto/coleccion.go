@@ -0,0 +1,67 @@
+package dto
+
+import (
+	"errors"
+	"gocooking-backend/model"
+	"gocooking-backend/utils"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Coleccion struct {
+	Id          string   `json:"id"`
+	Nombre      string   `json:"nombre"`
+	Descripcion string   `json:"descripcion"`
+	Recetas     []string `json:"recetas"`
+	UsuarioID   string   `json:"usuario_id"`
+}
+
+func NewColeccion(coleccion model.Coleccion) *Coleccion {
+	// Mapear los IDs de las recetas respetando el orden
+	recetasDTO := make([]string, len(coleccion.Recetas))
+	for i, recetaID := range coleccion.Recetas {
+		recetasDTO[i] = utils.GetStringIDFromObjectID(recetaID)
+	}
+
+	return &Coleccion{
+		Id:          utils.GetStringIDFromObjectID(coleccion.Id),
+		Nombre:      coleccion.Nombre,
+		Descripcion: coleccion.Descripcion,
+		Recetas:     recetasDTO,
+		UsuarioID:   coleccion.UsuarioID,
+	}
+}
+
+func (coleccion Coleccion) GetModel() model.Coleccion {
+	recetasModel := make([]primitive.ObjectID, len(coleccion.Recetas))
+	for i, recetaID := range coleccion.Recetas {
+		recetasModel[i] = utils.GetObjectIDFromStringID(recetaID)
+	}
+
+	return model.Coleccion{
+		Id:          utils.GetObjectIDFromStringID(coleccion.Id),
+		Nombre:      coleccion.Nombre,
+		Descripcion: coleccion.Descripcion,
+		Recetas:     recetasModel,
+		UsuarioID:   coleccion.UsuarioID,
+	}
+}
+
+func (coleccion Coleccion) Validate() error {
+	if coleccion.Nombre == "" {
+		return errors.New("el nombre de la colección es obligatorio")
+	}
+
+	// Verifica que los IDs de las recetas sean válidos y no se repitan
+	vistas := make(map[string]bool)
+	for _, recetaID := range coleccion.Recetas {
+		if !primitive.IsValidObjectID(recetaID) {
+			return errors.New("el ID de receta " + recetaID + " no es válido")
+		}
+		if vistas[recetaID] {
+			return errors.New("la receta " + recetaID + " está repetida en la colección")
+		}
+		vistas[recetaID] = true
+	}
+	return nil
+}
@@ -15,9 +15,10 @@ type Compra struct {
 }
 
 type ProductoCompra struct {
-	AlimentoID string  `json:"alimento_id"`
-	Nombre     string  `json:"nombre"`
-	Cantidad   float64 `json:"cantidad"`
+	AlimentoID  string  `json:"alimento_id"`
+	Nombre      string  `json:"nombre"`
+	Cantidad    float64 `json:"cantidad"`
+	CategoriaId string  `json:"categoria_id,omitempty"`
 }
 
 func NewCompra(compra model.Compra) *Compra {
@@ -60,11 +61,15 @@ func (compra Compra) GetModel() model.Compra {
 	}
 }
 func NewProductoCompra(prod model.ProductoCompra) *ProductoCompra {
-	return &ProductoCompra{
+	producto := &ProductoCompra{
 		AlimentoID: utils.GetStringIDFromObjectID(prod.AlimentoId),
 		Cantidad:   prod.Cantidad,
 		Nombre:     prod.Nombre,
 	}
+	if !prod.CategoriaID.IsZero() {
+		producto.CategoriaId = utils.GetStringIDFromObjectID(prod.CategoriaID)
+	}
+	return producto
 }
 
 func (prod ProductoCompra) GetModel() model.ProductoCompra {
@@ -0,0 +1,12 @@
+package dto
+
+// CopiaReceta son las opciones para copiar una receta del catálogo al recetario del usuario
+type CopiaReceta struct {
+	CrearFaltantes bool `json:"crear_faltantes"` // Crear los alimentos que el usuario no tiene, sin stock
+}
+
+// ResultadoCopia es la receta copiada junto con los alimentos que hubo que crear
+type ResultadoCopia struct {
+	Receta           *Receta  `json:"receta"`
+	AlimentosCreados []string `json:"alimentos_creados"`
+}
@@ -0,0 +1,20 @@
+package dto
+
+import "gocooking-backend/model"
+
+type CostoReceta struct {
+	Total                 float64  `json:"total"`
+	PorPorcion            float64  `json:"por_porcion"`
+	IngredientesSinPrecio []string `json:"ingredientes_sin_precio,omitempty"`
+}
+
+func NewCostoReceta(costo *model.CostoReceta) *CostoReceta {
+	if costo == nil {
+		return nil
+	}
+	return &CostoReceta{
+		Total:                 costo.Total,
+		PorPorcion:            costo.PorPorcion,
+		IngredientesSinPrecio: costo.IngredientesSinPrecio,
+	}
+}
@@ -0,0 +1,62 @@
+package dto
+
+import (
+	"errors"
+	"gocooking-backend/model"
+	"gocooking-backend/utils"
+	"slices"
+)
+
+// Equipamiento son los aparatos de cocina del usuario
+type Equipamiento struct {
+	Equipos     []utils.Equipo `json:"equipos"`
+	Configurado bool           `json:"configurado"` // Solo lectura, falso si el usuario nunca cargó su equipamiento
+	UsuarioID   string         `json:"usuario_id"`
+}
+
+func NewEquipamiento(equipamiento model.Equipamiento) *Equipamiento {
+	equipos := equipamiento.Equipos
+	if equipos == nil {
+		equipos = []utils.Equipo{}
+	}
+	return &Equipamiento{
+		Equipos:     equipos,
+		Configurado: true,
+		UsuarioID:   equipamiento.UsuarioID,
+	}
+}
+
+// NewEquipamientoSinConfigurar es el equipamiento de un usuario que todavía no lo cargó: no se sabe qué tiene
+func NewEquipamientoSinConfigurar(usuarioID string) *Equipamiento {
+	return &Equipamiento{
+		Equipos:   []utils.Equipo{},
+		UsuarioID: usuarioID,
+	}
+}
+
+func (equipamiento Equipamiento) GetModel() model.Equipamiento {
+	return model.Equipamiento{
+		Equipos:   equiposSinRepetir(equipamiento.Equipos),
+		UsuarioID: equipamiento.UsuarioID,
+	}
+}
+
+func (equipamiento Equipamiento) Validate() error {
+	for _, equipo := range equipamiento.Equipos {
+		if !equipo.EsValido() {
+			return errors.New("el equipamiento debe ser horno, microondas, procesadora o air_fryer")
+		}
+	}
+	return nil
+}
+
+// equiposSinRepetir devuelve los equipos en el orden en que aparecen, sin repetidos
+func equiposSinRepetir(equipos []utils.Equipo) []utils.Equipo {
+	resultado := []utils.Equipo{}
+	for _, equipo := range equipos {
+		if !slices.Contains(resultado, equipo) {
+			resultado = append(resultado, equipo)
+		}
+	}
+	return resultado
+}
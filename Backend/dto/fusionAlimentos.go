@@ -0,0 +1,31 @@
+package dto
+
+import "errors"
+
+// GrupoDuplicados reúne alimentos del usuario que parecen ser el mismo
+type GrupoDuplicados struct {
+	Alimentos []*Alimento `json:"alimentos"`
+}
+
+// FusionAlimentos indica el alimento que se conserva y los que se fusionan en él
+type FusionAlimentos struct {
+	AlimentoId string   `json:"alimento_id"`
+	Fusionados []string `json:"fusionados"`
+}
+
+func (fusion FusionAlimentos) Validate() error {
+	if fusion.AlimentoId == "" {
+		return errors.New("debe indicar el alimento que se conserva")
+	}
+	if len(fusion.Fusionados) == 0 {
+		return errors.New("debe indicar al menos un alimento a fusionar")
+	}
+	vistos := map[string]bool{fusion.AlimentoId: true}
+	for _, id := range fusion.Fusionados {
+		if vistos[id] {
+			return errors.New("los alimentos a fusionar no pueden repetirse ni incluir al que se conserva")
+		}
+		vistos[id] = true
+	}
+	return nil
+}
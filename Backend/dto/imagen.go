@@ -0,0 +1,27 @@
+package dto
+
+import (
+	"gocooking-backend/model"
+	"gocooking-backend/utils"
+	"time"
+)
+
+type Imagen struct {
+	Id            string    `json:"id"`
+	Tipo          string    `json:"tipo"`
+	Paso          int       `json:"paso,omitempty"`
+	ContentType   string    `json:"content_type"`
+	Tamano        int       `json:"tamano"`
+	FechaCreacion time.Time `json:"fecha_creacion"`
+}
+
+func NewImagen(imagen model.Imagen) *Imagen {
+	return &Imagen{
+		Id:            utils.GetStringIDFromObjectID(imagen.Id),
+		Tipo:          imagen.Tipo,
+		Paso:          imagen.Paso,
+		ContentType:   imagen.ContentType,
+		Tamano:        imagen.Tamano,
+		FechaCreacion: imagen.FechaCreacion,
+	}
+}
@@ -0,0 +1,54 @@
+package dto
+
+import (
+	"errors"
+	"gocooking-backend/model"
+	"gocooking-backend/utils"
+	"strings"
+)
+
+// Momento es un momento de consumo, predefinido o creado por el usuario
+type Momento struct {
+	Id            string        `json:"id,omitempty"` // Solo los personalizados, para eliminarlos
+	Codigo        utils.Momento `json:"codigo"`       // El valor que se usa en recetas y alimentos
+	Nombre        string        `json:"nombre"`
+	Personalizado bool          `json:"personalizado"`
+	UsuarioID     string        `json:"usuario_id,omitempty"`
+}
+
+func NewMomento(momento model.MomentoPersonalizado) *Momento {
+	return &Momento{
+		Id:            utils.GetStringIDFromObjectID(momento.Id),
+		Codigo:        momento.Codigo,
+		Nombre:        momento.Nombre,
+		Personalizado: true,
+		UsuarioID:     momento.UsuarioID,
+	}
+}
+
+func NewMomentoPredefinido(momento utils.Momento) *Momento {
+	return &Momento{
+		Codigo: momento,
+		Nombre: momento.String(),
+	}
+}
+
+func (momento Momento) GetModel() model.MomentoPersonalizado {
+	return model.MomentoPersonalizado{
+		Id:        utils.GetObjectIDFromStringID(momento.Id),
+		Nombre:    strings.TrimSpace(momento.Nombre),
+		UsuarioID: momento.UsuarioID,
+	}
+}
+
+func (momento Momento) Validate() error {
+	if strings.TrimSpace(momento.Nombre) == "" {
+		return errors.New("el nombre del momento es obligatorio")
+	}
+	for _, predefinido := range utils.MomentosPredefinidos() {
+		if utils.ClaveNombre(momento.Nombre) == utils.ClaveNombre(predefinido.String()) {
+			return errors.New("ya existe un momento predefinido con ese nombre")
+		}
+	}
+	return nil
+}
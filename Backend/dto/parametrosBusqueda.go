@@ -0,0 +1,32 @@
+package dto
+
+import (
+	"errors"
+	"strings"
+)
+
+// Cantidad de resultados que devuelve una búsqueda si no se indica otra
+const LimiteBusquedaDefault = 20
+
+type ParametrosBusqueda struct {
+	Texto  string `form:"q"`
+	Limite int    `form:"limite"`
+}
+
+func (parametros ParametrosBusqueda) Validate() error {
+	if strings.TrimSpace(parametros.Texto) == "" {
+		return errors.New("debe indicar el texto a buscar")
+	}
+	if parametros.Limite < 0 || parametros.Limite > 100 {
+		return errors.New("el límite debe estar entre 1 y 100")
+	}
+	return nil
+}
+
+// GetLimite devuelve el límite pedido o el valor por defecto
+func (parametros ParametrosBusqueda) GetLimite() int {
+	if parametros.Limite == 0 {
+		return LimiteBusquedaDefault
+	}
+	return parametros.Limite
+}
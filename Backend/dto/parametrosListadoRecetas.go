@@ -0,0 +1,32 @@
+package dto
+
+import "errors"
+
+// Criterios de orden admitidos en los listados de recetas
+const (
+	OrdenPuntuacion = "puntuacion"
+	OrdenCosto      = "costo" // De la más barata a la más cara por porción
+)
+
+type ParametrosListadoRecetas struct {
+	Etiqueta        string `form:"tag"`
+	Orden           string `form:"orden"`
+	ConCosto        bool   `form:"costo"`
+	ConEquipamiento bool   `form:"con_equipamiento"` // Solo las que se pueden preparar con el equipamiento del usuario
+}
+
+func (parametros ParametrosListadoRecetas) Validate() error {
+	return validarOrden(parametros.Orden)
+}
+
+// IncluyeCosto indica si hay que calcular el costo de las recetas del listado
+func (parametros ParametrosListadoRecetas) IncluyeCosto() bool {
+	return parametros.ConCosto || parametros.Orden == OrdenCosto
+}
+
+func validarOrden(orden string) error {
+	if orden != "" && orden != OrdenPuntuacion && orden != OrdenCosto {
+		return errors.New("criterio de orden inválido")
+	}
+	return nil
+}
@@ -2,16 +2,17 @@ package dto
 
 import (
 	"errors"
+	"gocooking-backend/utils"
 )
 
 type ParametrosProductosCantidad struct {
-	Tipo   int    `form:"tipo"`
-	Nombre string `form:"nombre"`
+	Categoria string `form:"categoria"` // Incluye las subcategorías
+	Nombre    string `form:"nombre"`
 }
 
 func (parametros ParametrosProductosCantidad) Validate() error {
-	if parametros.Tipo < 0 || parametros.Tipo > 6 {
-		return errors.New("tipo de comida invalido")
+	if parametros.Categoria != "" && utils.GetObjectIDFromStringID(parametros.Categoria).IsZero() {
+		return errors.New("categoría inválida")
 	}
 	return nil
 }
@@ -2,12 +2,18 @@ package dto
 
 import (
 	"errors"
+	"gocooking-backend/utils"
 )
 
 type ParametrosReceta struct {
-	Momento int    `form:"momento"`
-	Tipo    int    `form:"tipo"`
-	Nombre  string `form:"nombre"`
+	Momento         int     `form:"momento"`
+	Categoria       string  `form:"categoria"` // Incluye las subcategorías
+	Nombre          string  `form:"nombre"`
+	CostoMaximo     float64 `form:"costo_max"` // Costo máximo por porción
+	Dieta           string  `form:"dieta"`
+	Orden           string  `form:"orden"`
+	ConCosto        bool    `form:"costo"`
+	ConEquipamiento bool    `form:"con_equipamiento"` // Solo las que se pueden preparar con el equipamiento del usuario
 }
 
 // hay que corregir pq si no se les asigna valor arrancan en 0
@@ -16,12 +22,15 @@ func (parametros ParametrosReceta) Validate() error {
 	count := 0
 
 	// Verificar el campo Momento
-	if parametros.Momento >= 1 && parametros.Momento <= 4 {
+	if parametros.TieneMomento() {
 		count++
 	}
 
-	// Verificar el campo Tipo
-	if parametros.Tipo >= 1 && parametros.Tipo <= 6 {
+	// Verificar el campo Categoria
+	if parametros.Categoria != "" {
+		if utils.GetObjectIDFromStringID(parametros.Categoria).IsZero() {
+			return errors.New("categoría inválida")
+		}
 		count++
 	}
 
@@ -30,10 +39,37 @@ func (parametros ParametrosReceta) Validate() error {
 		count++
 	}
 
+	// Verificar el campo CostoMaximo
+	if parametros.CostoMaximo < 0 {
+		return errors.New("el costo máximo no puede ser negativo")
+	}
+	if parametros.CostoMaximo > 0 {
+		count++
+	}
+
+	// Verificar el campo Dieta
+	if parametros.Dieta != "" {
+		if !utils.Dieta(parametros.Dieta).EsValida() {
+			return errors.New("la dieta debe ser vegetariano, vegano, sin_lacteos, sin_gluten o alto_en_proteina")
+		}
+		count++
+	}
+
 	// Validar que al menos uno de los campos esté presente
 	if count == 0 {
-		return errors.New("debe proporcionar al menos uno de los parámetros (Momento, Tipo, Nombre)")
+		return errors.New("debe proporcionar al menos uno de los parámetros (Momento, Categoria, Nombre, CostoMaximo, Dieta)")
 	}
 
-	return nil
+	return validarOrden(parametros.Orden)
+}
+
+// IncluyeCosto indica si hay que calcular el costo de las recetas encontradas
+func (parametros ParametrosReceta) IncluyeCosto() bool {
+	return parametros.ConCosto || parametros.CostoMaximo > 0 || parametros.Orden == OrdenCosto
+}
+
+// TieneMomento indica si se pidió filtrar por un momento de consumo, predefinido o del usuario
+func (parametros ParametrosReceta) TieneMomento() bool {
+	momento := utils.Momento(parametros.Momento)
+	return momento.EsPredefinido() || momento.EsPersonalizado()
 }
@@ -0,0 +1,179 @@
+package dto
+
+import (
+	"errors"
+	"gocooking-backend/model"
+	"gocooking-backend/utils"
+	"slices"
+	"time"
+)
+
+// Días que abarca un plan generado
+const DiasPlan = 7
+
+// Máximo de recetas que se pueden pedir para cada comida
+const MaxRecetasPorMomento = 3
+
+// ParametrosPlan son las restricciones para generar un plan; sin fecha de inicio empieza hoy
+type ParametrosPlan struct {
+	FechaInicio       time.Time       `json:"fecha_inicio"`
+	Momentos          []utils.Momento `json:"momentos"`
+	Personas          int             `json:"personas"` // Una si no se indica
+	MaxRepeticiones   int             `json:"max_repeticiones"`
+	Dietas            []utils.Dieta   `json:"dietas"`
+	CaloriasDiarias   float64         `json:"calorias_diarias"`
+	Presupuesto       float64         `json:"presupuesto"`
+	RecetasPorMomento int             `json:"recetas_por_momento"` // Una si no se indica
+	UsuarioID         string          `json:"usuario_id"`
+}
+
+// CambioComida reemplaza la receta de una comida de un plan en borrador
+type CambioComida struct {
+	Dia      int           `json:"dia"`
+	Momento  utils.Momento `json:"momento"`
+	Orden    int           `json:"orden"` // Cuál de las recetas del momento, desde 0
+	RecetaId string        `json:"receta_id"`
+}
+
+type PlanSemanal struct {
+	Id            string           `json:"id"`
+	Estado        utils.EstadoPlan `json:"estado"`
+	FechaInicio   time.Time        `json:"fecha_inicio"`
+	Restricciones ParametrosPlan   `json:"restricciones"`
+	Comidas       []ComidaPlan     `json:"comidas"`
+	Compras       []ProductoCompra `json:"compras"`
+	CostoCompras  float64          `json:"costo_compras"`
+	UsuarioID     string           `json:"usuario_id"`
+}
+
+type ComidaPlan struct {
+	Dia          int           `json:"dia"`
+	Fecha        time.Time     `json:"fecha"`
+	Momento      utils.Momento `json:"momento"`
+	Orden        int           `json:"orden"`
+	RecetaId     string        `json:"receta_id"`
+	NombreReceta string        `json:"nombre_receta"`
+	Calorias     float64       `json:"calorias"`
+	CostoCompras float64       `json:"costo_compras"`
+	ConSobras    bool          `json:"con_sobras"`
+}
+
+func (parametros ParametrosPlan) Validate() error {
+	if len(parametros.Momentos) == 0 {
+		return errors.New("debe indicar al menos un momento de consumo")
+	}
+	for i, momento := range parametros.Momentos {
+		if !momento.EsPredefinido() && !momento.EsPersonalizado() {
+			return errors.New("momento de consumo inválido")
+		}
+		if slices.Contains(parametros.Momentos[:i], momento) {
+			return errors.New("los momentos de consumo no se pueden repetir")
+		}
+	}
+	if parametros.Personas < 0 {
+		return errors.New("la cantidad de personas no puede ser negativa")
+	}
+	if parametros.MaxRepeticiones < 0 {
+		return errors.New("la cantidad máxima de repeticiones no puede ser negativa")
+	}
+	for _, dieta := range parametros.Dietas {
+		if !dieta.EsValida() {
+			return errors.New("la dieta debe ser vegetariano, vegano, sin_lacteos, sin_gluten o alto_en_proteina")
+		}
+	}
+	if parametros.CaloriasDiarias < 0 {
+		return errors.New("las calorías diarias no pueden ser negativas")
+	}
+	if parametros.Presupuesto < 0 {
+		return errors.New("el presupuesto no puede ser negativo")
+	}
+	if parametros.RecetasPorMomento < 0 || parametros.RecetasPorMomento > MaxRecetasPorMomento {
+		return errors.New("las recetas por momento deben estar entre 1 y 3")
+	}
+	return nil
+}
+
+// GetModel arma el plan a generar, con los momentos en el orden del día y la fecha de inicio sin hora
+func (parametros ParametrosPlan) GetModel() model.PlanSemanal {
+	momentos := slices.Clone(parametros.Momentos)
+	slices.Sort(momentos)
+	personas := parametros.Personas
+	if personas == 0 {
+		personas = 1
+	}
+	recetasPorMomento := parametros.RecetasPorMomento
+	if recetasPorMomento == 0 {
+		recetasPorMomento = 1
+	}
+	inicio := parametros.FechaInicio
+	if inicio.IsZero() {
+		inicio = time.Now()
+	}
+	return model.PlanSemanal{
+		FechaInicio: time.Date(inicio.Year(), inicio.Month(), inicio.Day(), 0, 0, 0, 0, inicio.Location()),
+		Restricciones: model.RestriccionesPlan{
+			Momentos:          momentos,
+			Personas:          personas,
+			MaxRepeticiones:   parametros.MaxRepeticiones,
+			Dietas:            parametros.Dietas,
+			CaloriasDiarias:   parametros.CaloriasDiarias,
+			Presupuesto:       parametros.Presupuesto,
+			RecetasPorMomento: recetasPorMomento,
+		},
+		UsuarioID: parametros.UsuarioID,
+	}
+}
+
+func (cambio CambioComida) Validate() error {
+	if cambio.Dia < 0 || cambio.Dia >= DiasPlan {
+		return errors.New("el día debe estar entre 0 y 6")
+	}
+	if cambio.Orden < 0 || cambio.Orden >= MaxRecetasPorMomento {
+		return errors.New("el orden debe estar entre 0 y 2")
+	}
+	if utils.GetObjectIDFromStringID(cambio.RecetaId).IsZero() {
+		return errors.New("el ID de la receta es obligatorio")
+	}
+	return nil
+}
+
+func NewPlanSemanal(plan model.PlanSemanal) *PlanSemanal {
+	comidas := []ComidaPlan{}
+	for _, comida := range plan.Comidas {
+		comidas = append(comidas, ComidaPlan{
+			Dia:          comida.Dia,
+			Fecha:        plan.FechaInicio.AddDate(0, 0, comida.Dia),
+			Momento:      comida.Momento,
+			Orden:        comida.Orden,
+			RecetaId:     utils.GetStringIDFromObjectID(comida.RecetaID),
+			NombreReceta: comida.NombreReceta,
+			Calorias:     comida.Calorias,
+			CostoCompras: comida.CostoCompras,
+			ConSobras:    comida.ConSobras,
+		})
+	}
+	compras := []ProductoCompra{}
+	for _, producto := range plan.Compras {
+		compras = append(compras, *NewProductoCompra(producto))
+	}
+	return &PlanSemanal{
+		Id:          utils.GetStringIDFromObjectID(plan.Id),
+		Estado:      plan.Estado,
+		FechaInicio: plan.FechaInicio,
+		Restricciones: ParametrosPlan{
+			FechaInicio:       plan.FechaInicio,
+			Momentos:          plan.Restricciones.Momentos,
+			Personas:          plan.Restricciones.Personas,
+			MaxRepeticiones:   plan.Restricciones.MaxRepeticiones,
+			Dietas:            plan.Restricciones.Dietas,
+			CaloriasDiarias:   plan.Restricciones.CaloriasDiarias,
+			Presupuesto:       plan.Restricciones.Presupuesto,
+			RecetasPorMomento: plan.Restricciones.CantidadRecetasPorMomento(),
+			UsuarioID:         plan.UsuarioID,
+		},
+		Comidas:      comidas,
+		Compras:      compras,
+		CostoCompras: plan.CostoCompras,
+		UsuarioID:    plan.UsuarioID,
+	}
+}
@@ -4,39 +4,97 @@ import (
 	"errors"
 	"gocooking-backend/model"
 	"gocooking-backend/utils"
+	"slices"
+	"strings"
 )
 
 type Receta struct {
-	Id               string        `json:"id"`
-	Nombre           string        `json:"nombre"`
-	MomentoDeConsumo utils.Momento `json:"momento_consumo"`
-	Ingredientes     []Ingrediente `json:"ingredientes"`
-	UsuarioID        string        `json:"usuario_id"`
+	Id                   string             `json:"id"`
+	Nombre               string             `json:"nombre"`
+	MomentoDeConsumo     utils.Momento      `json:"momento_consumo"` // El primero de los momentos, para los clientes anteriores
+	MomentosDeConsumo    []utils.Momento    `json:"momentos_consumo"`
+	Ingredientes         []Ingrediente      `json:"ingredientes"`
+	Pasos                []string           `json:"pasos"`
+	Porciones            int                `json:"porciones"`
+	TiempoPreparacion    int                `json:"tiempo_preparacion"`
+	TiempoCoccion        int                `json:"tiempo_coccion"`
+	Etiquetas            []string           `json:"etiquetas"`
+	Equipamiento         []utils.Equipo     `json:"equipamiento"`
+	Dietas               []utils.Dieta      `json:"dietas"`   // Solo lectura, se deducen de los alimentos
+	Imagenes             []Imagen           `json:"imagenes"` // Solo lectura, se administran desde /recetas/:id/imagenes
+	Visibilidad          utils.Visibilidad  `json:"visibilidad"`
+	CompartidaCon        []string           `json:"compartida_con"`
+	Sustituciones        []SustitucionUsada `json:"sustituciones,omitempty"`         // Solo lectura, sustitutos necesarios por falta de stock
+	Costo                *CostoReceta       `json:"costo,omitempty"`                 // Solo lectura, en los listados que lo piden
+	PorcionesSobrantes   int                `json:"porciones_sobrantes,omitempty"`   // Solo lectura, sobras guardadas sin vencer
+	EquipamientoFaltante []utils.Equipo     `json:"equipamiento_faltante,omitempty"` // Solo lectura, equipos requeridos que el usuario no tiene
+	UsuarioID            string             `json:"usuario_id"`
 }
 
 type Ingrediente struct {
-	AlimentoId string  `json:"alimento_id"`
-	Nombre     string  `json:"nombre"`
-	Cantidad   float64 `json:"cantidad"`
+	AlimentoId  string  `json:"alimento_id"`
+	RecetaId    string  `json:"receta_id,omitempty"` // Subreceta en lugar de alimento; la cantidad son porciones
+	Nombre      string  `json:"nombre"`
+	Cantidad    float64 `json:"cantidad"`
+	Unidad      string  `json:"unidad,omitempty"`
+	Opcional    bool    `json:"opcional,omitempty"`
+	AGusto      bool    `json:"a_gusto,omitempty"`      // Sin cantidad fija; si se indica una cantidad es aproximada
+	SinResolver bool    `json:"sin_resolver,omitempty"` // Ingrediente importado que no se pudo asociar a un alimento del usuario
+}
+
+func NewIngrediente(ingrediente model.Ingrediente) *Ingrediente {
+	// Las subrecetas no tienen alimento
+	alimentoID, recetaID := "", ""
+	if !ingrediente.AlimentoId.IsZero() {
+		alimentoID = utils.GetStringIDFromObjectID(ingrediente.AlimentoId)
+	}
+	if ingrediente.EsSubreceta() {
+		recetaID = utils.GetStringIDFromObjectID(ingrediente.RecetaId)
+	}
+	return &Ingrediente{
+		AlimentoId: alimentoID,
+		RecetaId:   recetaID,
+		Cantidad:   ingrediente.Cantidad,
+		Nombre:     ingrediente.Nombre,
+		Unidad:     ingrediente.Unidad,
+		Opcional:   ingrediente.Opcional,
+		AGusto:     ingrediente.AGusto,
+	}
 }
 
 func NewReceta(receta model.Receta) *Receta {
 	// Mapear cada ingrediente del model a dto
 	ingredientesDTO := make([]Ingrediente, len(receta.Ingredientes))
 	for i, ing := range receta.Ingredientes {
-		ingredientesDTO[i] = Ingrediente{
-			AlimentoId: utils.GetStringIDFromObjectID(ing.AlimentoId),
-			Cantidad:   ing.Cantidad,
-			Nombre:     ing.Nombre,
-		}
+		ingredientesDTO[i] = *NewIngrediente(ing)
+	}
+
+	imagenesDTO := make([]Imagen, len(receta.Imagenes))
+	for i, imagen := range receta.Imagenes {
+		imagenesDTO[i] = *NewImagen(imagen)
 	}
 
 	return &Receta{
-		Id:               utils.GetStringIDFromObjectID(receta.Id),
-		Nombre:           receta.Nombre,
-		MomentoDeConsumo: receta.MomentoDeConsumo,
-		Ingredientes:     ingredientesDTO,
-		UsuarioID:        receta.UsuarioID,
+		Id:                   utils.GetStringIDFromObjectID(receta.Id),
+		Nombre:               receta.Nombre,
+		MomentoDeConsumo:     receta.MomentoDeConsumo,
+		MomentosDeConsumo:    receta.Momentos(),
+		Ingredientes:         ingredientesDTO,
+		Pasos:                receta.Pasos,
+		Porciones:            receta.Porciones,
+		TiempoPreparacion:    receta.TiempoPreparacion,
+		TiempoCoccion:        receta.TiempoCoccion,
+		Etiquetas:            receta.Etiquetas,
+		Equipamiento:         receta.Equipamiento,
+		Dietas:               receta.Dietas,
+		Imagenes:             imagenesDTO,
+		Visibilidad:          receta.Visibilidad,
+		CompartidaCon:        receta.CompartidaCon,
+		Sustituciones:        NewSustitucionesUsadas(receta.Sustituciones),
+		Costo:                NewCostoReceta(receta.Costo),
+		PorcionesSobrantes:   receta.PorcionesSobrantes,
+		EquipamientoFaltante: receta.EquipamientoFaltante,
+		UsuarioID:            receta.UsuarioID,
 	}
 }
 func (receta Receta) GetModel() model.Receta {
@@ -45,49 +103,168 @@ func (receta Receta) GetModel() model.Receta {
 	for i, ing := range receta.Ingredientes {
 		ingredientesModel[i] = model.Ingrediente{
 			AlimentoId: utils.GetObjectIDFromStringID(ing.AlimentoId),
+			RecetaId:   utils.GetObjectIDFromStringID(ing.RecetaId),
 			Cantidad:   ing.Cantidad,
 			Nombre:     ing.Nombre,
+			Unidad:     ing.Unidad,
+			Opcional:   ing.Opcional,
+			AGusto:     ing.AGusto,
 		}
 	}
 
+	// Las recetas son privadas salvo que se indique otra cosa, y solo las compartidas guardan usuarios
+	visibilidad := receta.Visibilidad
+	if visibilidad == "" {
+		visibilidad = utils.VisibilidadPrivada
+	}
+	compartidaCon := []string{}
+	if visibilidad == utils.VisibilidadCompartida {
+		compartidaCon = receta.CompartidaCon
+	}
+
+	momentos := receta.momentos()
+	momento := utils.MomentoDefault
+	if len(momentos) > 0 {
+		momento = momentos[0]
+	}
+
 	return model.Receta{
-		Id:               utils.GetObjectIDFromStringID(receta.Id),
-		Nombre:           receta.Nombre,
-		MomentoDeConsumo: receta.MomentoDeConsumo,
-		Ingredientes:     ingredientesModel,
-		UsuarioID:        receta.UsuarioID,
+		Id:                utils.GetObjectIDFromStringID(receta.Id),
+		Nombre:            receta.Nombre,
+		MomentoDeConsumo:  momento,
+		MomentosDeConsumo: momentos,
+		Ingredientes:      ingredientesModel,
+		Pasos:             receta.Pasos,
+		Porciones:         receta.Porciones,
+		TiempoPreparacion: receta.TiempoPreparacion,
+		TiempoCoccion:     receta.TiempoCoccion,
+		Etiquetas:         NormalizarEtiquetas(receta.Etiquetas),
+		Equipamiento:      equiposSinRepetir(receta.Equipamiento),
+		Visibilidad:       visibilidad,
+		CompartidaCon:     compartidaCon,
+		UsuarioID:         receta.UsuarioID,
 	}
 
 }
 
 func (receta Receta) Validate() error {
+	if problemas := receta.Problemas(); len(problemas) > 0 {
+		return errors.New(problemas[0].Mensaje)
+	}
+	return nil
+}
+
+// Problemas devuelve todos los datos inválidos de la receta, en el orden en que los informa Validate
+func (receta Receta) Problemas() []model.ProblemaReceta {
+	problemas := []model.ProblemaReceta{}
+	agregar := func(campo string, indice *int, mensaje string) {
+		problemas = append(problemas, model.ProblemaReceta{Tipo: model.ProblemaCampo, Campo: campo, Indice: indice, Mensaje: mensaje})
+	}
+
 	// Verifica que el nombre no esté vacío
 	if receta.Nombre == "" {
-		return errors.New("el nombre de la receta es obligatorio")
+		agregar("nombre", nil, "el nombre de la receta es obligatorio")
 	}
 
-	// Verifica que el momento de consumo sea válido
-	if receta.MomentoDeConsumo < 1 || receta.MomentoDeConsumo > 4 {
-		return errors.New("el momento de consumo no es válido")
+	// Verifica que haya al menos un momento de consumo y que sean válidos; que los personalizados existan se verifica al guardar
+	if len(receta.momentos()) == 0 {
+		agregar("momentos_consumo", nil, "debe haber al menos un momento de consumo")
+	}
+	for i, momento := range receta.momentos() {
+		if !momento.EsPredefinido() && !momento.EsPersonalizado() {
+			agregar("momentos_consumo", &i, "el momento de consumo no es válido")
+		}
 	}
 
 	// Verifica que haya al menos un ingrediente
 	if len(receta.Ingredientes) == 0 {
-		return errors.New("debe haber al menos un ingrediente en la receta")
+		agregar("ingredientes", nil, "debe haber al menos un ingrediente en la receta")
 	}
 
-	// Verifica que cada ingrediente tenga una cantidad válida
-	for _, ingrediente := range receta.Ingredientes {
-		if ingrediente.AlimentoId == "" {
-			return errors.New("el ID del alimento es obligatorio en todos los ingredientes")
+	// Verifica que cada ingrediente tenga una cantidad válida; los ingredientes a gusto pueden no tenerla
+	for i, ingrediente := range receta.Ingredientes {
+		if (ingrediente.AlimentoId == "") == (ingrediente.RecetaId == "") {
+			agregar("ingredientes", &i, "cada ingrediente debe tener el ID de un alimento o el de una receta, no ambos")
+		}
+		if ingrediente.RecetaId != "" && ingrediente.RecetaId == receta.Id {
+			agregar("ingredientes", &i, "la receta no puede usarse como ingrediente de sí misma")
 		}
-		if ingrediente.Cantidad <= 0 {
-			return errors.New("la cantidad de cada ingrediente debe ser mayor que cero")
+		if ingrediente.Cantidad < 0 || (ingrediente.Cantidad == 0 && !ingrediente.AGusto) {
+			agregar("ingredientes", &i, "la cantidad de cada ingrediente debe ser mayor que cero, salvo en los ingredientes a gusto")
 		}
 		if ingrediente.Nombre == "" {
-			return errors.New("el nombre del ingrediente es obligatorio")
+			agregar("ingredientes", &i, "el nombre del ingrediente es obligatorio")
 		}
 	}
 
-	return nil
+	// Verifica que las porciones y los tiempos no sean negativos
+	if receta.Porciones < 0 {
+		agregar("porciones", nil, "la cantidad de porciones no puede ser negativa")
+	}
+	if receta.TiempoPreparacion < 0 {
+		agregar("tiempo_preparacion", nil, "los tiempos de la receta no pueden ser negativos")
+	}
+	if receta.TiempoCoccion < 0 {
+		agregar("tiempo_coccion", nil, "los tiempos de la receta no pueden ser negativos")
+	}
+
+	// Verifica que los equipos requeridos sean conocidos
+	for i, equipo := range receta.Equipamiento {
+		if !equipo.EsValido() {
+			agregar("equipamiento", &i, "el equipamiento debe ser horno, microondas, procesadora o air_fryer")
+		}
+	}
+
+	// Verifica que no haya pasos vacíos
+	for i, paso := range receta.Pasos {
+		if strings.TrimSpace(paso) == "" {
+			agregar("pasos", &i, "los pasos de la receta no pueden estar vacíos")
+		}
+	}
+
+	// Verifica la visibilidad y que las recetas compartidas indiquen con quién
+	if receta.Visibilidad != "" && !receta.Visibilidad.EsValida() {
+		agregar("visibilidad", nil, "la visibilidad debe ser privada, compartida o publica")
+	}
+	if receta.Visibilidad == utils.VisibilidadCompartida {
+		if len(receta.CompartidaCon) == 0 {
+			agregar("compartida_con", nil, "debe indicar con qué usuarios se comparte la receta")
+		}
+		for i, usuarioID := range receta.CompartidaCon {
+			if usuarioID == "" {
+				agregar("compartida_con", &i, "los usuarios con los que se comparte la receta no pueden estar vacíos")
+			}
+		}
+	}
+
+	return problemas
+}
+
+// NormalizarEtiquetas pasa las etiquetas a minúsculas, quita espacios sobrantes y elimina vacías y repetidas
+func NormalizarEtiquetas(etiquetas []string) []string {
+	normalizadas := []string{}
+	vistas := make(map[string]bool)
+	for _, etiqueta := range etiquetas {
+		etiqueta = strings.ToLower(strings.TrimSpace(etiqueta))
+		if etiqueta == "" || vistas[etiqueta] {
+			continue
+		}
+		vistas[etiqueta] = true
+		normalizadas = append(normalizadas, etiqueta)
+	}
+	return normalizadas
+}
+
+// momentos devuelve los momentos de consumo sin repetir; los clientes anteriores solo envían momento_consumo
+func (receta Receta) momentos() []utils.Momento {
+	momentos := []utils.Momento{}
+	for _, momento := range receta.MomentosDeConsumo {
+		if !slices.Contains(momentos, momento) {
+			momentos = append(momentos, momento)
+		}
+	}
+	if len(momentos) == 0 && receta.MomentoDeConsumo != utils.MomentoDefault {
+		momentos = append(momentos, receta.MomentoDeConsumo)
+	}
+	return momentos
 }
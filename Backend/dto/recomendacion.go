@@ -0,0 +1,42 @@
+package dto
+
+import (
+	"errors"
+	"gocooking-backend/model"
+)
+
+// Cantidad de recetas que se recomiendan si no se indica otra
+const LimiteRecomendacionesDefault = 10
+
+type ParametrosRecomendaciones struct {
+	Limite int `form:"limite"`
+}
+
+func (parametros ParametrosRecomendaciones) Validate() error {
+	if parametros.Limite < 0 || parametros.Limite > 50 {
+		return errors.New("el límite debe estar entre 1 y 50")
+	}
+	return nil
+}
+
+// GetLimite devuelve el límite pedido o el valor por defecto
+func (parametros ParametrosRecomendaciones) GetLimite() int {
+	if parametros.Limite == 0 {
+		return LimiteRecomendacionesDefault
+	}
+	return parametros.Limite
+}
+
+type RecetaRecomendada struct {
+	Receta
+	Puntaje float64  `json:"puntaje"`
+	Motivos []string `json:"motivos"`
+}
+
+func NewRecetaRecomendada(recomendada model.RecetaRecomendada) *RecetaRecomendada {
+	return &RecetaRecomendada{
+		Receta:  *NewReceta(recomendada.Receta),
+		Puntaje: recomendada.Puntaje,
+		Motivos: recomendada.Motivos,
+	}
+}
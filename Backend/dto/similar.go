@@ -0,0 +1,45 @@
+package dto
+
+import (
+	"errors"
+	"gocooking-backend/model"
+)
+
+// Cantidad de recetas similares que se devuelven si no se indica otra
+const LimiteSimilaresDefault = 10
+
+type ParametrosSimilares struct {
+	Catalogo bool `form:"catalogo"` // Incluir las recetas públicas de otros usuarios
+	Limite   int  `form:"limite"`
+}
+
+func (parametros ParametrosSimilares) Validate() error {
+	if parametros.Limite < 0 || parametros.Limite > 50 {
+		return errors.New("el límite debe estar entre 1 y 50")
+	}
+	return nil
+}
+
+// GetLimite devuelve el límite pedido o el valor por defecto
+func (parametros ParametrosSimilares) GetLimite() int {
+	if parametros.Limite == 0 {
+		return LimiteSimilaresDefault
+	}
+	return parametros.Limite
+}
+
+type RecetaSimilar struct {
+	Receta
+	Similitud        float64  `json:"similitud"`
+	AlimentosEnComun []string `json:"alimentos_en_comun"`
+	MismoMomento     bool     `json:"mismo_momento"`
+}
+
+func NewRecetaSimilar(similar model.RecetaSimilar) *RecetaSimilar {
+	return &RecetaSimilar{
+		Receta:           *NewReceta(similar.Receta),
+		Similitud:        similar.Similitud,
+		AlimentosEnComun: similar.AlimentosEnComun,
+		MismoMomento:     similar.MismoMomento,
+	}
+}
@@ -0,0 +1,73 @@
+package dto
+
+import (
+	"errors"
+	"gocooking-backend/model"
+	"gocooking-backend/utils"
+	"time"
+)
+
+type Sobra struct {
+	Id               string    `json:"id"`
+	RecetaId         string    `json:"receta_id"`
+	NombreReceta     string    `json:"nombre_receta"` // Solo lectura
+	Porciones        int       `json:"porciones"`
+	FechaVencimiento time.Time `json:"fecha_vencimiento"`
+	Vencida          bool      `json:"vencida"` // Solo lectura
+	UsuarioID        string    `json:"usuario_id"`
+}
+
+// ConsumoSobra indica cuántas porciones de la sobra se comen; sin porciones se come una
+type ConsumoSobra struct {
+	Porciones int `json:"porciones"`
+}
+
+func NewSobra(sobra model.Sobra) *Sobra {
+	return &Sobra{
+		Id:               utils.GetStringIDFromObjectID(sobra.Id),
+		RecetaId:         utils.GetStringIDFromObjectID(sobra.RecetaID),
+		NombreReceta:     sobra.NombreReceta,
+		Porciones:        sobra.Porciones,
+		FechaVencimiento: sobra.FechaVencimiento,
+		Vencida:          sobra.Vencida(time.Now()),
+		UsuarioID:        sobra.UsuarioID,
+	}
+}
+
+func (sobra Sobra) GetModel() model.Sobra {
+	return model.Sobra{
+		Id:               utils.GetObjectIDFromStringID(sobra.Id),
+		RecetaID:         utils.GetObjectIDFromStringID(sobra.RecetaId),
+		Porciones:        sobra.Porciones,
+		FechaVencimiento: sobra.FechaVencimiento,
+		UsuarioID:        sobra.UsuarioID,
+	}
+}
+
+func (sobra Sobra) Validate() error {
+	if utils.GetObjectIDFromStringID(sobra.RecetaId).IsZero() {
+		return errors.New("el ID de la receta es obligatorio")
+	}
+	if sobra.Porciones <= 0 {
+		return errors.New("la cantidad de porciones debe ser mayor que cero")
+	}
+	if !sobra.FechaVencimiento.After(time.Now()) {
+		return errors.New("la fecha de vencimiento debe ser posterior a hoy")
+	}
+	return nil
+}
+
+func (consumo ConsumoSobra) Validate() error {
+	if consumo.Porciones < 0 {
+		return errors.New("la cantidad de porciones no puede ser negativa")
+	}
+	return nil
+}
+
+// PorcionesAComer devuelve las porciones pedidas, una si no se indicó
+func (consumo ConsumoSobra) PorcionesAComer() int {
+	if consumo.Porciones == 0 {
+		return 1
+	}
+	return consumo.Porciones
+}
@@ -0,0 +1,95 @@
+package dto
+
+import (
+	"errors"
+	"gocooking-backend/model"
+	"gocooking-backend/utils"
+)
+
+type Sustitucion struct {
+	Id          string  `json:"id"`
+	AlimentoId  string  `json:"alimento_id"`
+	SustitutoId string  `json:"sustituto_id"`
+	Proporcion  float64 `json:"proporcion"` // Cantidad del sustituto por cada unidad del alimento
+	Reciproca   bool    `json:"reciproca"`
+	RecetaId    string  `json:"receta_id,omitempty"` // Vacío para que valga en todas las recetas
+	UsuarioID   string  `json:"usuario_id"`
+}
+
+func NewSustitucion(sustitucion model.Sustitucion) *Sustitucion {
+	sustitucionDTO := &Sustitucion{
+		Id:          utils.GetStringIDFromObjectID(sustitucion.Id),
+		AlimentoId:  utils.GetStringIDFromObjectID(sustitucion.AlimentoID),
+		SustitutoId: utils.GetStringIDFromObjectID(sustitucion.SustitutoID),
+		Proporcion:  sustitucion.Proporcion,
+		Reciproca:   sustitucion.Reciproca,
+		UsuarioID:   sustitucion.UsuarioID,
+	}
+	if sustitucion.RecetaID != nil {
+		sustitucionDTO.RecetaId = utils.GetStringIDFromObjectID(*sustitucion.RecetaID)
+	}
+	return sustitucionDTO
+}
+
+func (sustitucion Sustitucion) GetModel() model.Sustitucion {
+	sustitucionModel := model.Sustitucion{
+		Id:          utils.GetObjectIDFromStringID(sustitucion.Id),
+		AlimentoID:  utils.GetObjectIDFromStringID(sustitucion.AlimentoId),
+		SustitutoID: utils.GetObjectIDFromStringID(sustitucion.SustitutoId),
+		Proporcion:  sustitucion.Proporcion,
+		Reciproca:   sustitucion.Reciproca,
+		UsuarioID:   sustitucion.UsuarioID,
+	}
+	if sustitucion.RecetaId != "" {
+		recetaID := utils.GetObjectIDFromStringID(sustitucion.RecetaId)
+		sustitucionModel.RecetaID = &recetaID
+	}
+	return sustitucionModel
+}
+
+func (sustitucion Sustitucion) Validate() error {
+	if sustitucion.AlimentoId == "" || sustitucion.SustitutoId == "" {
+		return errors.New("el alimento y su sustituto son obligatorios")
+	}
+	if utils.GetObjectIDFromStringID(sustitucion.AlimentoId).IsZero() || utils.GetObjectIDFromStringID(sustitucion.SustitutoId).IsZero() {
+		return errors.New("el ID del alimento o del sustituto no es válido")
+	}
+	if sustitucion.AlimentoId == sustitucion.SustitutoId {
+		return errors.New("un alimento no puede ser sustituto de sí mismo")
+	}
+	if sustitucion.Proporcion <= 0 {
+		return errors.New("la proporción debe ser mayor que cero")
+	}
+	if sustitucion.RecetaId != "" && utils.GetObjectIDFromStringID(sustitucion.RecetaId).IsZero() {
+		return errors.New("el ID de la receta no es válido")
+	}
+	return nil
+}
+
+// SustitucionUsada informa que un ingrediente se cubre con otro alimento por falta de stock
+type SustitucionUsada struct {
+	AlimentoId        string  `json:"alimento_id"`
+	Nombre            string  `json:"nombre"`
+	Cantidad          float64 `json:"cantidad"`
+	SustitutoId       string  `json:"sustituto_id"`
+	NombreSustituto   string  `json:"nombre_sustituto"`
+	CantidadSustituto float64 `json:"cantidad_sustituto"`
+}
+
+func NewSustitucionesUsadas(sustituciones []model.SustitucionUsada) []SustitucionUsada {
+	if len(sustituciones) == 0 {
+		return nil
+	}
+	usadas := make([]SustitucionUsada, len(sustituciones))
+	for i, sustitucion := range sustituciones {
+		usadas[i] = SustitucionUsada{
+			AlimentoId:        utils.GetStringIDFromObjectID(sustitucion.AlimentoId),
+			Nombre:            sustitucion.Nombre,
+			Cantidad:          sustitucion.Cantidad,
+			SustitutoId:       utils.GetStringIDFromObjectID(sustitucion.SustitutoId),
+			NombreSustituto:   sustitucion.NombreSustituto,
+			CantidadSustituto: sustitucion.CantidadSustituto,
+		}
+	}
+	return usadas
+}
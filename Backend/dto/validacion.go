@@ -0,0 +1,47 @@
+package dto
+
+import (
+	"gocooking-backend/model"
+	"gocooking-backend/utils"
+)
+
+// ProblemaReceta indica qué corregir en la receta; Campo e Indice ubican el dato en el editor
+type ProblemaReceta struct {
+	Tipo       string        `json:"tipo"`
+	Campo      string        `json:"campo,omitempty"`
+	Indice     *int          `json:"indice,omitempty"`
+	AlimentoId string        `json:"alimento_id,omitempty"`
+	Momento    utils.Momento `json:"momento,omitempty"`
+	Faltante   float64       `json:"faltante,omitempty"`
+	Mensaje    string        `json:"mensaje"`
+}
+
+type ValidacionReceta struct {
+	Valida        bool               `json:"valida"`
+	Problemas     []ProblemaReceta   `json:"problemas"`
+	Sustituciones []SustitucionUsada `json:"sustituciones,omitempty"`
+}
+
+func NewValidacionReceta(validacion model.ValidacionReceta) *ValidacionReceta {
+	problemas := []ProblemaReceta{}
+	for _, problema := range validacion.Problemas {
+		alimentoID := ""
+		if !problema.AlimentoId.IsZero() {
+			alimentoID = utils.GetStringIDFromObjectID(problema.AlimentoId)
+		}
+		problemas = append(problemas, ProblemaReceta{
+			Tipo:       problema.Tipo,
+			Campo:      problema.Campo,
+			Indice:     problema.Indice,
+			AlimentoId: alimentoID,
+			Momento:    problema.Momento,
+			Faltante:   problema.Faltante,
+			Mensaje:    problema.Mensaje,
+		})
+	}
+	return &ValidacionReceta{
+		Valida:        len(problemas) == 0,
+		Problemas:     problemas,
+		Sustituciones: NewSustitucionesUsadas(validacion.Sustituciones),
+	}
+}
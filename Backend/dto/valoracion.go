@@ -0,0 +1,82 @@
+package dto
+
+import (
+	"errors"
+	"gocooking-backend/model"
+	"gocooking-backend/utils"
+	"strings"
+	"time"
+)
+
+type Valoracion struct {
+	RecetaID   string `json:"receta_id"`
+	Puntuacion int    `json:"puntuacion"`
+	Favorita   bool   `json:"favorita"`
+	Notas      []Nota `json:"notas"`
+	UsuarioID  string `json:"usuario_id"`
+}
+
+type Nota struct {
+	Id            string     `json:"id"`
+	Texto         string     `json:"texto"`
+	FechaCoccion  *time.Time `json:"fecha_coccion,omitempty"`
+	FechaCreacion time.Time  `json:"fecha_creacion"`
+}
+
+func NewValoracion(valoracion model.Valoracion) *Valoracion {
+	notasDTO := make([]Nota, len(valoracion.Notas))
+	for i, nota := range valoracion.Notas {
+		notasDTO[i] = *NewNota(nota)
+	}
+
+	return &Valoracion{
+		RecetaID:   utils.GetStringIDFromObjectID(valoracion.RecetaID),
+		Puntuacion: valoracion.Puntuacion,
+		Favorita:   valoracion.Favorita,
+		Notas:      notasDTO,
+		UsuarioID:  valoracion.UsuarioID,
+	}
+}
+
+func (valoracion Valoracion) GetModel() model.Valoracion {
+	return model.Valoracion{
+		RecetaID:   utils.GetObjectIDFromStringID(valoracion.RecetaID),
+		Puntuacion: valoracion.Puntuacion,
+		Favorita:   valoracion.Favorita,
+		UsuarioID:  valoracion.UsuarioID,
+	}
+}
+
+func (valoracion Valoracion) Validate() error {
+	if valoracion.Puntuacion < 0 || valoracion.Puntuacion > 5 {
+		return errors.New("la puntuación debe estar entre 1 y 5 (0 para quitarla)")
+	}
+	return nil
+}
+
+func NewNota(nota model.Nota) *Nota {
+	return &Nota{
+		Id:            utils.GetStringIDFromObjectID(nota.Id),
+		Texto:         nota.Texto,
+		FechaCoccion:  nota.FechaCoccion,
+		FechaCreacion: nota.FechaCreacion,
+	}
+}
+
+func (nota Nota) GetModel() model.Nota {
+	return model.Nota{
+		Id:           utils.GetObjectIDFromStringID(nota.Id),
+		Texto:        strings.TrimSpace(nota.Texto),
+		FechaCoccion: nota.FechaCoccion,
+	}
+}
+
+func (nota Nota) Validate() error {
+	if strings.TrimSpace(nota.Texto) == "" {
+		return errors.New("el texto de la nota no puede estar vacío")
+	}
+	if nota.FechaCoccion != nil && nota.FechaCoccion.After(time.Now()) {
+		return errors.New("la fecha de cocción no puede ser futura")
+	}
+	return nil
+}
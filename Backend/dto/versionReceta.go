@@ -0,0 +1,65 @@
+package dto
+
+import (
+	"errors"
+	"gocooking-backend/model"
+	"time"
+)
+
+type VersionReceta struct {
+	Numero        int       `json:"numero"`
+	Receta        *Receta   `json:"receta"`
+	FechaCreacion time.Time `json:"fecha_creacion"`
+}
+
+func NewVersionReceta(version model.VersionReceta) *VersionReceta {
+	return &VersionReceta{
+		Numero:        version.Numero,
+		Receta:        NewReceta(version.Receta),
+		FechaCreacion: version.FechaCreacion,
+	}
+}
+
+// DiffReceta describe los cambios entre dos versiones de una receta
+type DiffReceta struct {
+	Desde                   int                 `json:"desde"`
+	Hasta                   int                 `json:"hasta"`
+	Campos                  []CambioCampo       `json:"campos"`
+	IngredientesAgregados   []Ingrediente       `json:"ingredientes_agregados"`
+	IngredientesQuitados    []Ingrediente       `json:"ingredientes_quitados"`
+	IngredientesModificados []CambioIngrediente `json:"ingredientes_modificados"`
+}
+
+type CambioCampo struct {
+	Campo    string      `json:"campo"`
+	Anterior interface{} `json:"anterior"`
+	Nuevo    interface{} `json:"nuevo"`
+}
+
+type CambioIngrediente struct {
+	AlimentoId       string  `json:"alimento_id"`
+	RecetaId         string  `json:"receta_id,omitempty"`
+	Nombre           string  `json:"nombre"`
+	CantidadAnterior float64 `json:"cantidad_anterior"`
+	CantidadNueva    float64 `json:"cantidad_nueva"`
+	UnidadAnterior   string  `json:"unidad_anterior,omitempty"`
+	UnidadNueva      string  `json:"unidad_nueva,omitempty"`
+	OpcionalAnterior bool    `json:"opcional_anterior"`
+	OpcionalNuevo    bool    `json:"opcional_nuevo"`
+	AGustoAnterior   bool    `json:"a_gusto_anterior"`
+	AGustoNuevo      bool    `json:"a_gusto_nuevo"`
+}
+
+// ParametrosDiffVersiones indica las versiones a comparar. Sin hasta se usa la última versión
+// y sin desde la anterior a hasta.
+type ParametrosDiffVersiones struct {
+	Desde int `form:"desde"`
+	Hasta int `form:"hasta"`
+}
+
+func (parametros ParametrosDiffVersiones) Validate() error {
+	if parametros.Desde < 0 || parametros.Hasta < 0 {
+		return errors.New("los números de versión no pueden ser negativos")
+	}
+	return nil
+}
@@ -0,0 +1,288 @@
+package formatos
+
+import (
+	"gocooking-backend/model"
+	"gocooking-backend/utils"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// RecetaCooklang es el contenido de un archivo .cook ya interpretado
+type RecetaCooklang struct {
+	Metadatos    map[string]string
+	Ingredientes []IngredienteCooklang
+	Pasos        []string
+	Utensilios   []string
+}
+
+type IngredienteCooklang struct {
+	Nombre   string
+	Cantidad float64
+	Unidad   string
+	Opcional bool // Marcado como @?ingrediente{}
+}
+
+var (
+	expresionComentarioBloque = regexp.MustCompile(`(?s)\[-.*?-\]`)
+	expresionParrafos         = regexp.MustCompile(`\n\s*\n`)
+)
+
+// ParsearCooklang interpreta un archivo en formato Cooklang (https://cooklang.org/docs/spec/). Cada mención
+// de un ingrediente es un ingrediente aparte, aunque se repita el nombre, como en la receta exportada.
+func ParsearCooklang(texto string) *RecetaCooklang {
+	receta := &RecetaCooklang{
+		Metadatos:    make(map[string]string),
+		Ingredientes: []IngredienteCooklang{},
+		Pasos:        []string{},
+		Utensilios:   []string{},
+	}
+
+	texto = strings.ReplaceAll(texto, "\r\n", "\n")
+	texto = expresionComentarioBloque.ReplaceAllString(texto, "")
+
+	// Separar los metadatos y quitar los comentarios de línea
+	var lineas []string
+	for _, linea := range strings.Split(texto, "\n") {
+		if indice := strings.Index(linea, "--"); indice >= 0 {
+			linea = linea[:indice]
+		}
+		if metadato, esMetadato := strings.CutPrefix(strings.TrimSpace(linea), ">>"); esMetadato {
+			if clave, valor, existe := strings.Cut(metadato, ":"); existe {
+				receta.Metadatos[strings.ToLower(strings.TrimSpace(clave))] = strings.TrimSpace(valor)
+			}
+			continue
+		}
+		lineas = append(lineas, linea)
+	}
+
+	for _, parrafo := range expresionParrafos.Split(strings.Join(lineas, "\n"), -1) {
+		parrafo = strings.Join(strings.Fields(parrafo), " ")
+		if parrafo == "" {
+			continue
+		}
+		receta.Pasos = append(receta.Pasos, receta.parsearPaso(parrafo))
+	}
+	return receta
+}
+
+// parsearPaso reemplaza las referencias @ingrediente, #utensilio y ~temporizador por su texto y registra los ingredientes
+func (receta *RecetaCooklang) parsearPaso(parrafo string) string {
+	var paso strings.Builder
+	runas := []rune(parrafo)
+	for i := 0; i < len(runas); i++ {
+		marca := runas[i]
+		if (marca != '@' && marca != '#' && marca != '~') || i+1 >= len(runas) {
+			paso.WriteRune(marca)
+			continue
+		}
+
+		// Un ingrediente opcional se marca con @?, como en las extensiones de Cooklang
+		inicio := i + 1
+		opcional := marca == '@' && runas[inicio] == '?'
+		if opcional {
+			inicio++
+		}
+		nombre, contenido, fin, valida := leerReferencia(runas, inicio)
+		if !valida {
+			paso.WriteRune(marca)
+			continue
+		}
+		i = fin
+
+		cantidad, unidad := parsearCantidadCooklang(contenido)
+		switch marca {
+		case '@':
+			paso.WriteString(nombre)
+			receta.Ingredientes = append(receta.Ingredientes, IngredienteCooklang{Nombre: nombre, Cantidad: cantidad, Unidad: unidad, Opcional: opcional})
+		case '#':
+			paso.WriteString(nombre)
+			receta.Utensilios = append(receta.Utensilios, nombre)
+		case '~':
+			// Los temporizadores se muestran con su duración
+			texto := strings.TrimSpace(strings.Replace(contenido, "%", " ", 1))
+			if texto == "" {
+				texto = nombre
+			}
+			paso.WriteString(texto)
+		}
+	}
+	return strings.TrimSpace(paso.String())
+}
+
+// leerReferencia lee el nombre y el contenido entre llaves de una referencia que empieza en la posición inicio.
+// Los nombres de varias palabras deben terminar en {}, los de una palabra pueden no llevar llaves.
+func leerReferencia(runas []rune, inicio int) (string, string, int, bool) {
+	for j := inicio; j < len(runas); j++ {
+		if runas[j] == '{' {
+			cierre := indiceRuna(runas, j, '}')
+			if cierre < 0 {
+				break
+			}
+			return strings.TrimSpace(string(runas[inicio:j])), string(runas[j+1 : cierre]), cierre, true
+		}
+		if strings.ContainsRune("@#~}.,;:!?()", runas[j]) {
+			break
+		}
+	}
+
+	fin := inicio
+	for fin < len(runas) && (unicode.IsLetter(runas[fin]) || unicode.IsDigit(runas[fin]) || runas[fin] == '_' || runas[fin] == '-') {
+		fin++
+	}
+	if fin == inicio {
+		return "", "", 0, false
+	}
+	return string(runas[inicio:fin]), "", fin - 1, true
+}
+
+func indiceRuna(runas []rune, desde int, buscada rune) int {
+	for j := desde; j < len(runas); j++ {
+		if runas[j] == buscada {
+			return j
+		}
+	}
+	return -1
+}
+
+// parsearCantidadCooklang interpreta "200%g", "1/2%taza" o "3"
+func parsearCantidadCooklang(contenido string) (float64, string) {
+	texto, unidad, _ := strings.Cut(contenido, "%")
+	texto = strings.TrimSpace(texto)
+	unidad = strings.TrimSpace(unidad)
+	if texto == "" {
+		return 0, unidad
+	}
+	linea := ParsearLineaIngrediente(texto)
+	return linea.Cantidad, unidad
+}
+
+// GenerarCooklang exporta la receta en formato Cooklang, marcando cada ingrediente en el primer paso que lo menciona.
+// Los ingredientes y el equipamiento que ningún paso menciona se referencian en un primer paso que los prepara.
+func GenerarCooklang(imprimible RecetaImprimible) string {
+	receta := imprimible.Receta
+	var salida strings.Builder
+	salida.WriteString(">> title: " + receta.Nombre + "\n")
+	if receta.Porciones > 0 {
+		salida.WriteString(">> servings: " + strconv.Itoa(receta.Porciones) + "\n")
+	}
+	if len(imprimible.Momentos) > 0 {
+		salida.WriteString(">> momento: " + strings.Join(imprimible.Momentos, ", ") + "\n")
+	}
+	if len(receta.Etiquetas) > 0 {
+		salida.WriteString(">> tags: " + strings.Join(receta.Etiquetas, ", ") + "\n")
+	}
+	if receta.TiempoPreparacion > 0 {
+		salida.WriteString(">> prep time: " + strconv.Itoa(receta.TiempoPreparacion) + " minutes\n")
+	}
+	if receta.TiempoCoccion > 0 {
+		salida.WriteString(">> cook time: " + strconv.Itoa(receta.TiempoCoccion) + " minutes\n")
+	}
+
+	referenciado := make([]bool, len(receta.Ingredientes))
+	equipoReferenciado := make([]bool, len(receta.Equipamiento))
+	pasos := make([]string, len(receta.Pasos))
+	for i, paso := range receta.Pasos {
+		pasos[i] = marcarIngredientes(paso, receta.Ingredientes, referenciado)
+		pasos[i] = marcarEquipamiento(pasos[i], receta.Equipamiento, equipoReferenciado)
+	}
+
+	var sueltos []string
+	for i, ingrediente := range receta.Ingredientes {
+		if !referenciado[i] {
+			sueltos = append(sueltos, referenciaCooklang(ingrediente))
+		}
+	}
+	for i, equipo := range receta.Equipamiento {
+		if !equipoReferenciado[i] {
+			sueltos = append(sueltos, "#"+equipo.Nombre()+"{}")
+		}
+	}
+	if len(sueltos) > 0 {
+		salida.WriteString("\nPreparar " + enumerar(sueltos) + ".\n")
+	}
+	for _, paso := range pasos {
+		salida.WriteString("\n" + paso + "\n")
+	}
+	return salida.String()
+}
+
+// marcarIngredientes reemplaza en el paso la primera mención de cada ingrediente aún no referenciado
+func marcarIngredientes(paso string, ingredientes []model.Ingrediente, referenciado []bool) string {
+	for i, ingrediente := range ingredientes {
+		if referenciado[i] || strings.TrimSpace(ingrediente.Nombre) == "" {
+			continue
+		}
+		expresion := regexp.MustCompile(`(?i)(^|[^\p{L}\p{N}@#~{?])(` + regexp.QuoteMeta(ingrediente.Nombre) + `)($|[^\p{L}\p{N}{])`)
+		posicion := expresion.FindStringSubmatchIndex(paso)
+		if posicion == nil {
+			continue
+		}
+		// Se usa el texto tal como aparece en el paso para que al importarlo el paso quede igual
+		mencionado := ingrediente
+		mencionado.Nombre = paso[posicion[4]:posicion[5]]
+		paso = paso[:posicion[4]] + referenciaCooklang(mencionado) + paso[posicion[5]:]
+		referenciado[i] = true
+	}
+	return paso
+}
+
+// marcarEquipamiento reemplaza en el paso la primera mención de cada equipo aún no referenciado por un utensilio #equipo{}
+func marcarEquipamiento(paso string, equipamiento []utils.Equipo, referenciado []bool) string {
+	for i, equipo := range equipamiento {
+		if referenciado[i] {
+			continue
+		}
+		expresion := regexp.MustCompile(`(?i)(^|[^\p{L}\p{N}@#~{])(` + regexp.QuoteMeta(equipo.Nombre()) + `)($|[^\p{L}\p{N}{])`)
+		posicion := expresion.FindStringSubmatchIndex(paso)
+		if posicion == nil {
+			continue
+		}
+		paso = paso[:posicion[4]] + "#" + paso[posicion[4]:posicion[5]] + "{}" + paso[posicion[5]:]
+		referenciado[i] = true
+	}
+	return paso
+}
+
+// enumerar une los elementos como "a, b y c"
+func enumerar(elementos []string) string {
+	if len(elementos) == 1 {
+		return elementos[0]
+	}
+	return strings.Join(elementos[:len(elementos)-1], ", ") + " y " + elementos[len(elementos)-1]
+}
+
+func referenciaCooklang(ingrediente model.Ingrediente) string {
+	contenido := ""
+	if ingrediente.Cantidad > 0 {
+		contenido = strconv.FormatFloat(ingrediente.Cantidad, 'f', -1, 64)
+		if ingrediente.Unidad != "" {
+			contenido += "%" + ingrediente.Unidad
+		}
+	}
+	marca := "@"
+	if ingrediente.Opcional {
+		marca = "@?"
+	}
+	return marca + ingrediente.Nombre + "{" + contenido + "}"
+}
+
+var expresionTiempo = regexp.MustCompile(`(\d+(?:[.,]\d+)?)\s*(h|hs|hora|horas|hour|hours|m|min|mins|minuto|minutos|minute|minutes)?\b`)
+
+// ParsearMinutos interpreta tiempos escritos como "1 hora 20 minutos", "45 min", "1h" o en formato ISO 8601
+func ParsearMinutos(texto string) int {
+	texto = strings.ToLower(strings.TrimSpace(texto))
+	if strings.HasPrefix(texto, "p") {
+		return ParsearDuracion(texto)
+	}
+	total := 0.0
+	for _, partes := range expresionTiempo.FindAllStringSubmatch(texto, -1) {
+		valor, _ := strconv.ParseFloat(strings.Replace(partes[1], ",", ".", 1), 64)
+		if strings.HasPrefix(partes[2], "h") {
+			valor *= 60
+		}
+		total += valor
+	}
+	return int(total)
+}
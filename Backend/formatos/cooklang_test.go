@@ -0,0 +1,88 @@
+package formatos
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsearCooklang(t *testing.T) {
+	casos := []struct {
+		nombre   string
+		texto    string
+		esperada RecetaCooklang
+	}{
+		{
+			nombre: "metadatos, cantidades y unidades",
+			texto: ">> title: Panqueques\n>> Servings: 4\n\n" +
+				"Mezclar @harina{200%g} con @leche{1/2%taza} y @huevos{2}.\n\nAgregar @sal{} y @azúcar impalpable{1%cucharada}.",
+			esperada: RecetaCooklang{
+				Metadatos: map[string]string{"title": "Panqueques", "servings": "4"},
+				Ingredientes: []IngredienteCooklang{
+					{Nombre: "harina", Cantidad: 200, Unidad: "g"},
+					{Nombre: "leche", Cantidad: 0.5, Unidad: "taza"},
+					{Nombre: "huevos", Cantidad: 2},
+					{Nombre: "sal"},
+					{Nombre: "azúcar impalpable", Cantidad: 1, Unidad: "cucharada"},
+				},
+				Pasos:      []string{"Mezclar harina con leche y huevos.", "Agregar sal y azúcar impalpable."},
+				Utensilios: []string{},
+			},
+		},
+		{
+			nombre: "comentarios, utensilios y temporizadores",
+			texto: "-- Receta de la abuela\nPoner la @manteca{50%g} en el #horno{} [- bien caliente -]\n" +
+				"durante ~{20%minutos}.\n\nServir en un #plato hondo{}.",
+			esperada: RecetaCooklang{
+				Metadatos:    map[string]string{},
+				Ingredientes: []IngredienteCooklang{{Nombre: "manteca", Cantidad: 50, Unidad: "g"}},
+				Pasos:        []string{"Poner la manteca en el horno durante 20 minutos.", "Servir en un plato hondo."},
+				Utensilios:   []string{"horno", "plato hondo"},
+			},
+		},
+		{
+			nombre: "cada mención de un ingrediente es un ingrediente aparte",
+			texto:  "Agregar @azúcar{100%g}.\n\nEspolvorear @Azúcar{20%g} y @azúcar{1%taza}.",
+			esperada: RecetaCooklang{
+				Metadatos: map[string]string{},
+				Ingredientes: []IngredienteCooklang{
+					{Nombre: "azúcar", Cantidad: 100, Unidad: "g"},
+					{Nombre: "Azúcar", Cantidad: 20, Unidad: "g"},
+					{Nombre: "azúcar", Cantidad: 1, Unidad: "taza"},
+				},
+				Pasos:      []string{"Agregar azúcar.", "Espolvorear Azúcar y azúcar."},
+				Utensilios: []string{},
+			},
+		},
+		{
+			nombre: "ingredientes opcionales",
+			texto:  "Preparar @sal{}, @?perejil{1%cucharada} y #procesadora{}.\n\nProcesar todo.",
+			esperada: RecetaCooklang{
+				Metadatos: map[string]string{},
+				Ingredientes: []IngredienteCooklang{
+					{Nombre: "sal"},
+					{Nombre: "perejil", Cantidad: 1, Unidad: "cucharada", Opcional: true},
+				},
+				Pasos:      []string{"Preparar sal, perejil y procesadora.", "Procesar todo."},
+				Utensilios: []string{"procesadora"},
+			},
+		},
+		{
+			nombre: "las marcas sin nombre quedan como texto",
+			texto:  "Cocinar a 180 # o más @ fuego medio.",
+			esperada: RecetaCooklang{
+				Metadatos:    map[string]string{},
+				Ingredientes: []IngredienteCooklang{},
+				Pasos:        []string{"Cocinar a 180 # o más @ fuego medio."},
+				Utensilios:   []string{},
+			},
+		},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nombre, func(t *testing.T) {
+			if receta := ParsearCooklang(caso.texto); !reflect.DeepEqual(*receta, caso.esperada) {
+				t.Errorf("ParsearCooklang() = %+v, se esperaba %+v", *receta, caso.esperada)
+			}
+		})
+	}
+}
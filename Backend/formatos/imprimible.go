@@ -0,0 +1,186 @@
+package formatos
+
+import (
+	"encoding/json"
+	"gocooking-backend/model"
+	"strconv"
+	"strings"
+)
+
+// RecetaImprimible es una receta con los datos calculados que se muestran al exportarla
+type RecetaImprimible struct {
+	Receta   model.Receta
+	Momentos []string           // Nombres de los momentos de consumo, incluidos los personalizados del dueño
+	Costo    *model.CostoReceta // Según el precio de los alimentos del dueño, nil si no se calculó
+}
+
+// TextoIngrediente escribe el ingrediente como "200 g de harina", "2 huevo" o "sal a gusto (opcional)",
+// que es como se vuelve a importar
+func TextoIngrediente(ingrediente model.Ingrediente) string {
+	texto := ingrediente.Nombre
+	if ingrediente.Cantidad > 0 {
+		cantidad := strconv.FormatFloat(ingrediente.Cantidad, 'f', -1, 64)
+		if ingrediente.Unidad != "" {
+			texto = cantidad + " " + ingrediente.Unidad + " de " + ingrediente.Nombre
+		} else {
+			texto = cantidad + " " + ingrediente.Nombre
+		}
+	}
+	if ingrediente.AGusto {
+		texto += " a gusto"
+	}
+	if ingrediente.Opcional {
+		texto += " (opcional)"
+	}
+	return texto
+}
+
+// datosGenerales arma las líneas de momento, porciones, tiempos, etiquetas, equipamiento y costo que tengan valor
+func datosGenerales(receta RecetaImprimible) []string {
+	var datos []string
+	if len(receta.Momentos) > 0 {
+		datos = append(datos, "Momento: "+strings.Join(receta.Momentos, ", "))
+	}
+	if receta.Receta.Porciones > 0 {
+		datos = append(datos, "Porciones: "+strconv.Itoa(receta.Receta.Porciones))
+	}
+	if receta.Receta.TiempoPreparacion > 0 {
+		datos = append(datos, "Preparación: "+strconv.Itoa(receta.Receta.TiempoPreparacion)+" min")
+	}
+	if receta.Receta.TiempoCoccion > 0 {
+		datos = append(datos, "Cocción: "+strconv.Itoa(receta.Receta.TiempoCoccion)+" min")
+	}
+	if len(receta.Receta.Etiquetas) > 0 {
+		datos = append(datos, "Etiquetas: "+strings.Join(receta.Receta.Etiquetas, ", "))
+	}
+	if len(receta.Receta.Equipamiento) > 0 {
+		var equipos []string
+		for _, equipo := range receta.Receta.Equipamiento {
+			equipos = append(equipos, equipo.Nombre())
+		}
+		datos = append(datos, "Equipamiento: "+strings.Join(equipos, ", "))
+	}
+	if receta.Costo != nil && receta.Costo.Total > 0 {
+		costo := "Costo estimado: $" + strconv.FormatFloat(receta.Costo.Total, 'f', 2, 64)
+		if receta.Receta.Porciones > 1 {
+			costo += " ($" + strconv.FormatFloat(receta.Costo.PorPorcion, 'f', 2, 64) + " por porción)"
+		}
+		if len(receta.Costo.IngredientesSinPrecio) > 0 {
+			costo += ", sin contar " + strings.Join(receta.Costo.IngredientesSinPrecio, ", ")
+		}
+		datos = append(datos, costo)
+	}
+	return datos
+}
+
+// GenerarMarkdown exporta la receta como documento Markdown
+func GenerarMarkdown(receta RecetaImprimible) string {
+	var salida strings.Builder
+	salida.WriteString("# " + receta.Receta.Nombre + "\n\n")
+	for _, dato := range datosGenerales(receta) {
+		salida.WriteString("- " + dato + "\n")
+	}
+
+	salida.WriteString("\n## Ingredientes\n\n")
+	for _, ingrediente := range receta.Receta.Ingredientes {
+		salida.WriteString("- " + TextoIngrediente(ingrediente) + "\n")
+	}
+
+	if len(receta.Receta.Pasos) > 0 {
+		salida.WriteString("\n## Preparación\n\n")
+		for i, paso := range receta.Receta.Pasos {
+			salida.WriteString(strconv.Itoa(i+1) + ". " + paso + "\n")
+		}
+	}
+	return salida.String()
+}
+
+// GenerarJSONLD exporta la receta como objeto schema.org/Recipe
+func GenerarJSONLD(receta RecetaImprimible) ([]byte, error) {
+	datos := map[string]interface{}{
+		"@context": "https://schema.org",
+		"@type":    "Recipe",
+		"name":     receta.Receta.Nombre,
+	}
+	if len(receta.Momentos) > 0 {
+		datos["recipeCategory"] = strings.Join(receta.Momentos, ", ")
+	}
+
+	ingredientes := []string{}
+	for _, ingrediente := range receta.Receta.Ingredientes {
+		ingredientes = append(ingredientes, TextoIngrediente(ingrediente))
+	}
+	datos["recipeIngredient"] = ingredientes
+
+	pasos := []map[string]string{}
+	for _, paso := range receta.Receta.Pasos {
+		pasos = append(pasos, map[string]string{"@type": "HowToStep", "text": paso})
+	}
+	datos["recipeInstructions"] = pasos
+
+	if receta.Receta.Porciones > 0 {
+		datos["recipeYield"] = strconv.Itoa(receta.Receta.Porciones)
+	}
+	if receta.Receta.TiempoPreparacion > 0 {
+		datos["prepTime"] = FormatearDuracion(receta.Receta.TiempoPreparacion)
+	}
+	if receta.Receta.TiempoCoccion > 0 {
+		datos["cookTime"] = FormatearDuracion(receta.Receta.TiempoCoccion)
+	}
+	if total := receta.Receta.TiempoPreparacion + receta.Receta.TiempoCoccion; total > 0 {
+		datos["totalTime"] = FormatearDuracion(total)
+	}
+	if len(receta.Receta.Etiquetas) > 0 {
+		datos["keywords"] = strings.Join(receta.Receta.Etiquetas, ", ")
+	}
+	// Con ingredientes sin precio el total sería menor al real
+	if receta.Costo != nil && receta.Costo.Total > 0 && len(receta.Costo.IngredientesSinPrecio) == 0 {
+		datos["estimatedCost"] = map[string]interface{}{"@type": "MonetaryAmount", "value": receta.Costo.Total}
+	}
+	return json.MarshalIndent(datos, "", "  ")
+}
+
+// GenerarPDF exporta una receta en un PDF
+func GenerarPDF(receta RecetaImprimible) []byte {
+	documento := NewDocumentoPDF()
+	escribirRecetaPDF(documento, receta)
+	return documento.Bytes()
+}
+
+// GenerarRecetarioPDF exporta varias recetas en un único PDF, con una portada con el índice y cada receta en su página
+func GenerarRecetarioPDF(titulo string, descripcion string, recetas []RecetaImprimible) []byte {
+	documento := NewDocumentoPDF()
+	documento.Titulo(titulo)
+	if descripcion != "" {
+		documento.Parrafo(descripcion)
+	}
+	documento.Subtitulo("Índice")
+	for i, receta := range recetas {
+		documento.Item(strconv.Itoa(i+1)+".", receta.Receta.Nombre)
+	}
+
+	for _, receta := range recetas {
+		documento.NuevaPagina()
+		escribirRecetaPDF(documento, receta)
+	}
+	return documento.Bytes()
+}
+
+func escribirRecetaPDF(documento *DocumentoPDF, receta RecetaImprimible) {
+	documento.Titulo(receta.Receta.Nombre)
+	for _, dato := range datosGenerales(receta) {
+		documento.Parrafo(dato)
+	}
+
+	documento.Subtitulo("Ingredientes")
+	for _, ingrediente := range receta.Receta.Ingredientes {
+		documento.Item("•", TextoIngrediente(ingrediente))
+	}
+
+	if len(receta.Receta.Pasos) > 0 {
+		documento.Subtitulo("Preparación")
+		for i, paso := range receta.Receta.Pasos {
+			documento.Item(strconv.Itoa(i+1)+".", paso)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package formatos
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// LineaIngrediente es el resultado de interpretar una línea de texto libre como "1 1/2 tazas de harina"
+type LineaIngrediente struct {
+	Texto    string
+	Cantidad float64
+	Unidad   string
+	Nombre   string
+	AGusto   bool // "sal a gusto", "pimienta c/n"
+	Opcional bool // "perejil (opcional)"
+}
+
+var fraccionesUnicode = map[string]string{
+	"½": " 1/2", "⅓": " 1/3", "⅔": " 2/3", "¼": " 1/4", "¾": " 3/4", "⅛": " 1/8",
+}
+
+// Unidades reconocidas y la forma en que se guardan
+var unidades = map[string]string{
+	"g": "g", "gr": "g", "grs": "g", "gramo": "g", "gramos": "g", "gram": "g", "grams": "g",
+	"kg": "kg", "kilo": "kg", "kilos": "kg", "kilogramo": "kg", "kilogramos": "kg",
+	"mg": "mg",
+	"ml": "ml", "mililitro": "ml", "mililitros": "ml", "cc": "ml",
+	"l": "l", "lt": "l", "lts": "l", "litro": "l", "litros": "l", "liter": "l", "liters": "l",
+	"taza": "taza", "tazas": "taza", "cup": "taza", "cups": "taza",
+	"cucharada": "cucharada", "cucharadas": "cucharada", "cda": "cucharada", "cdas": "cucharada", "tbsp": "cucharada", "tablespoon": "cucharada", "tablespoons": "cucharada",
+	"cucharadita": "cucharadita", "cucharaditas": "cucharadita", "cdita": "cucharadita", "cditas": "cucharadita", "cdta": "cucharadita", "tsp": "cucharadita", "teaspoon": "cucharadita", "teaspoons": "cucharadita",
+	"unidad": "unidad", "unidades": "unidad", "u": "unidad",
+	"diente": "diente", "dientes": "diente", "clove": "diente", "cloves": "diente",
+	"pizca": "pizca", "pizcas": "pizca", "pinch": "pizca",
+	"lata": "lata", "latas": "lata", "can": "lata", "cans": "lata",
+	"paquete": "paquete", "paquetes": "paquete", "package": "paquete",
+	"rodaja": "rodaja", "rodajas": "rodaja", "slice": "rodaja", "slices": "rodaja",
+	"oz": "oz", "ounce": "oz", "ounces": "oz",
+	"lb": "lb", "lbs": "lb", "pound": "lb", "pounds": "lb",
+}
+
+var (
+	expresionCantidad   = regexp.MustCompile(`^(\d+(?:[.,]\d+)?)(?:\s+(\d+)/(\d+)|/(\d+))?(?:\s*(?:-|a)\s*\d+(?:[.,]\d+)?(?:/\d+)?)?`)
+	expresionParentesis = regexp.MustCompile(`\([^)]*\)`)
+	expresionAGusto     = regexp.MustCompile(`(?i),?\s*\b(a gusto|c/n|cantidad necesaria|to taste)\b`)
+	expresionOpcional   = regexp.MustCompile(`(?i),?\s*\(?\b(opcional|optional)\b\)?`)
+)
+
+// ParsearLineaIngrediente separa cantidad, unidad y nombre de una línea de ingrediente
+func ParsearLineaIngrediente(texto string) LineaIngrediente {
+	linea := LineaIngrediente{Texto: strings.TrimSpace(texto)}
+
+	resto := linea.Texto
+	for fraccion, reemplazo := range fraccionesUnicode {
+		resto = strings.ReplaceAll(resto, fraccion, reemplazo)
+	}
+	// "a gusto" y "opcional" se marcan y se quitan antes de que los paréntesis o la coma los descarten
+	if expresionOpcional.MatchString(resto) {
+		linea.Opcional = true
+		resto = expresionOpcional.ReplaceAllString(resto, "")
+	}
+	if expresionAGusto.MatchString(resto) {
+		linea.AGusto = true
+		resto = expresionAGusto.ReplaceAllString(resto, "")
+	}
+	resto = strings.TrimSpace(expresionParentesis.ReplaceAllString(resto, ""))
+
+	// Cantidad: "2", "1,5", "1/2", "1 1/2" o rangos como "2-3" (se toma el primer valor)
+	if partes := expresionCantidad.FindStringSubmatch(resto); partes != nil {
+		cantidad, _ := strconv.ParseFloat(strings.Replace(partes[1], ",", ".", 1), 64)
+		switch {
+		case partes[2] != "":
+			cantidad += dividir(partes[2], partes[3])
+		case partes[4] != "":
+			cantidad = dividir(partes[1], partes[4])
+		}
+		linea.Cantidad = cantidad
+		resto = strings.TrimSpace(resto[len(partes[0]):])
+	}
+
+	// Unidad: solo se reconoce si viene a continuación de la cantidad o al principio de la línea
+	if palabras := strings.Fields(resto); len(palabras) > 1 {
+		candidata := strings.TrimSuffix(strings.ToLower(palabras[0]), ".")
+		if unidad, existe := unidades[candidata]; existe {
+			linea.Unidad = unidad
+			resto = strings.TrimSpace(strings.TrimPrefix(resto, palabras[0]))
+		}
+	}
+
+	// Nombre: se quitan el "de" inicial y las aclaraciones después de una coma ("cebolla, picada")
+	resto = strings.TrimPrefix(resto, "de ")
+	resto = strings.TrimPrefix(resto, "of ")
+	if coma := strings.Index(resto, ","); coma > 0 {
+		resto = resto[:coma]
+	}
+	linea.Nombre = strings.TrimSpace(resto)
+	return linea
+}
+
+func dividir(numerador, denominador string) float64 {
+	n, _ := strconv.ParseFloat(numerador, 64)
+	d, _ := strconv.ParseFloat(denominador, 64)
+	if d == 0 {
+		return 0
+	}
+	return n / d
+}
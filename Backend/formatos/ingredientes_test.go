@@ -0,0 +1,35 @@
+package formatos
+
+import "testing"
+
+func TestParsearLineaIngrediente(t *testing.T) {
+	casos := []struct {
+		texto    string
+		esperada LineaIngrediente
+	}{
+		{"200 g de harina", LineaIngrediente{Cantidad: 200, Unidad: "g", Nombre: "harina"}},
+		{"1 1/2 tazas de leche", LineaIngrediente{Cantidad: 1.5, Unidad: "taza", Nombre: "leche"}},
+		{"½ cucharadita de sal", LineaIngrediente{Cantidad: 0.5, Unidad: "cucharadita", Nombre: "sal"}},
+		{"1/4 kg de azúcar", LineaIngrediente{Cantidad: 0.25, Unidad: "kg", Nombre: "azúcar"}},
+		{"1,5 litros de caldo", LineaIngrediente{Cantidad: 1.5, Unidad: "l", Nombre: "caldo"}},
+		{"2-3 dientes de ajo", LineaIngrediente{Cantidad: 2, Unidad: "diente", Nombre: "ajo"}},
+		{"3 huevos", LineaIngrediente{Cantidad: 3, Nombre: "huevos"}},
+		{"1 cebolla, picada", LineaIngrediente{Cantidad: 1, Nombre: "cebolla"}},
+		{"2 cups flour", LineaIngrediente{Cantidad: 2, Unidad: "taza", Nombre: "flour"}},
+		{"sal a gusto", LineaIngrediente{Nombre: "sal", AGusto: true}},
+		{"pimienta c/n", LineaIngrediente{Nombre: "pimienta", AGusto: true}},
+		{"perejil (opcional)", LineaIngrediente{Nombre: "perejil", Opcional: true}},
+		{"50 g de nueces (picadas)", LineaIngrediente{Cantidad: 50, Unidad: "g", Nombre: "nueces"}},
+		{"azúcar", LineaIngrediente{Nombre: "azúcar"}},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.texto, func(t *testing.T) {
+			esperada := caso.esperada
+			esperada.Texto = caso.texto
+			if linea := ParsearLineaIngrediente(caso.texto); linea != esperada {
+				t.Errorf("ParsearLineaIngrediente(%q) = %+v, se esperaba %+v", caso.texto, linea, esperada)
+			}
+		})
+	}
+}
@@ -0,0 +1,226 @@
+package formatos
+
+import (
+	"encoding/json"
+	"errors"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// RecetaJSONLD reúne los datos de un objeto schema.org/Recipe que se usan para armar una receta
+type RecetaJSONLD struct {
+	Nombre            string
+	Categoria         string
+	Ingredientes      []string
+	Pasos             []string
+	Etiquetas         []string
+	TiempoPreparacion int // En minutos
+	TiempoCoccion     int // En minutos
+	Porciones         int
+}
+
+var (
+	expresionScriptJSONLD = regexp.MustCompile(`(?is)<script[^>]*type\s*=\s*["']application/ld\+json["'][^>]*>(.*?)</script>`)
+	expresionDuracion     = regexp.MustCompile(`^P(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+(?:\.\d+)?)S)?)?$`)
+	expresionEntero       = regexp.MustCompile(`\d+`)
+	expresionEtiquetaHTML = regexp.MustCompile(`<[^>]*>`)
+	expresionPuntuacion   = regexp.MustCompile(`\s+([.,;:!?])`)
+)
+
+// ExtraerRecetaJSONLD busca una receta schema.org en un documento JSON-LD o en el HTML de una página
+func ExtraerRecetaJSONLD(contenido []byte) (*RecetaJSONLD, error) {
+	var documentos [][]byte
+	texto := strings.TrimSpace(string(contenido))
+	if strings.HasPrefix(texto, "{") || strings.HasPrefix(texto, "[") {
+		documentos = append(documentos, []byte(texto))
+	} else {
+		for _, script := range expresionScriptJSONLD.FindAllStringSubmatch(texto, -1) {
+			documentos = append(documentos, []byte(script[1]))
+		}
+		if len(documentos) == 0 {
+			return nil, errors.New("el documento no contiene datos JSON-LD")
+		}
+	}
+
+	for _, documento := range documentos {
+		var datos interface{}
+		if err := json.Unmarshal(documento, &datos); err != nil {
+			continue
+		}
+		if receta := buscarObjetoReceta(datos); receta != nil {
+			return convertirRecetaJSONLD(receta), nil
+		}
+	}
+	return nil, errors.New("no se encontró una receta schema.org en el documento")
+}
+
+// buscarObjetoReceta recorre el JSON (incluyendo @graph y listas) hasta encontrar un objeto de tipo Recipe
+func buscarObjetoReceta(datos interface{}) map[string]interface{} {
+	switch valor := datos.(type) {
+	case []interface{}:
+		for _, elemento := range valor {
+			if receta := buscarObjetoReceta(elemento); receta != nil {
+				return receta
+			}
+		}
+	case map[string]interface{}:
+		if esTipoReceta(valor["@type"]) {
+			return valor
+		}
+		for _, clave := range []string{"@graph", "mainEntity", "mainEntityOfPage"} {
+			if receta := buscarObjetoReceta(valor[clave]); receta != nil {
+				return receta
+			}
+		}
+	}
+	return nil
+}
+
+func esTipoReceta(tipo interface{}) bool {
+	for _, valor := range listaDeTextos(tipo) {
+		if valor == "Recipe" || strings.HasSuffix(valor, "/Recipe") {
+			return true
+		}
+	}
+	return false
+}
+
+func convertirRecetaJSONLD(datos map[string]interface{}) *RecetaJSONLD {
+	receta := &RecetaJSONLD{
+		Nombre:       limpiarTexto(primerTexto(datos["name"])),
+		Categoria:    limpiarTexto(primerTexto(datos["recipeCategory"])),
+		Ingredientes: []string{},
+		Pasos:        extraerPasos(datos["recipeInstructions"]),
+		Etiquetas:    []string{},
+		Porciones:    extraerPorciones(datos["recipeYield"]),
+	}
+
+	ingredientes := datos["recipeIngredient"]
+	if ingredientes == nil {
+		// Propiedad antigua de schema.org que todavía usan algunos sitios
+		ingredientes = datos["ingredients"]
+	}
+	for _, ingrediente := range listaDeTextos(ingredientes) {
+		if ingrediente = limpiarTexto(ingrediente); ingrediente != "" {
+			receta.Ingredientes = append(receta.Ingredientes, ingrediente)
+		}
+	}
+
+	for _, palabraClave := range listaDeTextos(datos["keywords"]) {
+		for _, etiqueta := range strings.Split(palabraClave, ",") {
+			if etiqueta = limpiarTexto(etiqueta); etiqueta != "" {
+				receta.Etiquetas = append(receta.Etiquetas, etiqueta)
+			}
+		}
+	}
+
+	receta.TiempoPreparacion = ParsearDuracion(primerTexto(datos["prepTime"]))
+	receta.TiempoCoccion = ParsearDuracion(primerTexto(datos["cookTime"]))
+	if receta.TiempoPreparacion == 0 && receta.TiempoCoccion == 0 {
+		receta.TiempoPreparacion = ParsearDuracion(primerTexto(datos["totalTime"]))
+	}
+	return receta
+}
+
+// extraerPasos admite instrucciones como texto, lista de textos, HowToStep o HowToSection
+func extraerPasos(instrucciones interface{}) []string {
+	pasos := []string{}
+	switch valor := instrucciones.(type) {
+	case string:
+		for _, linea := range strings.Split(html.UnescapeString(valor), "\n") {
+			if linea = limpiarTexto(linea); linea != "" {
+				pasos = append(pasos, linea)
+			}
+		}
+	case []interface{}:
+		for _, elemento := range valor {
+			pasos = append(pasos, extraerPasos(elemento)...)
+		}
+	case map[string]interface{}:
+		if elementos, existe := valor["itemListElement"]; existe {
+			return extraerPasos(elementos)
+		}
+		texto := primerTexto(valor["text"])
+		if texto == "" {
+			texto = primerTexto(valor["name"])
+		}
+		if texto = limpiarTexto(texto); texto != "" {
+			pasos = append(pasos, texto)
+		}
+	}
+	return pasos
+}
+
+func extraerPorciones(rendimiento interface{}) int {
+	if numero, esNumero := rendimiento.(float64); esNumero {
+		return int(numero)
+	}
+	for _, texto := range listaDeTextos(rendimiento) {
+		if numero := expresionEntero.FindString(texto); numero != "" {
+			porciones, _ := strconv.Atoi(numero)
+			return porciones
+		}
+	}
+	return 0
+}
+
+// ParsearPorciones toma el primer número de textos como "4 porciones"
+func ParsearPorciones(texto string) int {
+	return extraerPorciones(texto)
+}
+
+// ParsearDuracion convierte una duración ISO 8601 (PT1H30M) a minutos
+func ParsearDuracion(duracion string) int {
+	partes := expresionDuracion.FindStringSubmatch(strings.ToUpper(strings.TrimSpace(duracion)))
+	if partes == nil {
+		return 0
+	}
+	dias, _ := strconv.Atoi(partes[1])
+	horas, _ := strconv.Atoi(partes[2])
+	minutos, _ := strconv.Atoi(partes[3])
+	segundos, _ := strconv.ParseFloat(partes[4], 64)
+	return dias*24*60 + horas*60 + minutos + int(segundos/60)
+}
+
+// FormatearDuracion convierte minutos a una duración ISO 8601
+func FormatearDuracion(minutos int) string {
+	if minutos%60 == 0 {
+		return "PT" + strconv.Itoa(minutos/60) + "H"
+	}
+	if minutos > 60 {
+		return "PT" + strconv.Itoa(minutos/60) + "H" + strconv.Itoa(minutos%60) + "M"
+	}
+	return "PT" + strconv.Itoa(minutos) + "M"
+}
+
+func listaDeTextos(valor interface{}) []string {
+	switch v := valor.(type) {
+	case string:
+		return []string{v}
+	case float64:
+		return []string{strconv.FormatFloat(v, 'f', -1, 64)}
+	case []interface{}:
+		var textos []string
+		for _, elemento := range v {
+			textos = append(textos, listaDeTextos(elemento)...)
+		}
+		return textos
+	}
+	return nil
+}
+
+func primerTexto(valor interface{}) string {
+	textos := listaDeTextos(valor)
+	if len(textos) == 0 {
+		return ""
+	}
+	return textos[0]
+}
+
+// limpiarTexto decodifica entidades HTML, quita etiquetas y espacios sobrantes
+func limpiarTexto(texto string) string {
+	texto = expresionEtiquetaHTML.ReplaceAllString(html.UnescapeString(texto), " ")
+	return expresionPuntuacion.ReplaceAllString(strings.Join(strings.Fields(texto), " "), "$1")
+}
@@ -0,0 +1,223 @@
+package formatos
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Generador de PDF mínimo, sin dependencias externas: páginas A4 con texto en Helvetica
+// (las fuentes estándar de PDF no necesitan embeberse) y codificación WinAnsi para los acentos.
+
+const (
+	anchoPagina  = 595.0
+	altoPagina   = 842.0
+	margen       = 56.0
+	anchoTexto   = anchoPagina - 2*margen
+	sangriaLista = 14.0
+)
+
+const (
+	fuenteNormal  = "F1"
+	fuenteNegrita = "F2"
+)
+
+// DocumentoPDF acumula el contenido de las páginas a medida que se escribe
+type DocumentoPDF struct {
+	paginas []*bytes.Buffer
+	y       float64 // Posición vertical de la próxima línea, desde abajo
+}
+
+func NewDocumentoPDF() *DocumentoPDF {
+	documento := &DocumentoPDF{}
+	documento.NuevaPagina()
+	return documento
+}
+
+// NuevaPagina empieza una página en blanco, salvo que la actual todavía esté vacía
+func (documento *DocumentoPDF) NuevaPagina() {
+	if len(documento.paginas) > 0 && documento.paginaActual().Len() == 0 {
+		return
+	}
+	documento.paginas = append(documento.paginas, &bytes.Buffer{})
+	documento.y = altoPagina - margen
+}
+
+func (documento *DocumentoPDF) Titulo(texto string) {
+	documento.escribir(texto, fuenteNegrita, 18, 0)
+	documento.espacio(6)
+}
+
+func (documento *DocumentoPDF) Subtitulo(texto string) {
+	documento.espacio(8)
+	documento.escribir(texto, fuenteNegrita, 13, 0)
+	documento.espacio(2)
+}
+
+func (documento *DocumentoPDF) Parrafo(texto string) {
+	documento.escribir(texto, fuenteNormal, 11, 0)
+	documento.espacio(3)
+}
+
+// Item escribe un elemento de lista con la viñeta indicada ("•", "1.", etc.)
+func (documento *DocumentoPDF) Item(vineta string, texto string) {
+	documento.verificarEspacio(11 * 1.35)
+	documento.linea(vineta, fuenteNormal, 11, margen)
+	documento.escribir(texto, fuenteNormal, 11, sangriaLista+6)
+	documento.espacio(2)
+}
+
+// Bytes arma el archivo PDF completo
+func (documento *DocumentoPDF) Bytes() []byte {
+	var salida bytes.Buffer
+	var posiciones []int
+	objeto := func(contenido string) {
+		posiciones = append(posiciones, salida.Len())
+		fmt.Fprintf(&salida, "%d 0 obj\n%s\nendobj\n", len(posiciones), contenido)
+	}
+
+	salida.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// Objetos 1 a 4: catálogo, árbol de páginas y las dos fuentes; luego cada página con su contenido
+	var hijos []string
+	for i := range documento.paginas {
+		hijos = append(hijos, strconv.Itoa(5+2*i)+" 0 R")
+	}
+	objeto("<< /Type /Catalog /Pages 2 0 R >>")
+	objeto(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(hijos, " "), len(documento.paginas)))
+	objeto("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	objeto("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	for i, pagina := range documento.paginas {
+		objeto(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %g %g] /Resources << /Font << /%s 3 0 R /%s 4 0 R >> >> /Contents %d 0 R >>",
+			anchoPagina, altoPagina, fuenteNormal, fuenteNegrita, 6+2*i))
+		objeto(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", pagina.Len(), pagina.String()))
+	}
+
+	inicioXref := salida.Len()
+	fmt.Fprintf(&salida, "xref\n0 %d\n0000000000 65535 f \n", len(posiciones)+1)
+	for _, posicion := range posiciones {
+		fmt.Fprintf(&salida, "%010d 00000 n \n", posicion)
+	}
+	fmt.Fprintf(&salida, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(posiciones)+1, inicioXref)
+	return salida.Bytes()
+}
+
+func (documento *DocumentoPDF) paginaActual() *bytes.Buffer {
+	return documento.paginas[len(documento.paginas)-1]
+}
+
+// escribir divide el texto en líneas que entren en el ancho disponible, pasando de página cuando hace falta
+func (documento *DocumentoPDF) escribir(texto string, fuente string, tamano float64, sangria float64) {
+	for _, linea := range dividirEnLineas(texto, fuente, tamano, anchoTexto-sangria) {
+		documento.verificarEspacio(tamano * 1.35)
+		documento.linea(linea, fuente, tamano, margen+sangria)
+		documento.y -= tamano * 1.35
+	}
+}
+
+func (documento *DocumentoPDF) linea(texto string, fuente string, tamano float64, x float64) {
+	fmt.Fprintf(documento.paginaActual(), "BT /%s %g Tf %.2f %.2f Td (%s) Tj ET\n", fuente, tamano, x, documento.y-tamano, textoPDF(texto))
+}
+
+func (documento *DocumentoPDF) espacio(puntos float64) {
+	documento.y -= puntos
+}
+
+func (documento *DocumentoPDF) verificarEspacio(alto float64) {
+	if documento.y-alto < margen {
+		documento.NuevaPagina()
+	}
+}
+
+func dividirEnLineas(texto string, fuente string, tamano float64, ancho float64) []string {
+	var lineas []string
+	actual := ""
+	for _, palabra := range strings.Fields(texto) {
+		candidata := palabra
+		if actual != "" {
+			candidata = actual + " " + palabra
+		}
+		if actual != "" && anchoDeTexto(candidata, fuente, tamano) > ancho {
+			lineas = append(lineas, actual)
+			candidata = palabra
+		}
+		actual = candidata
+	}
+	if actual != "" || len(lineas) == 0 {
+		lineas = append(lineas, actual)
+	}
+	return lineas
+}
+
+// Anchos de los caracteres ASCII imprimibles (32 a 126) en milésimas del tamaño de la fuente, según las métricas de Adobe
+var (
+	anchosHelvetica = [95]int{
+		278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+		1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+		333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+		556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+	}
+	anchosHelveticaNegrita = [95]int{
+		278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+		975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+		333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+		611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+	}
+)
+
+// Las letras acentuadas miden lo mismo que su letra base
+var letrasBase = map[rune]rune{
+	'á': 'a', 'é': 'e', 'í': 'i', 'ó': 'o', 'ú': 'u', 'ü': 'u', 'ñ': 'n',
+	'Á': 'A', 'É': 'E', 'Í': 'I', 'Ó': 'O', 'Ú': 'U', 'Ü': 'U', 'Ñ': 'N',
+	'¿': '?', '¡': '!', '–': '-', '‘': '\'', '’': '\'', '“': '"', '”': '"',
+}
+
+func anchoDeTexto(texto string, fuente string, tamano float64) float64 {
+	anchos := &anchosHelvetica
+	if fuente == fuenteNegrita {
+		anchos = &anchosHelveticaNegrita
+	}
+	total := 0
+	for _, runa := range texto {
+		if base, existe := letrasBase[runa]; existe {
+			runa = base
+		}
+		if runa >= 32 && runa <= 126 {
+			total += anchos[runa-32]
+		} else {
+			total += 556
+		}
+	}
+	return float64(total) * tamano / 1000
+}
+
+// Caracteres fuera de Latin-1 que tienen lugar en WinAnsiEncoding
+var caracteresWinAnsi = map[rune]byte{
+	'€': 0x80, '…': 0x85, '‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97,
+}
+
+// textoPDF codifica el texto en WinAnsi y escapa los caracteres especiales de las cadenas PDF
+func textoPDF(texto string) string {
+	var salida strings.Builder
+	for _, runa := range texto {
+		switch {
+		case runa == '(' || runa == ')' || runa == '\\':
+			salida.WriteByte('\\')
+			salida.WriteByte(byte(runa))
+		case runa >= 32 && runa <= 126, runa >= 0xA0 && runa <= 0xFF:
+			salida.WriteByte(byte(runa))
+		default:
+			if codigo, existe := caracteresWinAnsi[runa]; existe {
+				salida.WriteByte(codigo)
+			} else {
+				salida.WriteByte('?')
+			}
+		}
+	}
+	return salida.String()
+}
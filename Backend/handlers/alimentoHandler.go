@@ -117,3 +117,85 @@ func (handler *AlimentoHandler) DeleteAlimento(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, gin.H{"success": success})
 }
+
+// BuscarAlimentos busca los alimentos de la despensa por nombre con ?q=, sin importar mayúsculas ni acentos
+func (handler *AlimentoHandler) BuscarAlimentos(c *gin.Context) {
+	usuario := dto.NewUsuario(utils.GetUserInfoFromContext(c))
+	log.Printf("[handler:AlimentoHandler][method:BuscarAlimentos][status:before_service_call][user:%s]", usuario.Codigo)
+	var parametros dto.ParametrosBusqueda
+	if err := c.ShouldBindQuery(&parametros); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query parameters"})
+		return
+	}
+	resultados, appErr := handler.alimentoService.BuscarAlimentos(parametros, usuario.Codigo)
+	log.Printf("[handler:AlimentoHandler][method:BuscarAlimentos][status:after_service_call][cantidad:%d][user:%s]", len(resultados), usuario.Codigo)
+	if appErr != nil {
+		if appErr.Codigo == "ERR_400" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": appErr.Mensaje})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": appErr.Mensaje})
+		return
+	}
+	c.JSON(http.StatusOK, resultados)
+}
+
+// Autocompletar sugiere alimentos a partir de lo escrito en ?q=, para elegir ingredientes sin crear duplicados
+func (handler *AlimentoHandler) Autocompletar(c *gin.Context) {
+	usuario := dto.NewUsuario(utils.GetUserInfoFromContext(c))
+	log.Printf("[handler:AlimentoHandler][method:Autocompletar][status:before_service_call][user:%s]", usuario.Codigo)
+	var parametros dto.ParametrosAutocompletado
+	if err := c.ShouldBindQuery(&parametros); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query parameters"})
+		return
+	}
+	sugerencias, appErr := handler.alimentoService.Autocompletar(parametros, usuario.Codigo)
+	log.Printf("[handler:AlimentoHandler][method:Autocompletar][status:after_service_call][cantidad:%d][user:%s]", len(sugerencias), usuario.Codigo)
+	if appErr != nil {
+		if appErr.Codigo == "ERR_400" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": appErr.Mensaje})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": appErr.Mensaje})
+		return
+	}
+	c.JSON(http.StatusOK, sugerencias)
+}
+
+// GetDuplicados devuelve los grupos de alimentos que parecen repetidos, para fusionarlos
+func (handler *AlimentoHandler) GetDuplicados(c *gin.Context) {
+	usuario := dto.NewUsuario(utils.GetUserInfoFromContext(c))
+	log.Printf("[handler:AlimentoHandler][method:GetDuplicados][status:before_service_call][user:%s]", usuario.Codigo)
+	grupos, appErr := handler.alimentoService.GetDuplicados(usuario.Codigo)
+	log.Printf("[handler:AlimentoHandler][method:GetDuplicados][status:after_service_call][cantidad:%d][user:%s]", len(grupos), usuario.Codigo)
+	if appErr != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": appErr.Mensaje})
+		return
+	}
+	c.JSON(http.StatusOK, grupos)
+}
+
+// FusionarAlimentos fusiona varios alimentos en uno y devuelve el alimento resultante
+func (handler *AlimentoHandler) FusionarAlimentos(c *gin.Context) {
+	usuario := dto.NewUsuario(utils.GetUserInfoFromContext(c))
+	log.Printf("[handler:AlimentoHandler][method:FusionarAlimentos][status:before_service_call][user:%s]", usuario.Codigo)
+	var fusion dto.FusionAlimentos
+	if err := c.BindJSON(&fusion); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error al leer el body"})
+		return
+	}
+	alimento, appErr := handler.alimentoService.FusionarAlimentos(fusion, usuario.Codigo)
+	log.Printf("[handler:AlimentoHandler][method:FusionarAlimentos][status:after_service_call][fusionados:%d][user:%s]", len(fusion.Fusionados), usuario.Codigo)
+	if appErr != nil {
+		switch appErr.Codigo {
+		case "ERR_400":
+			c.JSON(http.StatusBadRequest, gin.H{"error": appErr.Mensaje})
+		case "ERR_404":
+			c.JSON(http.StatusNotFound, gin.H{"error": appErr.Mensaje})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": appErr.Mensaje})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, alimento)
+}
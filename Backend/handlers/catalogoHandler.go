@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"gocooking-backend/dto"
+	"gocooking-backend/service"
+	"gocooking-backend/utils"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CatalogoHandler struct {
+	catalogoService service.CatalogoInterface
+}
+
+func NewCatalogoHandler(catalogoService service.CatalogoInterface) *CatalogoHandler {
+	return &CatalogoHandler{
+		catalogoService: catalogoService,
+	}
+}
+
+// GetCatalogo devuelve las recetas públicas de todos los usuarios, opcionalmente filtradas por ?tag=
+func (handler *CatalogoHandler) GetCatalogo(c *gin.Context) {
+	usuario := dto.NewUsuario(utils.GetUserInfoFromContext(c))
+	log.Printf("[handler:CatalogoHandler][method:GetCatalogo][status:before_service_call][user:%s]", usuario.Codigo)
+	var parametros dto.ParametrosListadoRecetas
+	if bindErr := c.ShouldBindQuery(&parametros); bindErr != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query parameters"})
+		return
+	}
+	recetas, appErr := handler.catalogoService.GetCatalogo(parametros)
+	log.Printf("[handler:CatalogoHandler][method:GetCatalogo][status:after_service_call][cantidad:%d][user:%s]", len(recetas), usuario.Codigo)
+	if appErr != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": appErr.Mensaje})
+		return
+	}
+	c.JSON(http.StatusOK, recetas)
+}
+
+// CopiarReceta copia una receta del catálogo (o compartida con el usuario) a su recetario
+func (handler *CatalogoHandler) CopiarReceta(c *gin.Context) {
+	usuario := dto.NewUsuario(utils.GetUserInfoFromContext(c))
+	log.Printf("[handler:CatalogoHandler][method:CopiarReceta][status:before_service_call][user:%s]", usuario.Codigo)
+	var opciones dto.CopiaReceta
+	// El body es opcional: sin body no se crean los alimentos faltantes
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&opciones); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+			return
+		}
+	}
+	id := c.Param("id")
+	resultado, appErr := handler.catalogoService.CopiarReceta(id, usuario.Codigo, opciones)
+	log.Printf("[handler:CatalogoHandler][method:CopiarReceta][status:after_service_call][receta:%s][user:%s]", id, usuario.Codigo)
+	if appErr != nil {
+		if appErr.Codigo == "ERR_400" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": appErr.Mensaje})
+			return
+		}
+		if appErr.Codigo == "ERR_404" {
+			c.JSON(http.StatusNotFound, gin.H{"error": appErr.Mensaje})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": appErr.Mensaje})
+		return
+	}
+	c.JSON(http.StatusCreated, resultado)
+}
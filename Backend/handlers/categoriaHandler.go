@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"gocooking-backend/dto"
+	"gocooking-backend/service"
+	"gocooking-backend/utils"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CategoriaHandler struct {
+	categoriaService service.CategoriaInterface
+}
+
+func NewCategoriaHandler(categoriaService service.CategoriaInterface) *CategoriaHandler {
+	return &CategoriaHandler{
+		categoriaService: categoriaService,
+	}
+}
+
+// GetCategorias lista las categorías de alimentos que puede usar el usuario, del sistema y propias
+func (handler *CategoriaHandler) GetCategorias(c *gin.Context) {
+	usuario := dto.NewUsuario(utils.GetUserInfoFromContext(c))
+	log.Printf("[handler:CategoriaHandler][method:GetCategorias][status:before_service_call][user:%s]", usuario.Codigo)
+	categorias, err := handler.categoriaService.GetCategorias(usuario.Codigo)
+	log.Printf("[handler:CategoriaHandler][method:GetCategorias][status:after_service_call][cantidad:%d][user:%s]", len(categorias), usuario.Codigo)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Mensaje})
+		return
+	}
+	c.JSON(http.StatusOK, categorias)
+}
+
+func (handler *CategoriaHandler) InsertCategoria(c *gin.Context) {
+	usuario := dto.NewUsuario(utils.GetUserInfoFromContext(c))
+	log.Printf("[handler:CategoriaHandler][method:InsertCategoria][status:before_service_call][user:%s]", usuario.Codigo)
+	var categoria dto.Categoria
+	err := c.BindJSON(&categoria)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error al leer el body"})
+		return
+	}
+	categoria.UsuarioID = usuario.Codigo
+	creado, appErr := handler.categoriaService.InsertCategoria(&categoria)
+	log.Printf("[handler:CategoriaHandler][method:InsertCategoria][status:after_service_call][user:%s]", usuario.Codigo)
+	if appErr != nil {
+		if appErr.Codigo == "ERR_400" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": appErr.Mensaje})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": appErr.Mensaje})
+		return
+	}
+	c.JSON(http.StatusCreated, creado)
+}
+
+func (handler *CategoriaHandler) DeleteCategoria(c *gin.Context) {
+	usuario := dto.NewUsuario(utils.GetUserInfoFromContext(c))
+	log.Printf("[handler:CategoriaHandler][method:DeleteCategoria][status:before_service_call][user:%s]", usuario.Codigo)
+	id := c.Param("id")
+	_, appErr := handler.categoriaService.DeleteCategoria(id, usuario.Codigo)
+	log.Printf("[handler:CategoriaHandler][method:DeleteCategoria][status:after_service_call][categoria:%s][user:%s]", id, usuario.Codigo)
+	if appErr != nil {
+		switch appErr.Codigo {
+		case "ERR_400":
+			c.JSON(http.StatusBadRequest, gin.H{"error": appErr.Mensaje})
+		case "ERR_404":
+			c.JSON(http.StatusNotFound, gin.H{"error": appErr.Mensaje})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": appErr.Mensaje})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Categoría eliminada"})
+}
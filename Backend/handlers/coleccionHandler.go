@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"gocooking-backend/dto"
+	"gocooking-backend/service"
+	"gocooking-backend/utils"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ColeccionHandler struct {
+	coleccionService service.ColeccionInterface
+}
+
+func NewColeccionHandler(coleccionService service.ColeccionInterface) *ColeccionHandler {
+	return &ColeccionHandler{
+		coleccionService: coleccionService,
+	}
+}
+
+func (handler *ColeccionHandler) GetColecciones(c *gin.Context) {
+	usuario := dto.NewUsuario(utils.GetUserInfoFromContext(c))
+	log.Printf("[handler:ColeccionHandler][method:GetColecciones][status:before_service_call][user:%s]", usuario.Codigo)
+	colecciones, err := handler.coleccionService.GetColecciones(usuario.Codigo)
+	log.Printf("[handler:ColeccionHandler][method:GetColecciones][status:after_service_call][cantidad:%d][user:%s]", len(colecciones), usuario.Codigo)
+	if err != nil {
+		if err.Codigo == "ERR_404" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Mensaje})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Mensaje})
+		return
+	}
+	c.JSON(http.StatusOK, colecciones)
+}
+
+func (handler *ColeccionHandler) GetColeccionByID(c *gin.Context) {
+	usuario := dto.NewUsuario(utils.GetUserInfoFromContext(c))
+	log.Printf("[handler:ColeccionHandler][method:GetColeccionByID][status:before_service_call][user:%s]", usuario.Codigo)
+	id := c.Param("id")
+	coleccion, err := handler.coleccionService.GetColeccionByID(id, usuario.Codigo)
+	log.Printf("[handler:ColeccionHandler][method:GetColeccionByID][status:after_service_call][coleccion:%s][user:%s]", id, usuario.Codigo)
+	if err != nil {
+		if err.Codigo == "ERR_404" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Mensaje})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Mensaje})
+		return
+	}
+	c.JSON(http.StatusOK, coleccion)
+}
+
+func (handler *ColeccionHandler) InsertColeccion(c *gin.Context) {
+	usuario := dto.NewUsuario(utils.GetUserInfoFromContext(c))
+	log.Printf("[handler:ColeccionHandler][method:InsertColeccion][status:before_service_call][user:%s]", usuario.Codigo)
+	var coleccion dto.Coleccion
+	err := c.BindJSON(&coleccion)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error al leer el body"})
+		return
+	}
+	coleccion.UsuarioID = usuario.Codigo
+	creada, appErr := handler.coleccionService.InsertColeccion(&coleccion)
+	log.Printf("[handler:ColeccionHandler][method:InsertColeccion][status:after_service_call][user:%s]", usuario.Codigo)
+	if appErr != nil {
+		if appErr.Codigo == "ERR_400" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": appErr.Mensaje})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": appErr.Mensaje})
+		return
+	}
+	c.JSON(http.StatusCreated, creada)
+}
+
+func (handler *ColeccionHandler) UpdateColeccion(c *gin.Context) {
+	usuario := dto.NewUsuario(utils.GetUserInfoFromContext(c))
+	log.Printf("[handler:ColeccionHandler][method:UpdateColeccion][status:before_service_call][user:%s]", usuario.Codigo)
+	var coleccion dto.Coleccion
+	err := c.BindJSON(&coleccion)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error al leer el body"})
+		return
+	}
+	coleccion.Id = c.Param("id")
+	coleccion.UsuarioID = usuario.Codigo
+	success, appErr := handler.coleccionService.UpdateColeccion(&coleccion)
+	log.Printf("[handler:ColeccionHandler][method:UpdateColeccion][status:after_service_call][success:%t][user:%s]", success, usuario.Codigo)
+	if appErr != nil {
+		if appErr.Codigo == "ERR_400" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": appErr.Mensaje})
+			return
+		}
+		if appErr.Codigo == "ERR_404" {
+			c.JSON(http.StatusNotFound, gin.H{"error": appErr.Mensaje})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": appErr.Mensaje})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": success})
+}
+
+func (handler *ColeccionHandler) DeleteColeccion(c *gin.Context) {
+	usuario := dto.NewUsuario(utils.GetUserInfoFromContext(c))
+	log.Printf("[handler:ColeccionHandler][method:DeleteColeccion][status:before_service_call][user:%s]", usuario.Codigo)
+	id := c.Param("id")
+	success, appErr := handler.coleccionService.DeleteColeccion(id, usuario.Codigo)
+	log.Printf("[handler:ColeccionHandler][method:DeleteColeccion][status:after_service_call][success:%t][user:%s]", success, usuario.Codigo)
+	if appErr != nil {
+		if appErr.Codigo == "ERR_404" {
+			c.JSON(http.StatusNotFound, gin.H{"error": appErr.Mensaje})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": appErr.Mensaje})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": success})
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"gocooking-backend/dto"
+	"gocooking-backend/service"
+	"gocooking-backend/utils"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type EquipamientoHandler struct {
+	equipamientoService service.EquipamientoInterface
+}
+
+func NewEquipamientoHandler(equipamientoService service.EquipamientoInterface) *EquipamientoHandler {
+	return &EquipamientoHandler{
+		equipamientoService: equipamientoService,
+	}
+}
+
+func (handler *EquipamientoHandler) GetEquipamiento(c *gin.Context) {
+	usuario := dto.NewUsuario(utils.GetUserInfoFromContext(c))
+	log.Printf("[handler:EquipamientoHandler][method:GetEquipamiento][status:before_service_call][user:%s]", usuario.Codigo)
+	equipamiento, err := handler.equipamientoService.GetEquipamiento(usuario.Codigo)
+	log.Printf("[handler:EquipamientoHandler][method:GetEquipamiento][status:after_service_call][user:%s]", usuario.Codigo)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Mensaje})
+		return
+	}
+	c.JSON(http.StatusOK, equipamiento)
+}
+
+// UpdateEquipamiento reemplaza la lista de equipos que tiene el usuario
+func (handler *EquipamientoHandler) UpdateEquipamiento(c *gin.Context) {
+	usuario := dto.NewUsuario(utils.GetUserInfoFromContext(c))
+	log.Printf("[handler:EquipamientoHandler][method:UpdateEquipamiento][status:before_service_call][user:%s]", usuario.Codigo)
+	var equipamiento dto.Equipamiento
+	err := c.BindJSON(&equipamiento)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error al leer el body"})
+		return
+	}
+	equipamiento.UsuarioID = usuario.Codigo
+	actualizado, appErr := handler.equipamientoService.UpdateEquipamiento(&equipamiento)
+	log.Printf("[handler:EquipamientoHandler][method:UpdateEquipamiento][status:after_service_call][user:%s]", usuario.Codigo)
+	if appErr != nil {
+		if appErr.Codigo == "ERR_400" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": appErr.Mensaje})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": appErr.Mensaje})
+		return
+	}
+	c.JSON(http.StatusOK, actualizado)
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"gocooking-backend/dto"
+	"gocooking-backend/service"
+	"gocooking-backend/utils"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ExportacionHandler struct {
+	exportacionService service.ExportacionInterface
+}
+
+func NewExportacionHandler(exportacionService service.ExportacionInterface) *ExportacionHandler {
+	return &ExportacionHandler{
+		exportacionService: exportacionService,
+	}
+}
+
+// ExportarReceta descarga la receta en el formato indicado con ?format=markdown|pdf|jsonld|cooklang (markdown por defecto)
+func (handler *ExportacionHandler) ExportarReceta(c *gin.Context) {
+	usuario := dto.NewUsuario(utils.GetUserInfoFromContext(c))
+	log.Printf("[handler:ExportacionHandler][method:ExportarReceta][status:before_service_call][user:%s]", usuario.Codigo)
+	id := c.Param("id")
+	archivo, appErr := handler.exportacionService.ExportarReceta(id, usuario.Codigo, c.Query("format"))
+	log.Printf("[handler:ExportacionHandler][method:ExportarReceta][status:after_service_call][receta:%s][user:%s]", id, usuario.Codigo)
+	if appErr != nil {
+		responderErrorExportacion(c, appErr)
+		return
+	}
+	c.Header("Content-Disposition", `attachment; filename="`+archivo.Nombre+`"`)
+	c.Data(http.StatusOK, archivo.ContentType, archivo.Contenido)
+}
+
+// ExportarColeccion descarga todas las recetas de la colección como un recetario en PDF
+func (handler *ExportacionHandler) ExportarColeccion(c *gin.Context) {
+	usuario := dto.NewUsuario(utils.GetUserInfoFromContext(c))
+	log.Printf("[handler:ExportacionHandler][method:ExportarColeccion][status:before_service_call][user:%s]", usuario.Codigo)
+	id := c.Param("id")
+	archivo, appErr := handler.exportacionService.ExportarColeccion(id, usuario.Codigo)
+	log.Printf("[handler:ExportacionHandler][method:ExportarColeccion][status:after_service_call][coleccion:%s][user:%s]", id, usuario.Codigo)
+	if appErr != nil {
+		responderErrorExportacion(c, appErr)
+		return
+	}
+	c.Header("Content-Disposition", `attachment; filename="`+archivo.Nombre+`"`)
+	c.Data(http.StatusOK, archivo.ContentType, archivo.Contenido)
+}
+
+func responderErrorExportacion(c *gin.Context, appErr *utils.AppError) {
+	if appErr.Codigo == "ERR_400" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": appErr.Mensaje})
+		return
+	}
+	if appErr.Codigo == "ERR_404" {
+		c.JSON(http.StatusNotFound, gin.H{"error": appErr.Mensaje})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": appErr.Mensaje})
+}
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"gocooking-backend/dto"
+	"gocooking-backend/service"
+	"gocooking-backend/utils"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ImagenHandler struct {
+	imagenService service.ImagenInterface
+}
+
+func NewImagenHandler(imagenService service.ImagenInterface) *ImagenHandler {
+	return &ImagenHandler{
+		imagenService: imagenService,
+	}
+}
+
+func (handler *ImagenHandler) InsertImagen(c *gin.Context) {
+	usuario := dto.NewUsuario(utils.GetUserInfoFromContext(c))
+	log.Printf("[handler:ImagenHandler][method:InsertImagen][status:before_service_call][user:%s]", usuario.Codigo)
+
+	// Limitar el body para no leer archivos enormes (se deja margen para el resto de los campos del formulario)
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, service.TamanoMaximoImagen+1<<20)
+	archivo, err := c.FormFile("imagen")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Debe enviar la imagen en el campo 'imagen' de un formulario multipart"})
+		return
+	}
+	paso, err := strconv.Atoi(c.DefaultPostForm("paso", "0"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "El número de paso es inválido"})
+		return
+	}
+	abierto, err := archivo.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No se pudo leer la imagen"})
+		return
+	}
+	defer abierto.Close()
+	contenido, err := io.ReadAll(io.LimitReader(abierto, service.TamanoMaximoImagen+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No se pudo leer la imagen"})
+		return
+	}
+
+	imagen, appErr := handler.imagenService.InsertImagen(c.Param("id"), usuario.Codigo, c.PostForm("tipo"), paso, contenido)
+	log.Printf("[handler:ImagenHandler][method:InsertImagen][status:after_service_call][user:%s]", usuario.Codigo)
+	if appErr != nil {
+		if appErr.Codigo == "ERR_400" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": appErr.Mensaje})
+			return
+		}
+		if appErr.Codigo == "ERR_404" {
+			c.JSON(http.StatusNotFound, gin.H{"error": appErr.Mensaje})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": appErr.Mensaje})
+		return
+	}
+	c.JSON(http.StatusCreated, imagen)
+}
+
+func (handler *ImagenHandler) GetImagen(c *gin.Context) {
+	usuario := dto.NewUsuario(utils.GetUserInfoFromContext(c))
+	log.Printf("[handler:ImagenHandler][method:GetImagen][status:before_service_call][user:%s]", usuario.Codigo)
+	miniatura := c.Query("miniatura") == "true"
+	contenido, contentType, appErr := handler.imagenService.GetImagen(c.Param("id"), usuario.Codigo, c.Param("imgId"), miniatura)
+	log.Printf("[handler:ImagenHandler][method:GetImagen][status:after_service_call][imagen:%s][user:%s]", c.Param("imgId"), usuario.Codigo)
+	if appErr != nil {
+		if appErr.Codigo == "ERR_404" {
+			c.JSON(http.StatusNotFound, gin.H{"error": appErr.Mensaje})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": appErr.Mensaje})
+		return
+	}
+	c.Data(http.StatusOK, contentType, contenido)
+}
+
+func (handler *ImagenHandler) DeleteImagen(c *gin.Context) {
+	usuario := dto.NewUsuario(utils.GetUserInfoFromContext(c))
+	log.Printf("[handler:ImagenHandler][method:DeleteImagen][status:before_service_call][user:%s]", usuario.Codigo)
+	success, appErr := handler.imagenService.DeleteImagen(c.Param("id"), usuario.Codigo, c.Param("imgId"))
+	log.Printf("[handler:ImagenHandler][method:DeleteImagen][status:after_service_call][success:%t][user:%s]", success, usuario.Codigo)
+	if appErr != nil {
+		if appErr.Codigo == "ERR_404" {
+			c.JSON(http.StatusNotFound, gin.H{"error": appErr.Mensaje})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": appErr.Mensaje})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": success})
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"gocooking-backend/dto"
+	"gocooking-backend/service"
+	"gocooking-backend/utils"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Tamaño máximo del documento a importar (las páginas de recetas suelen ser HTML pesados)
+const tamanoMaximoImportacion = 5 << 20
+
+type ImportacionHandler struct {
+	importacionService service.ImportacionInterface
+}
+
+func NewImportacionHandler(importacionService service.ImportacionInterface) *ImportacionHandler {
+	return &ImportacionHandler{
+		importacionService: importacionService,
+	}
+}
+
+// ImportarReceta recibe el documento (JSON-LD, HTML o Cooklang) como body o como archivo en el campo 'archivo' de un formulario multipart
+func (handler *ImportacionHandler) ImportarReceta(c *gin.Context) {
+	usuario := dto.NewUsuario(utils.GetUserInfoFromContext(c))
+	log.Printf("[handler:ImportacionHandler][method:ImportarReceta][status:before_service_call][user:%s]", usuario.Codigo)
+
+	contenido, nombreArchivo, err := leerDocumento(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No se pudo leer el documento a importar"})
+		return
+	}
+
+	// El formato se indica con ?format= o se deduce de la extensión del archivo subido
+	var receta *dto.Receta
+	var appErr *utils.AppError
+	if c.Query("format") == "cooklang" || strings.HasSuffix(strings.ToLower(nombreArchivo), ".cook") {
+		receta, appErr = handler.importacionService.ImportarCooklang(contenido, nombreArchivo, usuario.Codigo)
+	} else {
+		receta, appErr = handler.importacionService.ImportarJSONLD(contenido, usuario.Codigo)
+	}
+	log.Printf("[handler:ImportacionHandler][method:ImportarReceta][status:after_service_call][user:%s]", usuario.Codigo)
+	if appErr != nil {
+		if appErr.Codigo == "ERR_400" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": appErr.Mensaje})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": appErr.Mensaje})
+		return
+	}
+	c.JSON(http.StatusOK, receta)
+}
+
+func leerDocumento(c *gin.Context) ([]byte, string, error) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, tamanoMaximoImportacion)
+	if !strings.HasPrefix(c.ContentType(), "multipart/") {
+		contenido, err := io.ReadAll(c.Request.Body)
+		return contenido, "", err
+	}
+
+	archivo, err := c.FormFile("archivo")
+	if err != nil {
+		return nil, "", err
+	}
+	abierto, err := archivo.Open()
+	if err != nil {
+		return nil, "", err
+	}
+	defer abierto.Close()
+	contenido, err := io.ReadAll(abierto)
+	return contenido, archivo.Filename, err
+}
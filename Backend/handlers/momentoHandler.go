@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"gocooking-backend/dto"
+	"gocooking-backend/service"
+	"gocooking-backend/utils"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MomentoHandler struct {
+	momentoService service.MomentoInterface
+}
+
+func NewMomentoHandler(momentoService service.MomentoInterface) *MomentoHandler {
+	return &MomentoHandler{
+		momentoService: momentoService,
+	}
+}
+
+// GetMomentos lista los momentos de consumo que puede usar el usuario, predefinidos y propios
+func (handler *MomentoHandler) GetMomentos(c *gin.Context) {
+	usuario := dto.NewUsuario(utils.GetUserInfoFromContext(c))
+	log.Printf("[handler:MomentoHandler][method:GetMomentos][status:before_service_call][user:%s]", usuario.Codigo)
+	momentos, err := handler.momentoService.GetMomentos(usuario.Codigo)
+	log.Printf("[handler:MomentoHandler][method:GetMomentos][status:after_service_call][cantidad:%d][user:%s]", len(momentos), usuario.Codigo)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Mensaje})
+		return
+	}
+	c.JSON(http.StatusOK, momentos)
+}
+
+func (handler *MomentoHandler) InsertMomento(c *gin.Context) {
+	usuario := dto.NewUsuario(utils.GetUserInfoFromContext(c))
+	log.Printf("[handler:MomentoHandler][method:InsertMomento][status:before_service_call][user:%s]", usuario.Codigo)
+	var momento dto.Momento
+	err := c.BindJSON(&momento)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error al leer el body"})
+		return
+	}
+	momento.UsuarioID = usuario.Codigo
+	creado, appErr := handler.momentoService.InsertMomento(&momento)
+	log.Printf("[handler:MomentoHandler][method:InsertMomento][status:after_service_call][user:%s]", usuario.Codigo)
+	if appErr != nil {
+		if appErr.Codigo == "ERR_400" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": appErr.Mensaje})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": appErr.Mensaje})
+		return
+	}
+	c.JSON(http.StatusCreated, creado)
+}
+
+func (handler *MomentoHandler) DeleteMomento(c *gin.Context) {
+	usuario := dto.NewUsuario(utils.GetUserInfoFromContext(c))
+	log.Printf("[handler:MomentoHandler][method:DeleteMomento][status:before_service_call][user:%s]", usuario.Codigo)
+	id := c.Param("id")
+	_, appErr := handler.momentoService.DeleteMomento(id, usuario.Codigo)
+	log.Printf("[handler:MomentoHandler][method:DeleteMomento][status:after_service_call][momento:%s][user:%s]", id, usuario.Codigo)
+	if appErr != nil {
+		switch appErr.Codigo {
+		case "ERR_400":
+			c.JSON(http.StatusBadRequest, gin.H{"error": appErr.Mensaje})
+		case "ERR_404":
+			c.JSON(http.StatusNotFound, gin.H{"error": appErr.Mensaje})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": appErr.Mensaje})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Momento eliminado"})
+}
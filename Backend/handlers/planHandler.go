@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"gocooking-backend/dto"
+	"gocooking-backend/service"
+	"gocooking-backend/utils"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PlanHandler struct {
+	planService service.PlanInterface
+}
+
+func NewPlanHandler(planService service.PlanInterface) *PlanHandler {
+	return &PlanHandler{
+		planService: planService,
+	}
+}
+
+func (handler *PlanHandler) GetPlanes(c *gin.Context) {
+	usuario := dto.NewUsuario(utils.GetUserInfoFromContext(c))
+	log.Printf("[handler:PlanHandler][method:GetPlanes][status:before_service_call][user:%s]", usuario.Codigo)
+	planes, err := handler.planService.GetPlanes(usuario.Codigo)
+	log.Printf("[handler:PlanHandler][method:GetPlanes][status:after_service_call][cantidad:%d][user:%s]", len(planes), usuario.Codigo)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Mensaje})
+		return
+	}
+	c.JSON(http.StatusOK, planes)
+}
+
+func (handler *PlanHandler) GetPlanByID(c *gin.Context) {
+	usuario := dto.NewUsuario(utils.GetUserInfoFromContext(c))
+	log.Printf("[handler:PlanHandler][method:GetPlanByID][status:before_service_call][user:%s]", usuario.Codigo)
+	id := c.Param("id")
+	plan, err := handler.planService.GetPlanByID(id, usuario.Codigo)
+	log.Printf("[handler:PlanHandler][method:GetPlanByID][status:after_service_call][plan:%s][user:%s]", id, usuario.Codigo)
+	if err != nil {
+		if err.Codigo == "ERR_404" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Mensaje})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Mensaje})
+		return
+	}
+	c.JSON(http.StatusOK, plan)
+}
+
+// GenerarPlan arma un menú semanal en borrador con las restricciones del body
+func (handler *PlanHandler) GenerarPlan(c *gin.Context) {
+	usuario := dto.NewUsuario(utils.GetUserInfoFromContext(c))
+	log.Printf("[handler:PlanHandler][method:GenerarPlan][status:before_service_call][user:%s]", usuario.Codigo)
+	var parametros dto.ParametrosPlan
+	if err := c.BindJSON(&parametros); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error al leer el body"})
+		return
+	}
+	parametros.UsuarioID = usuario.Codigo
+	plan, appErr := handler.planService.GenerarPlan(parametros)
+	log.Printf("[handler:PlanHandler][method:GenerarPlan][status:after_service_call][user:%s]", usuario.Codigo)
+	if appErr != nil {
+		if appErr.Codigo == "ERR_400" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": appErr.Mensaje})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": appErr.Mensaje})
+		return
+	}
+	c.JSON(http.StatusCreated, plan)
+}
+
+// CambiarComida reemplaza la receta de una comida del borrador y recalcula las compras
+func (handler *PlanHandler) CambiarComida(c *gin.Context) {
+	usuario := dto.NewUsuario(utils.GetUserInfoFromContext(c))
+	log.Printf("[handler:PlanHandler][method:CambiarComida][status:before_service_call][user:%s]", usuario.Codigo)
+	var cambio dto.CambioComida
+	if err := c.BindJSON(&cambio); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error al leer el body"})
+		return
+	}
+	id := c.Param("id")
+	plan, appErr := handler.planService.CambiarComida(id, usuario.Codigo, cambio)
+	log.Printf("[handler:PlanHandler][method:CambiarComida][status:after_service_call][plan:%s][user:%s]", id, usuario.Codigo)
+	if appErr != nil {
+		switch appErr.Codigo {
+		case "ERR_400":
+			c.JSON(http.StatusBadRequest, gin.H{"error": appErr.Mensaje})
+		case "ERR_404":
+			c.JSON(http.StatusNotFound, gin.H{"error": appErr.Mensaje})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": appErr.Mensaje})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, plan)
+}
+
+func (handler *PlanHandler) AceptarPlan(c *gin.Context) {
+	usuario := dto.NewUsuario(utils.GetUserInfoFromContext(c))
+	log.Printf("[handler:PlanHandler][method:AceptarPlan][status:before_service_call][user:%s]", usuario.Codigo)
+	id := c.Param("id")
+	plan, appErr := handler.planService.AceptarPlan(id, usuario.Codigo)
+	log.Printf("[handler:PlanHandler][method:AceptarPlan][status:after_service_call][plan:%s][user:%s]", id, usuario.Codigo)
+	if appErr != nil {
+		switch appErr.Codigo {
+		case "ERR_400":
+			c.JSON(http.StatusBadRequest, gin.H{"error": appErr.Mensaje})
+		case "ERR_404":
+			c.JSON(http.StatusNotFound, gin.H{"error": appErr.Mensaje})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": appErr.Mensaje})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, plan)
+}
+
+func (handler *PlanHandler) DeletePlan(c *gin.Context) {
+	usuario := dto.NewUsuario(utils.GetUserInfoFromContext(c))
+	log.Printf("[handler:PlanHandler][method:DeletePlan][status:before_service_call][user:%s]", usuario.Codigo)
+	id := c.Param("id")
+	_, appErr := handler.planService.DeletePlan(id, usuario.Codigo)
+	log.Printf("[handler:PlanHandler][method:DeletePlan][status:after_service_call][plan:%s][user:%s]", id, usuario.Codigo)
+	if appErr != nil {
+		if appErr.Codigo == "ERR_404" {
+			c.JSON(http.StatusNotFound, gin.H{"error": appErr.Mensaje})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": appErr.Mensaje})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Plan eliminado"})
+}
@@ -23,9 +23,18 @@ func NewRecetaHandler(recetaService service.RecetaInterface) *RecetaHandler {
 func (handler *RecetaHandler) GetRecetas(c *gin.Context) {
 	usuario := dto.NewUsuario(utils.GetUserInfoFromContext(c))
 	log.Printf("[handler:RecetaHandler][method:GetRecetas][status:before_service_call][user:%s]", usuario.Codigo)
-	recetas, err := handler.recetaService.GetRecetas(usuario.Codigo)
+	var parametros dto.ParametrosListadoRecetas
+	if bindErr := c.ShouldBindQuery(&parametros); bindErr != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query parameters"})
+		return
+	}
+	recetas, err := handler.recetaService.GetRecetas(usuario.Codigo, parametros)
 	log.Printf("[handler:RecetaHandler][method:GetRecetas][status:after_service_call][cantidad:%d][user:%s]", len(recetas), usuario.Codigo)
 	if err != nil {
+		if err.Codigo == "ERR_400" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Mensaje})
+			return
+		}
 		if err.Codigo == "ERR_404" {
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Mensaje})
 			return
@@ -39,8 +48,8 @@ func (handler *RecetaHandler) GetRecetaByID(c *gin.Context) {
 	usuario := dto.NewUsuario(utils.GetUserInfoFromContext(c))
 	log.Printf("[handler:RecetaHandler][method:GetRecetaByID][status:before_service_call][user:%s]", usuario.Codigo)
 	id := c.Param("id")
-	receta, err := handler.recetaService.GetRecetaById(id)
-	log.Printf("[handler:RecetaHandler][method:GetRecetaByID][status:after_service_call][receta:%s][user:%s]", receta.Id, usuario.Codigo)
+	receta, err := handler.recetaService.GetRecetaById(id, usuario.Codigo)
+	log.Printf("[handler:RecetaHandler][method:GetRecetaByID][status:after_service_call][receta:%s][user:%s]", id, usuario.Codigo)
 	if err != nil {
 		if err.Codigo == "ERR_404" {
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Mensaje})
@@ -92,6 +101,10 @@ func (handler *RecetaHandler) UpdateReceta(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": appErr.Mensaje})
 			return
 		}
+		if appErr.Codigo == "ERR_404" {
+			c.JSON(http.StatusNotFound, gin.H{"error": appErr.Mensaje})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": appErr.Mensaje})
 		return
 
@@ -102,9 +115,13 @@ func (handler *RecetaHandler) DeleteReceta(c *gin.Context) {
 	usuario := dto.NewUsuario(utils.GetUserInfoFromContext(c))
 	log.Printf("[handler:RecetaHandler][method:DeleteReceta][status:before_service_call][user:%s]", usuario.Codigo)
 	id := c.Param("id")
-	_, appErr := handler.recetaService.DeleteReceta(id)
+	_, appErr := handler.recetaService.DeleteReceta(id, usuario.Codigo)
 	log.Printf("[handler:RecetaHandler][method:DeleteReceta][status:after_service_call][user:%s]", usuario.Codigo)
 	if appErr != nil {
+		if appErr.Codigo == "ERR_400" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": appErr.Mensaje})
+			return
+		}
 		if appErr.Codigo == "ERR_404" {
 			c.JSON(http.StatusNotFound, gin.H{"error": appErr.Mensaje})
 			return
@@ -177,3 +194,142 @@ func (handler *RecetaHandler) GetCantidadRecetasPorTipoAlimento(c *gin.Context)
 
 	c.JSON(http.StatusOK, cantidadRecetasPorTipoAlimento)
 }
+
+// GetRecetasCompartidas devuelve las recetas que otros usuarios compartieron con el usuario
+func (handler *RecetaHandler) GetRecetasCompartidas(c *gin.Context) {
+	usuario := dto.NewUsuario(utils.GetUserInfoFromContext(c))
+	log.Printf("[handler:RecetaHandler][method:GetRecetasCompartidas][status:before_service_call][user:%s]", usuario.Codigo)
+	recetas, appErr := handler.recetaService.GetRecetasCompartidas(usuario.Codigo)
+	log.Printf("[handler:RecetaHandler][method:GetRecetasCompartidas][status:after_service_call][cantidad:%d][user:%s]", len(recetas), usuario.Codigo)
+	if appErr != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": appErr.Mensaje})
+		return
+	}
+	c.JSON(http.StatusOK, recetas)
+}
+
+// BuscarRecetas busca las recetas por nombre, ingredientes y pasos con ?q=, sin importar mayúsculas ni acentos
+func (handler *RecetaHandler) BuscarRecetas(c *gin.Context) {
+	usuario := dto.NewUsuario(utils.GetUserInfoFromContext(c))
+	log.Printf("[handler:RecetaHandler][method:BuscarRecetas][status:before_service_call][user:%s]", usuario.Codigo)
+	var parametros dto.ParametrosBusqueda
+	if err := c.ShouldBindQuery(&parametros); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query parameters"})
+		return
+	}
+	resultados, appErr := handler.recetaService.BuscarRecetas(parametros, usuario.Codigo)
+	log.Printf("[handler:RecetaHandler][method:BuscarRecetas][status:after_service_call][cantidad:%d][user:%s]", len(resultados), usuario.Codigo)
+	if appErr != nil {
+		if appErr.Codigo == "ERR_400" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": appErr.Mensaje})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": appErr.Mensaje})
+		return
+	}
+	c.JSON(http.StatusOK, resultados)
+}
+
+// GetCostoReceta devuelve cuánto cuesta preparar la receta según el precio de sus alimentos
+func (handler *RecetaHandler) GetCostoReceta(c *gin.Context) {
+	usuario := dto.NewUsuario(utils.GetUserInfoFromContext(c))
+	log.Printf("[handler:RecetaHandler][method:GetCostoReceta][status:before_service_call][user:%s]", usuario.Codigo)
+	id := c.Param("id")
+	costo, err := handler.recetaService.GetCostoReceta(id, usuario.Codigo)
+	log.Printf("[handler:RecetaHandler][method:GetCostoReceta][status:after_service_call][receta:%s][user:%s]", id, usuario.Codigo)
+	if err != nil {
+		if err.Codigo == "ERR_404" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Mensaje})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Mensaje})
+		return
+	}
+	c.JSON(http.StatusOK, costo)
+}
+
+// GetIngredientesBase devuelve los alimentos de la receta con las subrecetas expandidas y las cantidades sumadas
+func (handler *RecetaHandler) GetIngredientesBase(c *gin.Context) {
+	usuario := dto.NewUsuario(utils.GetUserInfoFromContext(c))
+	log.Printf("[handler:RecetaHandler][method:GetIngredientesBase][status:before_service_call][user:%s]", usuario.Codigo)
+	id := c.Param("id")
+	ingredientes, err := handler.recetaService.GetIngredientesBase(id, usuario.Codigo)
+	log.Printf("[handler:RecetaHandler][method:GetIngredientesBase][status:after_service_call][receta:%s][cantidad:%d][user:%s]", id, len(ingredientes), usuario.Codigo)
+	if err != nil {
+		if err.Codigo == "ERR_404" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Mensaje})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Mensaje})
+		return
+	}
+	c.JSON(http.StatusOK, ingredientes)
+}
+
+// GetRecetasRecomendadas sugiere qué cocinar para aprovechar lo que está por vencer o sobra en la despensa
+func (handler *RecetaHandler) GetRecetasRecomendadas(c *gin.Context) {
+	usuario := dto.NewUsuario(utils.GetUserInfoFromContext(c))
+	log.Printf("[handler:RecetaHandler][method:GetRecetasRecomendadas][status:before_service_call][user:%s]", usuario.Codigo)
+	var parametros dto.ParametrosRecomendaciones
+	if err := c.ShouldBindQuery(&parametros); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query parameters"})
+		return
+	}
+	recomendadas, appErr := handler.recetaService.GetRecetasRecomendadas(parametros, usuario.Codigo)
+	log.Printf("[handler:RecetaHandler][method:GetRecetasRecomendadas][status:after_service_call][cantidad:%d][user:%s]", len(recomendadas), usuario.Codigo)
+	if appErr != nil {
+		if appErr.Codigo == "ERR_400" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": appErr.Mensaje})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": appErr.Mensaje})
+		return
+	}
+	c.JSON(http.StatusOK, recomendadas)
+}
+
+// ValidarReceta revisa la receta del body sin guardarla y devuelve todos sus problemas juntos
+func (handler *RecetaHandler) ValidarReceta(c *gin.Context) {
+	usuario := dto.NewUsuario(utils.GetUserInfoFromContext(c))
+	log.Printf("[handler:RecetaHandler][method:ValidarReceta][status:before_service_call][user:%s]", usuario.Codigo)
+	var receta dto.Receta
+	if err := c.BindJSON(&receta); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error al leer el body"})
+		return
+	}
+	receta.UsuarioID = usuario.Codigo
+	validacion, appErr := handler.recetaService.ValidarReceta(&receta)
+	if appErr != nil {
+		log.Printf("[handler:RecetaHandler][method:ValidarReceta][status:after_service_call][user:%s]", usuario.Codigo)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": appErr.Mensaje})
+		return
+	}
+	log.Printf("[handler:RecetaHandler][method:ValidarReceta][status:after_service_call][problemas:%d][user:%s]", len(validacion.Problemas), usuario.Codigo)
+	c.JSON(http.StatusOK, validacion)
+}
+
+// GetRecetasSimilares lista las recetas que usan alimentos parecidos; con catalogo=true incluye las públicas
+func (handler *RecetaHandler) GetRecetasSimilares(c *gin.Context) {
+	usuario := dto.NewUsuario(utils.GetUserInfoFromContext(c))
+	log.Printf("[handler:RecetaHandler][method:GetRecetasSimilares][status:before_service_call][user:%s]", usuario.Codigo)
+	var parametros dto.ParametrosSimilares
+	if err := c.ShouldBindQuery(&parametros); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query parameters"})
+		return
+	}
+	id := c.Param("id")
+	similares, appErr := handler.recetaService.GetRecetasSimilares(id, parametros, usuario.Codigo)
+	log.Printf("[handler:RecetaHandler][method:GetRecetasSimilares][status:after_service_call][receta:%s][cantidad:%d][user:%s]", id, len(similares), usuario.Codigo)
+	if appErr != nil {
+		switch appErr.Codigo {
+		case "ERR_400":
+			c.JSON(http.StatusBadRequest, gin.H{"error": appErr.Mensaje})
+		case "ERR_404":
+			c.JSON(http.StatusNotFound, gin.H{"error": appErr.Mensaje})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": appErr.Mensaje})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, similares)
+}
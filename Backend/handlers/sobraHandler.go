@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"gocooking-backend/dto"
+	"gocooking-backend/service"
+	"gocooking-backend/utils"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SobraHandler struct {
+	sobraService service.SobraInterface
+}
+
+func NewSobraHandler(sobraService service.SobraInterface) *SobraHandler {
+	return &SobraHandler{
+		sobraService: sobraService,
+	}
+}
+
+// GetSobras lista las porciones guardadas del usuario, primero las que vencen antes
+func (handler *SobraHandler) GetSobras(c *gin.Context) {
+	usuario := dto.NewUsuario(utils.GetUserInfoFromContext(c))
+	log.Printf("[handler:SobraHandler][method:GetSobras][status:before_service_call][user:%s]", usuario.Codigo)
+	sobras, err := handler.sobraService.GetSobras(usuario.Codigo)
+	log.Printf("[handler:SobraHandler][method:GetSobras][status:after_service_call][cantidad:%d][user:%s]", len(sobras), usuario.Codigo)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Mensaje})
+		return
+	}
+	c.JSON(http.StatusOK, sobras)
+}
+
+func (handler *SobraHandler) InsertSobra(c *gin.Context) {
+	usuario := dto.NewUsuario(utils.GetUserInfoFromContext(c))
+	log.Printf("[handler:SobraHandler][method:InsertSobra][status:before_service_call][user:%s]", usuario.Codigo)
+	var sobra dto.Sobra
+	err := c.BindJSON(&sobra)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error al leer el body"})
+		return
+	}
+	sobra.UsuarioID = usuario.Codigo
+	creada, appErr := handler.sobraService.InsertSobra(&sobra)
+	log.Printf("[handler:SobraHandler][method:InsertSobra][status:after_service_call][receta:%s][user:%s]", sobra.RecetaId, usuario.Codigo)
+	if appErr != nil {
+		switch appErr.Codigo {
+		case "ERR_400":
+			c.JSON(http.StatusBadRequest, gin.H{"error": appErr.Mensaje})
+		case "ERR_404":
+			c.JSON(http.StatusNotFound, gin.H{"error": appErr.Mensaje})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": appErr.Mensaje})
+		}
+		return
+	}
+	c.JSON(http.StatusCreated, creada)
+}
+
+// ComerSobra descuenta las porciones comidas; sin body se come una
+func (handler *SobraHandler) ComerSobra(c *gin.Context) {
+	usuario := dto.NewUsuario(utils.GetUserInfoFromContext(c))
+	log.Printf("[handler:SobraHandler][method:ComerSobra][status:before_service_call][user:%s]", usuario.Codigo)
+	var consumo dto.ConsumoSobra
+	if c.Request.ContentLength > 0 {
+		if err := c.BindJSON(&consumo); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Error al leer el body"})
+			return
+		}
+	}
+	id := c.Param("id")
+	sobra, appErr := handler.sobraService.ComerSobra(id, usuario.Codigo, consumo)
+	log.Printf("[handler:SobraHandler][method:ComerSobra][status:after_service_call][sobra:%s][user:%s]", id, usuario.Codigo)
+	if appErr != nil {
+		switch appErr.Codigo {
+		case "ERR_400":
+			c.JSON(http.StatusBadRequest, gin.H{"error": appErr.Mensaje})
+		case "ERR_404":
+			c.JSON(http.StatusNotFound, gin.H{"error": appErr.Mensaje})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": appErr.Mensaje})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, sobra)
+}
+
+func (handler *SobraHandler) DeleteSobra(c *gin.Context) {
+	usuario := dto.NewUsuario(utils.GetUserInfoFromContext(c))
+	log.Printf("[handler:SobraHandler][method:DeleteSobra][status:before_service_call][user:%s]", usuario.Codigo)
+	id := c.Param("id")
+	_, appErr := handler.sobraService.DeleteSobra(id, usuario.Codigo)
+	log.Printf("[handler:SobraHandler][method:DeleteSobra][status:after_service_call][sobra:%s][user:%s]", id, usuario.Codigo)
+	if appErr != nil {
+		if appErr.Codigo == "ERR_404" {
+			c.JSON(http.StatusNotFound, gin.H{"error": appErr.Mensaje})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": appErr.Mensaje})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Sobra eliminada"})
+}
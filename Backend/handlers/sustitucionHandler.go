@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"gocooking-backend/dto"
+	"gocooking-backend/service"
+	"gocooking-backend/utils"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SustitucionHandler struct {
+	sustitucionService service.SustitucionInterface
+}
+
+func NewSustitucionHandler(sustitucionService service.SustitucionInterface) *SustitucionHandler {
+	return &SustitucionHandler{
+		sustitucionService: sustitucionService,
+	}
+}
+
+// GetSustituciones lista las sustituciones del usuario; con ?receta= solo las que aplican a esa receta
+func (handler *SustitucionHandler) GetSustituciones(c *gin.Context) {
+	usuario := dto.NewUsuario(utils.GetUserInfoFromContext(c))
+	log.Printf("[handler:SustitucionHandler][method:GetSustituciones][status:before_service_call][user:%s]", usuario.Codigo)
+	sustituciones, err := handler.sustitucionService.GetSustituciones(usuario.Codigo, c.Query("receta"))
+	log.Printf("[handler:SustitucionHandler][method:GetSustituciones][status:after_service_call][cantidad:%d][user:%s]", len(sustituciones), usuario.Codigo)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Mensaje})
+		return
+	}
+	c.JSON(http.StatusOK, sustituciones)
+}
+
+func (handler *SustitucionHandler) InsertSustitucion(c *gin.Context) {
+	usuario := dto.NewUsuario(utils.GetUserInfoFromContext(c))
+	log.Printf("[handler:SustitucionHandler][method:InsertSustitucion][status:before_service_call][user:%s]", usuario.Codigo)
+	var sustitucion dto.Sustitucion
+	err := c.BindJSON(&sustitucion)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error al leer el body"})
+		return
+	}
+	sustitucion.UsuarioID = usuario.Codigo
+	creada, appErr := handler.sustitucionService.InsertSustitucion(&sustitucion)
+	log.Printf("[handler:SustitucionHandler][method:InsertSustitucion][status:after_service_call][user:%s]", usuario.Codigo)
+	if appErr != nil {
+		if appErr.Codigo == "ERR_400" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": appErr.Mensaje})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": appErr.Mensaje})
+		return
+	}
+	c.JSON(http.StatusCreated, creada)
+}
+
+func (handler *SustitucionHandler) DeleteSustitucion(c *gin.Context) {
+	usuario := dto.NewUsuario(utils.GetUserInfoFromContext(c))
+	log.Printf("[handler:SustitucionHandler][method:DeleteSustitucion][status:before_service_call][user:%s]", usuario.Codigo)
+	id := c.Param("id")
+	_, appErr := handler.sustitucionService.DeleteSustitucion(id, usuario.Codigo)
+	log.Printf("[handler:SustitucionHandler][method:DeleteSustitucion][status:after_service_call][sustitucion:%s][user:%s]", id, usuario.Codigo)
+	if appErr != nil {
+		if appErr.Codigo == "ERR_404" {
+			c.JSON(http.StatusNotFound, gin.H{"error": appErr.Mensaje})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": appErr.Mensaje})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Sustitución eliminada"})
+}
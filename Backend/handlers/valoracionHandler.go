@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"gocooking-backend/dto"
+	"gocooking-backend/service"
+	"gocooking-backend/utils"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ValoracionHandler struct {
+	valoracionService service.ValoracionInterface
+}
+
+func NewValoracionHandler(valoracionService service.ValoracionInterface) *ValoracionHandler {
+	return &ValoracionHandler{
+		valoracionService: valoracionService,
+	}
+}
+
+func (handler *ValoracionHandler) GetValoracion(c *gin.Context) {
+	usuario := dto.NewUsuario(utils.GetUserInfoFromContext(c))
+	log.Printf("[handler:ValoracionHandler][method:GetValoracion][status:before_service_call][user:%s]", usuario.Codigo)
+	id := c.Param("id")
+	valoracion, err := handler.valoracionService.GetValoracion(id, usuario.Codigo)
+	log.Printf("[handler:ValoracionHandler][method:GetValoracion][status:after_service_call][receta:%s][user:%s]", id, usuario.Codigo)
+	if err != nil {
+		if err.Codigo == "ERR_404" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Mensaje})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Mensaje})
+		return
+	}
+	c.JSON(http.StatusOK, valoracion)
+}
+
+func (handler *ValoracionHandler) UpsertValoracion(c *gin.Context) {
+	usuario := dto.NewUsuario(utils.GetUserInfoFromContext(c))
+	log.Printf("[handler:ValoracionHandler][method:UpsertValoracion][status:before_service_call][user:%s]", usuario.Codigo)
+	var valoracion dto.Valoracion
+	err := c.BindJSON(&valoracion)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error al leer el body"})
+		return
+	}
+	valoracion.RecetaID = c.Param("id")
+	valoracion.UsuarioID = usuario.Codigo
+	success, appErr := handler.valoracionService.UpsertValoracion(&valoracion)
+	log.Printf("[handler:ValoracionHandler][method:UpsertValoracion][status:after_service_call][success:%t][user:%s]", success, usuario.Codigo)
+	if appErr != nil {
+		if appErr.Codigo == "ERR_400" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": appErr.Mensaje})
+			return
+		}
+		if appErr.Codigo == "ERR_404" {
+			c.JSON(http.StatusNotFound, gin.H{"error": appErr.Mensaje})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": appErr.Mensaje})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": success})
+}
+
+func (handler *ValoracionHandler) InsertNota(c *gin.Context) {
+	usuario := dto.NewUsuario(utils.GetUserInfoFromContext(c))
+	log.Printf("[handler:ValoracionHandler][method:InsertNota][status:before_service_call][user:%s]", usuario.Codigo)
+	var nota dto.Nota
+	err := c.BindJSON(&nota)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error al leer el body"})
+		return
+	}
+	creada, appErr := handler.valoracionService.InsertNota(c.Param("id"), usuario.Codigo, &nota)
+	log.Printf("[handler:ValoracionHandler][method:InsertNota][status:after_service_call][user:%s]", usuario.Codigo)
+	if appErr != nil {
+		if appErr.Codigo == "ERR_400" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": appErr.Mensaje})
+			return
+		}
+		if appErr.Codigo == "ERR_404" {
+			c.JSON(http.StatusNotFound, gin.H{"error": appErr.Mensaje})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": appErr.Mensaje})
+		return
+	}
+	c.JSON(http.StatusCreated, creada)
+}
+
+func (handler *ValoracionHandler) DeleteNota(c *gin.Context) {
+	usuario := dto.NewUsuario(utils.GetUserInfoFromContext(c))
+	log.Printf("[handler:ValoracionHandler][method:DeleteNota][status:before_service_call][user:%s]", usuario.Codigo)
+	success, appErr := handler.valoracionService.DeleteNota(c.Param("id"), usuario.Codigo, c.Param("notaId"))
+	log.Printf("[handler:ValoracionHandler][method:DeleteNota][status:after_service_call][success:%t][user:%s]", success, usuario.Codigo)
+	if appErr != nil {
+		if appErr.Codigo == "ERR_404" {
+			c.JSON(http.StatusNotFound, gin.H{"error": appErr.Mensaje})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": appErr.Mensaje})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": success})
+}
+
+func (handler *ValoracionHandler) GetRecetasFavoritas(c *gin.Context) {
+	usuario := dto.NewUsuario(utils.GetUserInfoFromContext(c))
+	log.Printf("[handler:ValoracionHandler][method:GetRecetasFavoritas][status:before_service_call][user:%s]", usuario.Codigo)
+	recetas, err := handler.valoracionService.GetRecetasFavoritas(usuario.Codigo)
+	log.Printf("[handler:ValoracionHandler][method:GetRecetasFavoritas][status:after_service_call][cantidad:%d][user:%s]", len(recetas), usuario.Codigo)
+	if err != nil {
+		if err.Codigo == "ERR_404" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Mensaje})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Mensaje})
+		return
+	}
+	c.JSON(http.StatusOK, recetas)
+}
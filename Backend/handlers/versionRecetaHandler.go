@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"gocooking-backend/dto"
+	"gocooking-backend/service"
+	"gocooking-backend/utils"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type VersionRecetaHandler struct {
+	versionRecetaService service.VersionRecetaInterface
+}
+
+func NewVersionRecetaHandler(versionRecetaService service.VersionRecetaInterface) *VersionRecetaHandler {
+	return &VersionRecetaHandler{
+		versionRecetaService: versionRecetaService,
+	}
+}
+
+func (handler *VersionRecetaHandler) GetVersiones(c *gin.Context) {
+	usuario := dto.NewUsuario(utils.GetUserInfoFromContext(c))
+	log.Printf("[handler:VersionRecetaHandler][method:GetVersiones][status:before_service_call][user:%s]", usuario.Codigo)
+	id := c.Param("id")
+	versiones, appErr := handler.versionRecetaService.GetVersiones(id, usuario.Codigo)
+	log.Printf("[handler:VersionRecetaHandler][method:GetVersiones][status:after_service_call][receta:%s][cantidad:%d][user:%s]", id, len(versiones), usuario.Codigo)
+	if appErr != nil {
+		responderErrorVersion(c, appErr)
+		return
+	}
+	c.JSON(http.StatusOK, versiones)
+}
+
+func (handler *VersionRecetaHandler) GetVersion(c *gin.Context) {
+	usuario := dto.NewUsuario(utils.GetUserInfoFromContext(c))
+	log.Printf("[handler:VersionRecetaHandler][method:GetVersion][status:before_service_call][user:%s]", usuario.Codigo)
+	id := c.Param("id")
+	numero, err := strconv.Atoi(c.Param("v"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "El número de versión no es válido"})
+		return
+	}
+	version, appErr := handler.versionRecetaService.GetVersion(id, numero, usuario.Codigo)
+	log.Printf("[handler:VersionRecetaHandler][method:GetVersion][status:after_service_call][receta:%s][version:%d][user:%s]", id, numero, usuario.Codigo)
+	if appErr != nil {
+		responderErrorVersion(c, appErr)
+		return
+	}
+	c.JSON(http.StatusOK, version)
+}
+
+// GetDiff compara dos versiones indicadas con ?desde=&hasta= (por defecto, la última con la anterior)
+func (handler *VersionRecetaHandler) GetDiff(c *gin.Context) {
+	usuario := dto.NewUsuario(utils.GetUserInfoFromContext(c))
+	log.Printf("[handler:VersionRecetaHandler][method:GetDiff][status:before_service_call][user:%s]", usuario.Codigo)
+	var parametros dto.ParametrosDiffVersiones
+	if err := c.ShouldBindQuery(&parametros); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query parameters"})
+		return
+	}
+	id := c.Param("id")
+	diff, appErr := handler.versionRecetaService.GetDiff(id, parametros, usuario.Codigo)
+	log.Printf("[handler:VersionRecetaHandler][method:GetDiff][status:after_service_call][receta:%s][user:%s]", id, usuario.Codigo)
+	if appErr != nil {
+		responderErrorVersion(c, appErr)
+		return
+	}
+	c.JSON(http.StatusOK, diff)
+}
+
+func (handler *VersionRecetaHandler) Revertir(c *gin.Context) {
+	usuario := dto.NewUsuario(utils.GetUserInfoFromContext(c))
+	log.Printf("[handler:VersionRecetaHandler][method:Revertir][status:before_service_call][user:%s]", usuario.Codigo)
+	id := c.Param("id")
+	numero, err := strconv.Atoi(c.Param("v"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "El número de versión no es válido"})
+		return
+	}
+	receta, appErr := handler.versionRecetaService.Revertir(id, numero, usuario.Codigo)
+	log.Printf("[handler:VersionRecetaHandler][method:Revertir][status:after_service_call][receta:%s][version:%d][user:%s]", id, numero, usuario.Codigo)
+	if appErr != nil {
+		responderErrorVersion(c, appErr)
+		return
+	}
+	c.JSON(http.StatusOK, receta)
+}
+
+func responderErrorVersion(c *gin.Context, appErr *utils.AppError) {
+	if appErr.Codigo == "ERR_400" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": appErr.Mensaje})
+		return
+	}
+	if appErr.Codigo == "ERR_404" {
+		c.JSON(http.StatusNotFound, gin.H{"error": appErr.Mensaje})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": appErr.Mensaje})
+}
@@ -6,16 +6,31 @@ import (
 	"gocooking-backend/middlewares"
 	"gocooking-backend/repositories"
 	"gocooking-backend/service"
+	"gocooking-backend/storage"
 	"log"
+	"os"
 
 	"github.com/gin-gonic/gin"
 )
 
 var (
-	router           *gin.Engine
-	alimentosHandler *handlers.AlimentoHandler
-	recetasHandler   *handlers.RecetaHandler
-	compraHandler    *handlers.CompraHandler
+	router              *gin.Engine
+	alimentosHandler    *handlers.AlimentoHandler
+	recetasHandler      *handlers.RecetaHandler
+	compraHandler       *handlers.CompraHandler
+	coleccionHandler    *handlers.ColeccionHandler
+	valoracionHandler   *handlers.ValoracionHandler
+	imagenHandler       *handlers.ImagenHandler
+	importacionHandler  *handlers.ImportacionHandler
+	exportacionHandler  *handlers.ExportacionHandler
+	catalogoHandler     *handlers.CatalogoHandler
+	versionHandler      *handlers.VersionRecetaHandler
+	sustitucionHandler  *handlers.SustitucionHandler
+	momentoHandler      *handlers.MomentoHandler
+	categoriaHandler    *handlers.CategoriaHandler
+	sobraHandler        *handlers.SobraHandler
+	planHandler         *handlers.PlanHandler
+	equipamientoHandler *handlers.EquipamientoHandler
 )
 
 func main() {
@@ -33,23 +48,106 @@ func dependencies() {
 	var alimentosRepository repositories.AlimentoRepositoryInterface
 	var recetasRepository repositories.RecetaRepositoryInterface
 	var comprasRepository repositories.CompraRepositoryInterface
+	var coleccionesRepository repositories.ColeccionRepositoryInterface
+	var valoracionesRepository repositories.ValoracionRepositoryInterface
+	var imagenesRepository repositories.ImagenRepositoryInterface
+	var versionesRepository repositories.VersionRecetaRepositoryInterface
+	var sustitucionesRepository repositories.SustitucionRepositoryInterface
+	var momentosRepository repositories.MomentoRepositoryInterface
+	var categoriasRepository repositories.CategoriaRepositoryInterface
+	var sobrasRepository repositories.SobraRepositoryInterface
+	var planesRepository repositories.PlanRepositoryInterface
+	var equipamientoRepository repositories.EquipamientoRepositoryInterface
 
 	var alimentosService service.AlimentoInterface
 	var recetasService service.RecetaInterface
 	var comprasService service.CompraInterface
+	var coleccionesService service.ColeccionInterface
+	var valoracionesService service.ValoracionInterface
+	var imagenesService service.ImagenInterface
+	var importacionService service.ImportacionInterface
+	var exportacionService service.ExportacionInterface
+	var catalogoService service.CatalogoInterface
+	var versionesService service.VersionRecetaInterface
+	var sustitucionesService service.SustitucionInterface
+	var momentosService service.MomentoInterface
+	var categoriasService service.CategoriaInterface
+	var sobrasService service.SobraInterface
+	var planesService service.PlanInterface
+	var equipamientoService service.EquipamientoInterface
 	//Inyectar repositorios
-	database, _ = repositories.NewMongoDB()
+	mongoDB, err := repositories.NewMongoDB()
+	database = mongoDB
+	if err == nil {
+		if !repositories.AdmiteTransacciones(database) {
+			log.Printf("MongoDB no es un replica set: los alimentos se fusionarán y las recetas se modificarán sin transacción")
+		}
+		if err := repositories.CrearIndices(database); err != nil {
+			log.Printf("Error al crear los índices de MongoDB: %v", err)
+		}
+		if err := repositories.MigrarCategorias(database); err != nil {
+			log.Printf("Error al migrar los tipos de alimento a categorías: %v", err)
+		}
+		if err := repositories.MigrarDietas(database); err != nil {
+			log.Printf("Error al deducir las dietas de las recetas: %v", err)
+		}
+	}
 	alimentosRepository = repositories.NewAlimentoRepository(database)
 	recetasRepository = repositories.NewRecetaRepository(database)
 	comprasRepository = repositories.NewCompraRepository(database)
+	coleccionesRepository = repositories.NewColeccionRepository(database)
+	valoracionesRepository = repositories.NewValoracionRepository(database)
+	imagenesRepository = repositories.NewImagenRepository(database)
+	versionesRepository = repositories.NewVersionRecetaRepository(database)
+	sustitucionesRepository = repositories.NewSustitucionRepository(database)
+	momentosRepository = repositories.NewMomentoRepository(database)
+	categoriasRepository = repositories.NewCategoriaRepository(database)
+	sobrasRepository = repositories.NewSobraRepository(database)
+	planesRepository = repositories.NewPlanRepository(database)
+	equipamientoRepository = repositories.NewEquipamientoRepository(database)
+	//Inyectar almacenamiento de archivos
+	directorioImagenes := os.Getenv("IMAGENES_DIR")
+	if directorioImagenes == "" {
+		directorioImagenes = "imagenes"
+	}
+	almacenamiento, err := storage.NewAlmacenamientoLocal(directorioImagenes)
+	if err != nil {
+		log.Fatalf("No se pudo inicializar el almacenamiento de imágenes: %v", err)
+	}
 	//Inyectar servicios
 	alimentosService = service.NewAlimentoService(alimentosRepository)
-	recetasService = service.NewRecetaService(recetasRepository)
+	recetasService = service.NewRecetaService(recetasRepository, almacenamiento)
 	comprasService = service.NewCompraService(comprasRepository)
+	coleccionesService = service.NewColeccionService(coleccionesRepository)
+	valoracionesService = service.NewValoracionService(valoracionesRepository)
+	imagenesService = service.NewImagenService(imagenesRepository, almacenamiento)
+	importacionService = service.NewImportacionService(alimentosRepository, momentosRepository)
+	exportacionService = service.NewExportacionService(recetasRepository, coleccionesRepository, momentosRepository)
+	catalogoService = service.NewCatalogoService(recetasRepository, alimentosRepository, categoriasRepository)
+	versionesService = service.NewVersionRecetaService(versionesRepository, recetasRepository)
+	sustitucionesService = service.NewSustitucionService(sustitucionesRepository)
+	momentosService = service.NewMomentoService(momentosRepository)
+	categoriasService = service.NewCategoriaService(categoriasRepository)
+	sobrasService = service.NewSobraService(sobrasRepository)
+	planesService = service.NewPlanService(planesRepository)
+	equipamientoService = service.NewEquipamientoService(equipamientoRepository)
 	//Inyectar handlers
 	alimentosHandler = handlers.NewAlimentoHandler(alimentosService)
 	recetasHandler = handlers.NewRecetaHandler(recetasService)
 	compraHandler = handlers.NewCompraHandler(comprasService)
+	coleccionHandler = handlers.NewColeccionHandler(coleccionesService)
+	valoracionHandler = handlers.NewValoracionHandler(valoracionesService)
+	imagenHandler = handlers.NewImagenHandler(imagenesService)
+	importacionHandler = handlers.NewImportacionHandler(importacionService)
+	exportacionHandler = handlers.NewExportacionHandler(exportacionService)
+	catalogoHandler = handlers.NewCatalogoHandler(catalogoService)
+	versionHandler = handlers.NewVersionRecetaHandler(versionesService)
+	sustitucionHandler = handlers.NewSustitucionHandler(sustitucionesService)
+	momentoHandler = handlers.NewMomentoHandler(momentosService)
+	categoriaHandler = handlers.NewCategoriaHandler(categoriasService)
+	sobraHandler = handlers.NewSobraHandler(sobrasService)
+	planHandler = handlers.NewPlanHandler(planesService)
+	equipamientoHandler = handlers.NewEquipamientoHandler(equipamientoService)
 
 }
 
@@ -62,8 +160,12 @@ func mappingRoutes() {
 	groupAlimentos := router.Group("/alimentos")
 
 	groupAlimentos.GET("/", alimentosHandler.GetAlimentos)
+	groupAlimentos.GET("/busqueda", alimentosHandler.BuscarAlimentos)
+	groupAlimentos.GET("/autocompletar", alimentosHandler.Autocompletar)
+	groupAlimentos.GET("/duplicados", alimentosHandler.GetDuplicados)
 	groupAlimentos.GET("/:id", alimentosHandler.GetAlimentoByID)
 	groupAlimentos.POST("/", alimentosHandler.InsertAlimento)
+	groupAlimentos.POST("/fusionar", alimentosHandler.FusionarAlimentos)
 	groupAlimentos.PUT("/:id", alimentosHandler.UpdateAlimento)
 	groupAlimentos.DELETE("/:id", alimentosHandler.DeleteAlimento)
 
@@ -73,9 +175,30 @@ func mappingRoutes() {
 	groupRecetas.GET("/", recetasHandler.GetRecetas)
 	groupRecetas.GET("/:id", recetasHandler.GetRecetaByID)
 	groupRecetas.GET("/buscar", recetasHandler.GetRecetasByParameters)
+	groupRecetas.GET("/busqueda", recetasHandler.BuscarRecetas)
+	groupRecetas.GET("/favoritas", valoracionHandler.GetRecetasFavoritas)
+	groupRecetas.GET("/compartidas", recetasHandler.GetRecetasCompartidas)
+	groupRecetas.GET("/recomendadas", recetasHandler.GetRecetasRecomendadas)
 	groupRecetas.POST("/", recetasHandler.InsertReceta)
+	groupRecetas.POST("/import", importacionHandler.ImportarReceta)
+	groupRecetas.POST("/validar", recetasHandler.ValidarReceta)
 	groupRecetas.PUT("/:id", recetasHandler.UpdateReceta)
 	groupRecetas.DELETE("/:id", recetasHandler.DeleteReceta)
+	groupRecetas.GET("/:id/valoracion", valoracionHandler.GetValoracion)
+	groupRecetas.PUT("/:id/valoracion", valoracionHandler.UpsertValoracion)
+	groupRecetas.POST("/:id/notas", valoracionHandler.InsertNota)
+	groupRecetas.DELETE("/:id/notas/:notaId", valoracionHandler.DeleteNota)
+	groupRecetas.POST("/:id/imagenes", imagenHandler.InsertImagen)
+	groupRecetas.GET("/:id/imagenes/:imgId", imagenHandler.GetImagen)
+	groupRecetas.DELETE("/:id/imagenes/:imgId", imagenHandler.DeleteImagen)
+	groupRecetas.GET("/:id/export", exportacionHandler.ExportarReceta)
+	groupRecetas.GET("/:id/costo", recetasHandler.GetCostoReceta)
+	groupRecetas.GET("/:id/ingredientes", recetasHandler.GetIngredientesBase)
+	groupRecetas.GET("/:id/similares", recetasHandler.GetRecetasSimilares)
+	groupRecetas.GET("/:id/versiones", versionHandler.GetVersiones)
+	groupRecetas.GET("/:id/versiones/diff", versionHandler.GetDiff)
+	groupRecetas.GET("/:id/versiones/:v", versionHandler.GetVersion)
+	groupRecetas.POST("/:id/versiones/:v/revertir", versionHandler.Revertir)
 
 	//Ruta compras
 	groupCompras := router.Group("/compras")
@@ -84,6 +207,60 @@ func mappingRoutes() {
 	groupCompras.GET("/productos-cantidad", compraHandler.GetProductosPorCantidadMinima)
 	groupCompras.POST("/", compraHandler.PostNuevaCompra)
 
+	//Ruta colecciones
+	groupColecciones := router.Group("/colecciones")
+
+	groupColecciones.GET("/", coleccionHandler.GetColecciones)
+	groupColecciones.GET("/:id", coleccionHandler.GetColeccionByID)
+	groupColecciones.POST("/", coleccionHandler.InsertColeccion)
+	groupColecciones.PUT("/:id", coleccionHandler.UpdateColeccion)
+	groupColecciones.DELETE("/:id", coleccionHandler.DeleteColeccion)
+	groupColecciones.GET("/:id/export", exportacionHandler.ExportarColeccion)
+
+	groupSustituciones := router.Group("/sustituciones")
+
+	groupSustituciones.GET("/", sustitucionHandler.GetSustituciones)
+	groupSustituciones.POST("/", sustitucionHandler.InsertSustitucion)
+	groupSustituciones.DELETE("/:id", sustitucionHandler.DeleteSustitucion)
+
+	groupMomentos := router.Group("/momentos")
+
+	groupMomentos.GET("/", momentoHandler.GetMomentos)
+	groupMomentos.POST("/", momentoHandler.InsertMomento)
+	groupMomentos.DELETE("/:id", momentoHandler.DeleteMomento)
+
+	groupCategorias := router.Group("/categorias")
+
+	groupCategorias.GET("/", categoriaHandler.GetCategorias)
+	groupCategorias.POST("/", categoriaHandler.InsertCategoria)
+	groupCategorias.DELETE("/:id", categoriaHandler.DeleteCategoria)
+
+	groupSobras := router.Group("/sobras")
+
+	groupSobras.GET("/", sobraHandler.GetSobras)
+	groupSobras.POST("/", sobraHandler.InsertSobra)
+	groupSobras.POST("/:id/comer", sobraHandler.ComerSobra)
+	groupSobras.DELETE("/:id", sobraHandler.DeleteSobra)
+
+	groupPlanes := router.Group("/planes")
+
+	groupPlanes.GET("/", planHandler.GetPlanes)
+	groupPlanes.GET("/:id", planHandler.GetPlanByID)
+	groupPlanes.POST("/generar", planHandler.GenerarPlan)
+	groupPlanes.PUT("/:id/comidas", planHandler.CambiarComida)
+	groupPlanes.POST("/:id/aceptar", planHandler.AceptarPlan)
+	groupPlanes.DELETE("/:id", planHandler.DeletePlan)
+
+	groupEquipamiento := router.Group("/equipamiento")
+
+	groupEquipamiento.GET("/", equipamientoHandler.GetEquipamiento)
+	groupEquipamiento.PUT("/", equipamientoHandler.UpdateEquipamiento)
+
+	groupCatalogo := router.Group("/catalogo")
+
+	groupCatalogo.GET("/", catalogoHandler.GetCatalogo)
+	groupCatalogo.POST("/:id/copiar", catalogoHandler.CopiarReceta)
+
 	groupReportes := router.Group("/reportes")
 
 	groupReportes.GET("/recetas-momento", recetasHandler.GetCantidadRecetasPorMomento)
@@ -8,14 +8,18 @@ import (
 )
 
 type Alimento struct {
-	Id                 primitive.ObjectID `bson:"_id,omitempty"`
-	Nombre             string             `bson:"nombre"`
-	Tipo               utils.TipoComida   `bson:"tipo"`
-	MomentosDeConsumo  []utils.Momento    `bson:"momento"`
-	PrecioUnitario     float64            `bson:"precio_unitario"`
-	CantidadActual     float64            `bson:"cantidad_actual"`
-	CantidadMinima     float64            `bson:"cantidad_minima"`
-	UsuarioID          string             `bson:"id_usuario"`
-	FechaCreacion      time.Time          `bson:"fecha_creacion"`
-	FechaActualizacion time.Time          `bson:"fecha_actualizacion"`
+	Id                 primitive.ObjectID       `bson:"_id,omitempty"`
+	Nombre             string                   `bson:"nombre"`
+	CategoriaID        primitive.ObjectID       `bson:"id_categoria,omitempty"`
+	MomentosDeConsumo  []utils.Momento          `bson:"momento"`
+	Atributos          []utils.AtributoAlimento `bson:"atributos,omitempty"`
+	PrecioUnitario     float64                  `bson:"precio_unitario"`
+	CantidadActual     float64                  `bson:"cantidad_actual"`
+	CantidadMinima     float64                  `bson:"cantidad_minima"`
+	Calorias           float64                  `bson:"calorias,omitempty"`          // Por unidad del alimento, la misma en que se cuenta el stock
+	FechaVencimiento   *time.Time               `bson:"fecha_vencimiento,omitempty"` // Del stock actual, si se conoce
+	Fusionados         []primitive.ObjectID     `bson:"fusionados,omitempty"`        // Alimentos cuyo stock ya se sumó a este al fusionarlos
+	UsuarioID          string                   `bson:"id_usuario"`
+	FechaCreacion      time.Time                `bson:"fecha_creacion"`
+	FechaActualizacion time.Time                `bson:"fecha_actualizacion"`
 }
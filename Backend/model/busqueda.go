@@ -0,0 +1,13 @@
+package model
+
+// RecetaEncontrada es una receta resultado de una búsqueda de texto, con su puntaje de relevancia
+type RecetaEncontrada struct {
+	Receta     `bson:",inline"`
+	Relevancia float64 `bson:"relevancia"`
+}
+
+// AlimentoEncontrado es un alimento resultado de una búsqueda de texto, con su puntaje de relevancia
+type AlimentoEncontrado struct {
+	Alimento   `bson:",inline"`
+	Relevancia float64 `bson:"relevancia"`
+}
@@ -0,0 +1,22 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Categoria agrupa alimentos. Las del sistema las ven todos; cada usuario puede agregar las suyas,
+// también como subcategorías de otras (Carne > Pollo).
+type Categoria struct {
+	Id            primitive.ObjectID  `bson:"_id,omitempty"`
+	Nombre        string              `bson:"nombre"`
+	PadreID       *primitive.ObjectID `bson:"id_padre,omitempty"`
+	Clave         string              `bson:"clave,omitempty"` // Identifica a las del sistema entre migraciones
+	UsuarioID     string              `bson:"id_usuario"`      // Vacío en las del sistema
+	FechaCreacion time.Time           `bson:"fecha_creacion"`
+}
+
+func (categoria Categoria) EsDelSistema() bool {
+	return categoria.UsuarioID == ""
+}
@@ -0,0 +1,17 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Coleccion struct {
+	Id                 primitive.ObjectID   `bson:"_id,omitempty"`
+	Nombre             string               `bson:"nombre"`
+	Descripcion        string               `bson:"descripcion"`
+	Recetas            []primitive.ObjectID `bson:"recetas"` // El orden del slice es el orden de las recetas en la colección
+	UsuarioID          string               `bson:"id_usuario"`
+	FechaCreacion      time.Time            `bson:"fecha_creacion"`
+	FechaActualizacion time.Time            `bson:"fecha_actualizacion"`
+}
@@ -1,7 +1,6 @@
 package model
 
 import (
-	"gocooking-backend/utils"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -17,8 +16,8 @@ type Compra struct {
 }
 
 type ProductoCompra struct {
-	AlimentoId primitive.ObjectID `bson:"id_alimento"`
-	Cantidad   float64            `bson:"cantidad_comprada"`
-	Nombre     string             `bson:"nombre"`
-	Tipo       utils.TipoComida   `bson:"tipo"`
+	AlimentoId  primitive.ObjectID `bson:"id_alimento"`
+	Cantidad    float64            `bson:"cantidad_comprada"`
+	Nombre      string             `bson:"nombre"`
+	CategoriaID primitive.ObjectID `bson:"id_categoria,omitempty"`
 }
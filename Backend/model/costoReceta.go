@@ -0,0 +1,8 @@
+package model
+
+// CostoReceta es lo que cuesta preparar una receta según el precio unitario de sus alimentos
+type CostoReceta struct {
+	Total                 float64
+	PorPorcion            float64
+	IngredientesSinPrecio []string // Sus alimentos no tienen precio o ya no existen, así que no suman al total
+}
@@ -0,0 +1,32 @@
+package model
+
+import (
+	"gocooking-backend/utils"
+	"slices"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Equipamiento son los aparatos de cocina que tiene el usuario; hay uno solo por usuario
+type Equipamiento struct {
+	Id                 primitive.ObjectID `bson:"_id,omitempty"`
+	Equipos            []utils.Equipo     `bson:"equipos"`
+	UsuarioID          string             `bson:"id_usuario"`
+	FechaActualizacion time.Time          `bson:"fecha_actualizacion"`
+}
+
+// Faltante devuelve los equipos requeridos que el usuario no tiene. Si nunca cargó su equipamiento
+// (equipamiento nil) no se sabe qué le falta y no se informa nada.
+func (equipamiento *Equipamiento) Faltante(requeridos []utils.Equipo) []utils.Equipo {
+	if equipamiento == nil {
+		return nil
+	}
+	var faltante []utils.Equipo
+	for _, equipo := range requeridos {
+		if !slices.Contains(equipamiento.Equipos, equipo) {
+			faltante = append(faltante, equipo)
+		}
+	}
+	return faltante
+}
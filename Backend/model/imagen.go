@@ -0,0 +1,24 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Tipos de imagen que se pueden asociar a una receta
+const (
+	ImagenPortada = "portada"
+	ImagenPaso    = "paso"
+)
+
+type Imagen struct {
+	Id             primitive.ObjectID `bson:"_id"`
+	Tipo           string             `bson:"tipo"`
+	Paso           int                `bson:"paso,omitempty"` // Número de paso (empezando en 1) para las imágenes de tipo paso
+	ContentType    string             `bson:"content_type"`
+	Tamano         int                `bson:"tamano"`
+	Clave          string             `bson:"clave"`
+	ClaveMiniatura string             `bson:"clave_miniatura"`
+	FechaCreacion  time.Time          `bson:"fecha_creacion"`
+}
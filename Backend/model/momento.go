@@ -0,0 +1,17 @@
+package model
+
+import (
+	"gocooking-backend/utils"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MomentoPersonalizado es un momento de consumo definido por el usuario (colación, brunch, post-entreno)
+type MomentoPersonalizado struct {
+	Id            primitive.ObjectID `bson:"_id,omitempty"`
+	Codigo        utils.Momento      `bson:"codigo"` // Se usa en recetas y alimentos igual que los predefinidos
+	Nombre        string             `bson:"nombre"`
+	UsuarioID     string             `bson:"id_usuario"`
+	FechaCreacion time.Time          `bson:"fecha_creacion"`
+}
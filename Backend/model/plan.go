@@ -0,0 +1,49 @@
+package model
+
+import (
+	"gocooking-backend/utils"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PlanSemanal es un menú de una semana armado a partir de las recetas del usuario
+type PlanSemanal struct {
+	Id                 primitive.ObjectID `bson:"_id,omitempty"`
+	Estado             utils.EstadoPlan   `bson:"estado"`
+	FechaInicio        time.Time          `bson:"fecha_inicio"` // Primer día del plan
+	Restricciones      RestriccionesPlan  `bson:"restricciones"`
+	Comidas            []ComidaPlan       `bson:"comidas"`       // Ordenadas por día, momento y orden
+	Compras            []ProductoCompra   `bson:"compras"`       // Lo que falta para cumplir el plan con el stock al generarlo
+	CostoCompras       float64            `bson:"costo_compras"` // Costo de Compras
+	UsuarioID          string             `bson:"id_usuario"`
+	FechaCreacion      time.Time          `bson:"fecha_creacion"`
+	FechaActualizacion time.Time          `bson:"fecha_actualizacion"`
+}
+
+// RestriccionesPlan son las condiciones con las que se generó el plan
+type RestriccionesPlan struct {
+	Momentos          []utils.Momento `bson:"momentos"`
+	Personas          int             `bson:"personas"`                      // Porciones que se comen en cada comida
+	MaxRepeticiones   int             `bson:"max_repeticiones,omitempty"`    // Veces que puede aparecer una receta; sin límite si es cero
+	Dietas            []utils.Dieta   `bson:"dietas,omitempty"`              // Las recetas tienen que cumplir todas
+	CaloriasDiarias   float64         `bson:"calorias_diarias,omitempty"`    // Objetivo por persona
+	Presupuesto       float64         `bson:"presupuesto,omitempty"`         // Máximo a gastar en compras
+	RecetasPorMomento int             `bson:"recetas_por_momento,omitempty"` // Platos de cada comida, como principal y guarnición; uno si es cero
+}
+
+// CantidadRecetasPorMomento devuelve cuántas recetas lleva cada comida, también en los planes anteriores
+func (restricciones RestriccionesPlan) CantidadRecetasPorMomento() int {
+	return max(restricciones.RecetasPorMomento, 1)
+}
+
+type ComidaPlan struct {
+	Dia          int                `bson:"dia"` // Desde 0, el día de FechaInicio
+	Momento      utils.Momento      `bson:"momento"`
+	Orden        int                `bson:"orden,omitempty"` // Entre las recetas del mismo momento, desde 0
+	RecetaID     primitive.ObjectID `bson:"id_receta"`
+	NombreReceta string             `bson:"nombre_receta"`
+	Calorias     float64            `bson:"calorias"`             // Por porción, de los alimentos que tienen calorías cargadas
+	CostoCompras float64            `bson:"costo_compras"`        // Lo que hay que comprar para esta comida
+	ConSobras    bool               `bson:"con_sobras,omitempty"` // Se come de sobras guardadas en lugar de cocinar
+}
@@ -8,17 +8,94 @@ import (
 )
 
 type Receta struct {
-	Id                 primitive.ObjectID `bson:"_id,omitempty"`
-	Nombre             string             `bson:"nombre"`
-	MomentoDeConsumo   utils.Momento      `bson:"momento_consumo"`
-	Ingredientes       []Ingrediente      `bson:"ingredientes"`
-	FechaCreacion      time.Time          `bson:"fecha_creacion"`
-	FechaActualizacion time.Time          `bson:"fecha_actualizacion"`
-	UsuarioID          string             `bson:"id_usuario"`
+	Id                      primitive.ObjectID `bson:"_id,omitempty"`
+	Nombre                  string             `bson:"nombre"`
+	MomentoDeConsumo        utils.Momento      `bson:"momento_consumo"` // El primero de MomentosDeConsumo, se mantiene para las recetas anteriores
+	MomentosDeConsumo       []utils.Momento    `bson:"momentos_consumo,omitempty"`
+	Ingredientes            []Ingrediente      `bson:"ingredientes"`
+	Pasos                   []string           `bson:"pasos"`
+	Porciones               int                `bson:"porciones"`
+	TiempoPreparacion       int                `bson:"tiempo_preparacion"` // En minutos
+	TiempoCoccion           int                `bson:"tiempo_coccion"`     // En minutos
+	Etiquetas               []string           `bson:"etiquetas"`
+	Equipamiento            []utils.Equipo     `bson:"equipamiento,omitempty"` // Aparatos necesarios para prepararla
+	Dietas                  []utils.Dieta      `bson:"dietas"`                 // Se deducen de los alimentos al guardar la receta o cambiar sus alimentos
+	Imagenes                []Imagen           `bson:"imagenes"`
+	Visibilidad             utils.Visibilidad  `bson:"visibilidad"`
+	CompartidaCon           []string           `bson:"compartida_con"`                     // Códigos de los usuarios con los que se comparte
+	SinConsumoDeStock       bool               `bson:"sin_consumo_stock,omitempty"`        // Copias del catálogo: no descontaron stock al crearse
+	SustitucionesConsumidas []SustitucionUsada `bson:"sustituciones_consumidas,omitempty"` // Con las que se descontó el stock al crearla, para devolverlo al eliminarla
+	OpcionalesOmitidos      []Ingrediente      `bson:"opcionales_omitidos,omitempty"`      // Opcionales que no había al crearla y no se descontaron
+	IngredientesConsumidos  []Ingrediente      `bson:"ingredientes_consumidos,omitempty"`  // Con subrecetas: los ingredientes de las subrecetas que se descontaron al crearla
+	Sustituciones           []SustitucionUsada `bson:"-"`                                  // Las que harían falta hoy para prepararla, se calculan en los listados
+	Costo                   *CostoReceta       `bson:"-"`                                  // Solo se calcula en los listados que lo piden
+	PorcionesSobrantes      int                `bson:"-"`                                  // Sobras sin vencer, se calculan en los listados de recetas disponibles
+	EquipamientoFaltante    []utils.Equipo     `bson:"-"`                                  // Equipos requeridos que el usuario no tiene, se calculan en los listados
+	FechaCreacion           time.Time          `bson:"fecha_creacion"`
+	FechaActualizacion      time.Time          `bson:"fecha_actualizacion"`
+	UsuarioID               string             `bson:"id_usuario"`
 }
 
 type Ingrediente struct {
 	AlimentoId primitive.ObjectID `bson:"id_alimento"`
+	RecetaId   primitive.ObjectID `bson:"id_receta,omitempty"` // Subreceta usada en lugar de un alimento; la cantidad son porciones
 	Nombre     string             `bson:"nombre"`
 	Cantidad   float64            `bson:"cantidad"`
+	Unidad     string             `bson:"unidad,omitempty"`   // Solo informativa, el stock se descuenta en la unidad del alimento
+	Opcional   bool               `bson:"opcional,omitempty"` // No impide preparar la receta: se descuenta solo si hay stock
+	AGusto     bool               `bson:"a_gusto,omitempty"`  // Sin cantidad fija, como la sal; tampoco impide prepararla
+}
+
+// VisiblePara indica si el usuario puede ver la receta: es el dueño, es pública o se la compartieron
+func (receta Receta) VisiblePara(usuarioID string) bool {
+	switch receta.Visibilidad {
+	case utils.VisibilidadPublica:
+		return true
+	case utils.VisibilidadCompartida:
+		for _, compartida := range receta.CompartidaCon {
+			if compartida == usuarioID {
+				return true
+			}
+		}
+	}
+	return receta.UsuarioID == usuarioID
+}
+
+// TieneSubrecetas indica si algún ingrediente de la receta es otra receta
+func (receta Receta) TieneSubrecetas() bool {
+	for _, ingrediente := range receta.Ingredientes {
+		if ingrediente.EsSubreceta() {
+			return true
+		}
+	}
+	return false
+}
+
+// IngredientesDescontados devuelve los ingredientes con los que se descontó el stock al crear la receta
+func (receta Receta) IngredientesDescontados() []Ingrediente {
+	if len(receta.IngredientesConsumidos) > 0 {
+		return receta.IngredientesConsumidos
+	}
+	return receta.Ingredientes
+}
+
+// Momentos devuelve los momentos de consumo de la receta, incluso de las guardadas con un único momento
+func (receta Receta) Momentos() []utils.Momento {
+	if len(receta.MomentosDeConsumo) > 0 {
+		return receta.MomentosDeConsumo
+	}
+	if receta.MomentoDeConsumo != utils.MomentoDefault {
+		return []utils.Momento{receta.MomentoDeConsumo}
+	}
+	return []utils.Momento{}
+}
+
+// EsSubreceta indica si el ingrediente es otra receta del usuario en lugar de un alimento
+func (ingrediente Ingrediente) EsSubreceta() bool {
+	return !ingrediente.RecetaId.IsZero()
+}
+
+// Obligatorio indica si el ingrediente tiene que estar en stock para poder preparar la receta
+func (ingrediente Ingrediente) Obligatorio() bool {
+	return !ingrediente.Opcional && !ingrediente.AGusto
 }
@@ -0,0 +1,8 @@
+package model
+
+// RecetaRecomendada es una receta sugerida para cocinar primero, con su puntaje y los motivos que lo explican
+type RecetaRecomendada struct {
+	Receta
+	Puntaje float64
+	Motivos []string
+}
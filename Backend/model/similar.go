@@ -0,0 +1,9 @@
+package model
+
+// RecetaSimilar es una receta comparada con otra por los alimentos que usan y sus momentos
+type RecetaSimilar struct {
+	Receta
+	Similitud        float64  // Entre 0 y 1
+	AlimentosEnComun []string // Nombres de los alimentos que usan las dos
+	MismoMomento     bool     // Comparten algún momento de consumo
+}
@@ -0,0 +1,23 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Sobra son porciones ya cocinadas de una receta que quedan guardadas (heladera, freezer) para comer más adelante
+type Sobra struct {
+	Id               primitive.ObjectID `bson:"_id,omitempty"`
+	RecetaID         primitive.ObjectID `bson:"id_receta"`
+	NombreReceta     string             `bson:"nombre_receta"` // Se conserva aunque la receta se elimine después
+	Porciones        int                `bson:"porciones"`     // Las que quedan; la sobra se elimina al comer la última
+	FechaVencimiento time.Time          `bson:"fecha_vencimiento"`
+	UsuarioID        string             `bson:"id_usuario"`
+	FechaCreacion    time.Time          `bson:"fecha_creacion"`
+}
+
+// Vencida indica si la sobra ya no se puede comer en el momento dado
+func (sobra Sobra) Vencida(ahora time.Time) bool {
+	return !ahora.Before(sobra.FechaVencimiento)
+}
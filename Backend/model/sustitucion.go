@@ -0,0 +1,30 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Sustitucion permite usar otro alimento cuando falta stock del original.
+// Proporcion es la cantidad del sustituto que reemplaza a una unidad del original.
+type Sustitucion struct {
+	Id            primitive.ObjectID  `bson:"_id,omitempty"`
+	AlimentoID    primitive.ObjectID  `bson:"id_alimento"`
+	SustitutoID   primitive.ObjectID  `bson:"id_sustituto"`
+	Proporcion    float64             `bson:"proporcion"`
+	Reciproca     bool                `bson:"reciproca"`           // También vale al revés, con la proporción inversa
+	RecetaID      *primitive.ObjectID `bson:"id_receta,omitempty"` // Sin receta la sustitución vale para todas
+	UsuarioID     string              `bson:"id_usuario"`
+	FechaCreacion time.Time           `bson:"fecha_creacion"`
+}
+
+// SustitucionUsada indica que un ingrediente se cubre con un sustituto
+type SustitucionUsada struct {
+	AlimentoId        primitive.ObjectID `bson:"id_alimento"`
+	Nombre            string             `bson:"nombre"`
+	Cantidad          float64            `bson:"cantidad"`
+	SustitutoId       primitive.ObjectID `bson:"id_sustituto"`
+	NombreSustituto   string             `bson:"nombre_sustituto"`
+	CantidadSustituto float64            `bson:"cantidad_sustituto"`
+}
@@ -0,0 +1,34 @@
+package model
+
+import (
+	"gocooking-backend/utils"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Tipos de problema que puede tener una receta antes de guardarla
+const (
+	ProblemaCampo               = "campo"                // Dato inválido o faltante
+	ProblemaAlimentoInexistente = "alimento_inexistente" // El ingrediente usa un alimento que no existe
+	ProblemaSubreceta           = "subreceta"            // La subreceta no existe o la receta termina usándose a sí misma
+	ProblemaMomentoInexistente  = "momento_inexistente"  // Momento personalizado que el usuario no tiene
+	ProblemaMomentoIncompatible = "momento_incompatible" // El alimento no es adecuado para un momento de la receta
+	ProblemaStockInsuficiente   = "stock_insuficiente"   // No alcanza el stock ni hay sustituto
+)
+
+// ProblemaReceta es uno de los motivos por los que una receta no se podría guardar
+type ProblemaReceta struct {
+	Tipo       string
+	Campo      string
+	Indice     *int // Posición en la lista del campo, como el número de ingrediente
+	AlimentoId primitive.ObjectID
+	Momento    utils.Momento
+	Faltante   float64 // Cantidad del alimento que falta para preparar la receta
+	Mensaje    string
+}
+
+// ValidacionReceta es el resultado de revisar una receta sin guardarla
+type ValidacionReceta struct {
+	Problemas     []ProblemaReceta
+	Sustituciones []SustitucionUsada // Las que se usarían con el stock actual
+}
@@ -0,0 +1,26 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Valoracion guarda la opinión de un usuario sobre una receta
+type Valoracion struct {
+	Id                 primitive.ObjectID `bson:"_id,omitempty"`
+	RecetaID           primitive.ObjectID `bson:"id_receta"`
+	UsuarioID          string             `bson:"id_usuario"`
+	Puntuacion         int                `bson:"puntuacion"` // 0 indica que la receta aún no fue puntuada
+	Favorita           bool               `bson:"favorita"`
+	Notas              []Nota             `bson:"notas"`
+	FechaCreacion      time.Time          `bson:"fecha_creacion"`
+	FechaActualizacion time.Time          `bson:"fecha_actualizacion"`
+}
+
+type Nota struct {
+	Id            primitive.ObjectID `bson:"_id"`
+	Texto         string             `bson:"texto"`
+	FechaCoccion  *time.Time         `bson:"fecha_coccion,omitempty"` // Vez en que se cocinó la receta a la que se refiere la nota
+	FechaCreacion time.Time          `bson:"fecha_creacion"`
+}
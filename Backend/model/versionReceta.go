@@ -0,0 +1,18 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// VersionReceta es una foto completa de la receta después de crearla o de cada modificación.
+// La última versión coincide con el estado actual de la receta.
+type VersionReceta struct {
+	Id            primitive.ObjectID `bson:"_id,omitempty"`
+	RecetaID      primitive.ObjectID `bson:"id_receta"`
+	Numero        int                `bson:"numero"` // Empieza en 1 y crece con cada modificación
+	Receta        Receta             `bson:"receta"`
+	UsuarioID     string             `bson:"id_usuario"`
+	FechaCreacion time.Time          `bson:"fecha_creacion"`
+}
@@ -4,11 +4,13 @@ import (
 	"context"
 	"errors"
 	"gocooking-backend/model"
+	"gocooking-backend/utils"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type AlimentoRepositoryInterface interface {
@@ -17,6 +19,9 @@ type AlimentoRepositoryInterface interface {
 	InsertAlimento(alimento model.Alimento) (*mongo.InsertOneResult, error)
 	UpdateAlimento(alimento model.Alimento) (*mongo.UpdateResult, error)
 	DeleteAlimento(id primitive.ObjectID) (*mongo.DeleteResult, error)
+	BuscarAlimentos(usuarioID string, texto string, limite int) ([]model.AlimentoEncontrado, error)
+	GetNombresIngredientesCatalogo() ([]string, error)
+	FusionarAlimentos(destinoID primitive.ObjectID, fusionadosIDs []primitive.ObjectID, usuarioID string) (*model.Alimento, error)
 }
 
 type AlimentoRepository struct {
@@ -76,6 +81,14 @@ func (repository AlimentoRepository) GetAlimentoByID(id primitive.ObjectID) (*mo
 
 func (repository AlimentoRepository) InsertAlimento(alimento model.Alimento) (*mongo.InsertOneResult, error) {
 	alimento.FechaCreacion = time.Now()
+	err := verificarMomentosExistentes(repository.db, alimento.UsuarioID, alimento.MomentosDeConsumo)
+	if err != nil {
+		return nil, err
+	}
+	err = verificarCategoria(repository.db, alimento.UsuarioID, alimento.CategoriaID)
+	if err != nil {
+		return nil, err
+	}
 	collection := repository.db.GetClient().Database("gocooking").Collection("alimentos")
 	resultado, err := collection.InsertOne(context.TODO(), alimento)
 	return resultado, err
@@ -83,6 +96,14 @@ func (repository AlimentoRepository) InsertAlimento(alimento model.Alimento) (*m
 
 func (repository AlimentoRepository) UpdateAlimento(alimento model.Alimento) (*mongo.UpdateResult, error) {
 	alimento.FechaActualizacion = time.Now()
+	err := verificarMomentosExistentes(repository.db, alimento.UsuarioID, alimento.MomentosDeConsumo)
+	if err != nil {
+		return nil, err
+	}
+	err = verificarCategoria(repository.db, alimento.UsuarioID, alimento.CategoriaID)
+	if err != nil {
+		return nil, err
+	}
 	collection := repository.db.GetClient().Database("gocooking").Collection("alimentos")
 
 	filtro := bson.M{"_id": alimento.Id}
@@ -90,11 +111,14 @@ func (repository AlimentoRepository) UpdateAlimento(alimento model.Alimento) (*m
 		"$set": bson.M{
 			"nombre":              alimento.Nombre,
 			"fecha_actualizacion": alimento.FechaActualizacion,
-			"tipo":                alimento.Tipo,
+			"id_categoria":        alimento.CategoriaID,
 			"momento":             alimento.MomentosDeConsumo,
+			"atributos":           alimento.Atributos,
 			"precio_unitario":     alimento.PrecioUnitario,
 			"cantidad_actual":     alimento.CantidadActual,
 			"cantidad_minima":     alimento.CantidadMinima,
+			"calorias":            alimento.Calorias,
+			"fecha_vencimiento":   alimento.FechaVencimiento,
 		},
 	}
 
@@ -108,6 +132,11 @@ func (repository AlimentoRepository) UpdateAlimento(alimento model.Alimento) (*m
 		return nil, errors.New("no se encontró el alimento")
 	}
 
+	// La categoría o los atributos pueden cambiar las dietas de las recetas que lo usan
+	if err := actualizarDietas(repository.db, bson.M{"ingredientes.id_alimento": alimento.Id}); err != nil {
+		return nil, err
+	}
+
 	return resultado, nil
 }
 
@@ -126,5 +155,151 @@ func (repository AlimentoRepository) DeleteAlimento(id primitive.ObjectID) (*mon
 		return nil, errors.New("404")
 	}
 
+	// Las sustituciones que usaban el alimento dejan de tener sentido
+	_, err = repository.db.GetClient().Database("gocooking").Collection("sustituciones").DeleteMany(context.TODO(), bson.M{"$or": bson.A{bson.M{"id_alimento": id}, bson.M{"id_sustituto": id}}})
+	if err != nil {
+		return nil, err
+	}
+
+	// Las recetas que lo usaban como ingrediente obligatorio se quedan sin dietas, porque ya no se pueden
+	// comprobar; en las que era opcional deja de contar
+	if err := actualizarDietas(repository.db, bson.M{"ingredientes.id_alimento": id}); err != nil {
+		return nil, err
+	}
+
 	return resultado, nil
 }
+
+// BuscarAlimentos busca el texto en el nombre de los alimentos del usuario
+func (repository AlimentoRepository) BuscarAlimentos(usuarioID string, texto string, limite int) ([]model.AlimentoEncontrado, error) {
+	alimentos := []model.AlimentoEncontrado{}
+	err := buscarPorTexto(repository.db.GetClient().Database("gocooking").Collection("alimentos"), usuarioID, texto, limite, &alimentos)
+	if err != nil {
+		return nil, err
+	}
+	return alimentos, nil
+}
+
+// GetNombresIngredientesCatalogo devuelve los nombres, sin repetir, de los ingredientes de las recetas públicas
+func (repository AlimentoRepository) GetNombresIngredientesCatalogo() ([]string, error) {
+	collection := repository.db.GetClient().Database("gocooking").Collection("recetas")
+	valores, err := collection.Distinct(context.TODO(), "ingredientes.nombre", bson.M{"visibilidad": utils.VisibilidadPublica})
+	if err != nil {
+		return nil, err
+	}
+	nombres := []string{}
+	for _, valor := range valores {
+		if nombre, esTexto := valor.(string); esTexto && nombre != "" {
+			nombres = append(nombres, nombre)
+		}
+	}
+	return nombres, nil
+}
+
+// FusionarAlimentos pasa el stock y las referencias de los alimentos fusionados al de destino y los elimina.
+// Todo ocurre en una transacción si el servidor las admite (ver enTransaccion): si algo falla, ninguna receta,
+// compra o sustitución queda a medio cambiar. Con un mongod sin réplicas la fusión se puede repetir si falla a
+// mitad de camino: el stock de cada fusionado se suma una sola vez, porque el destino guarda cuáles ya sumó, y
+// las referencias se reescriben de nuevo sin efecto hasta que los fusionados se eliminan al final.
+func (repository AlimentoRepository) FusionarAlimentos(destinoID primitive.ObjectID, fusionadosIDs []primitive.ObjectID, usuarioID string) (*model.Alimento, error) {
+	database := repository.db.GetClient().Database("gocooking")
+	collection := database.Collection("alimentos")
+	var destino model.Alimento
+	err := enTransaccion(repository.db, func(ctx context.Context) error {
+		cursor, err := collection.Find(ctx, bson.M{"_id": bson.M{"$in": append([]primitive.ObjectID{destinoID}, fusionadosIDs...)}, "id_usuario": usuarioID})
+		if err != nil {
+			return err
+		}
+		var alimentos []model.Alimento
+		if err := cursor.All(ctx, &alimentos); err != nil {
+			return err
+		}
+		if len(alimentos) != len(fusionadosIDs)+1 {
+			return errors.New("404")
+		}
+
+		// El destino conserva sus datos, suma el stock de los demás y admite todos sus momentos de consumo.
+		// El stock se suma con $inc para no pisar lo que otra operación haya usado o comprado mientras tanto.
+		for _, alimento := range alimentos {
+			if alimento.Id == destinoID {
+				destino = alimento
+				continue
+			}
+			_, err := collection.UpdateOne(ctx,
+				bson.M{"_id": destinoID, "fusionados": bson.M{"$ne": alimento.Id}},
+				bson.M{
+					"$inc":      bson.M{"cantidad_actual": alimento.CantidadActual},
+					"$addToSet": bson.M{"momento": bson.M{"$each": alimento.MomentosDeConsumo}, "fusionados": alimento.Id},
+					"$set":      bson.M{"fecha_actualizacion": time.Now()},
+				})
+			if err != nil {
+				return err
+			}
+		}
+
+		enFusionados := bson.M{"$in": fusionadosIDs}
+		referencias := []struct {
+			coleccion string
+			filtro    bson.M
+			cambios   bson.M
+			filtros   []interface{}
+		}{
+			{"recetas", bson.M{"ingredientes.id_alimento": enFusionados},
+				bson.M{"ingredientes.$[i].id_alimento": destinoID, "ingredientes.$[i].nombre": destino.Nombre},
+				[]interface{}{bson.M{"i.id_alimento": enFusionados}}},
+			{"recetas", bson.M{"ingredientes_consumidos.id_alimento": enFusionados},
+				bson.M{"ingredientes_consumidos.$[i].id_alimento": destinoID, "ingredientes_consumidos.$[i].nombre": destino.Nombre},
+				[]interface{}{bson.M{"i.id_alimento": enFusionados}}},
+			{"recetas", bson.M{"opcionales_omitidos.id_alimento": enFusionados},
+				bson.M{"opcionales_omitidos.$[o].id_alimento": destinoID, "opcionales_omitidos.$[o].nombre": destino.Nombre},
+				[]interface{}{bson.M{"o.id_alimento": enFusionados}}},
+			{"recetas", bson.M{"sustituciones_consumidas.id_alimento": enFusionados},
+				bson.M{"sustituciones_consumidas.$[s].id_alimento": destinoID, "sustituciones_consumidas.$[s].nombre": destino.Nombre},
+				[]interface{}{bson.M{"s.id_alimento": enFusionados}}},
+			{"recetas", bson.M{"sustituciones_consumidas.id_sustituto": enFusionados},
+				bson.M{"sustituciones_consumidas.$[s].id_sustituto": destinoID, "sustituciones_consumidas.$[s].nombre_sustituto": destino.Nombre},
+				[]interface{}{bson.M{"s.id_sustituto": enFusionados}}},
+			{"versiones_receta", bson.M{"receta.ingredientes.id_alimento": enFusionados},
+				bson.M{"receta.ingredientes.$[i].id_alimento": destinoID, "receta.ingredientes.$[i].nombre": destino.Nombre},
+				[]interface{}{bson.M{"i.id_alimento": enFusionados}}},
+			{"compras", bson.M{"lista_productos.id_alimento": enFusionados},
+				bson.M{"lista_productos.$[p].id_alimento": destinoID, "lista_productos.$[p].nombre": destino.Nombre},
+				[]interface{}{bson.M{"p.id_alimento": enFusionados}}},
+			{"planes", bson.M{"compras.id_alimento": enFusionados},
+				bson.M{"compras.$[p].id_alimento": destinoID, "compras.$[p].nombre": destino.Nombre},
+				[]interface{}{bson.M{"p.id_alimento": enFusionados}}},
+			{"sustituciones", bson.M{"id_alimento": enFusionados}, bson.M{"id_alimento": destinoID}, nil},
+			{"sustituciones", bson.M{"id_sustituto": enFusionados}, bson.M{"id_sustituto": destinoID}, nil},
+		}
+		for _, referencia := range referencias {
+			opciones := options.Update()
+			if referencia.filtros != nil {
+				opciones.SetArrayFilters(options.ArrayFilters{Filters: referencia.filtros})
+			}
+			_, err = database.Collection(referencia.coleccion).UpdateMany(ctx, referencia.filtro, bson.M{"$set": referencia.cambios}, opciones)
+			if err != nil {
+				return err
+			}
+		}
+
+		// Una sustitución entre dos alimentos fusionados quedaría como sustituto de sí mismo
+		_, err = database.Collection("sustituciones").DeleteMany(ctx, bson.M{"id_usuario": usuarioID, "$expr": bson.M{"$eq": bson.A{"$id_alimento", "$id_sustituto"}}})
+		if err != nil {
+			return err
+		}
+		_, err = collection.DeleteMany(ctx, bson.M{"_id": enFusionados})
+		if err != nil {
+			return err
+		}
+		return collection.FindOne(ctx, bson.M{"_id": destinoID}).Decode(&destino)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Las recetas ahora usan el destino, que puede tener otra categoría que los fusionados
+	if err := actualizarDietas(repository.db, bson.M{"ingredientes.id_alimento": destinoID}); err != nil {
+		return nil, err
+	}
+	return &destino, nil
+}
@@ -0,0 +1,161 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"gocooking-backend/model"
+	"gocooking-backend/utils"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type CategoriaRepositoryInterface interface {
+	GetCategorias(usuarioID string) ([]model.Categoria, error)
+	GetCategoriaByID(id primitive.ObjectID) (*model.Categoria, error)
+	InsertCategoria(categoria model.Categoria) (*mongo.InsertOneResult, error)
+	DeleteCategoria(id primitive.ObjectID, usuarioID string) (*mongo.DeleteResult, error)
+}
+
+type CategoriaRepository struct {
+	db DB
+}
+
+func NewCategoriaRepository(db DB) *CategoriaRepository {
+	return &CategoriaRepository{
+		db: db,
+	}
+}
+
+// GetCategorias devuelve las categorías del sistema y las del usuario, ordenadas por nombre
+func (repository CategoriaRepository) GetCategorias(usuarioID string) ([]model.Categoria, error) {
+	return categoriasDelUsuario(repository.db, usuarioID)
+}
+
+func (repository CategoriaRepository) GetCategoriaByID(id primitive.ObjectID) (*model.Categoria, error) {
+	var categoria model.Categoria
+	err := repository.db.GetClient().Database("gocooking").Collection("categorias").FindOne(context.TODO(), bson.M{"_id": id}).Decode(&categoria)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("404")
+		}
+		return nil, err
+	}
+	return &categoria, nil
+}
+
+// InsertCategoria guarda una categoría del usuario. Devuelve "400" si la categoría padre no es visible para el usuario
+// o si ya hay una categoría con el mismo nombre en el mismo nivel.
+func (repository CategoriaRepository) InsertCategoria(categoria model.Categoria) (*mongo.InsertOneResult, error) {
+	categorias, err := categoriasDelUsuario(repository.db, categoria.UsuarioID)
+	if err != nil {
+		return nil, err
+	}
+	if categoria.PadreID != nil && buscarCategoria(categorias, *categoria.PadreID) == nil {
+		return nil, errors.New("400")
+	}
+	for _, existente := range categorias {
+		mismoPadre := (existente.PadreID == nil && categoria.PadreID == nil) ||
+			(existente.PadreID != nil && categoria.PadreID != nil && *existente.PadreID == *categoria.PadreID)
+		if mismoPadre && utils.ClaveNombre(existente.Nombre) == utils.ClaveNombre(categoria.Nombre) {
+			return nil, errors.New("400")
+		}
+	}
+
+	categoria.FechaCreacion = time.Now()
+	return repository.db.GetClient().Database("gocooking").Collection("categorias").InsertOne(context.TODO(), categoria)
+}
+
+// DeleteCategoria elimina una categoría del usuario. Devuelve "400" si tiene subcategorías o alimentos.
+func (repository CategoriaRepository) DeleteCategoria(id primitive.ObjectID, usuarioID string) (*mongo.DeleteResult, error) {
+	database := repository.db.GetClient().Database("gocooking")
+	existe, err := database.Collection("categorias").CountDocuments(context.TODO(), bson.M{"_id": id, "id_usuario": usuarioID})
+	if err != nil {
+		return nil, err
+	}
+	// Las categorías del sistema tampoco se encuentran, porque no son del usuario
+	if existe == 0 {
+		return nil, errors.New("404")
+	}
+
+	subcategorias, err := database.Collection("categorias").CountDocuments(context.TODO(), bson.M{"id_padre": id})
+	if err != nil {
+		return nil, err
+	}
+	alimentos, err := database.Collection("alimentos").CountDocuments(context.TODO(), bson.M{"id_categoria": id})
+	if err != nil {
+		return nil, err
+	}
+	if subcategorias > 0 || alimentos > 0 {
+		return nil, errors.New("400")
+	}
+	return database.Collection("categorias").DeleteOne(context.TODO(), bson.M{"_id": id})
+}
+
+func categoriasDelUsuario(db DB, usuarioID string) ([]model.Categoria, error) {
+	filtro := bson.M{"id_usuario": bson.M{"$in": bson.A{"", usuarioID}}}
+	cursor, err := db.GetClient().Database("gocooking").Collection("categorias").Find(context.TODO(), filtro, options.Find().SetSort(bson.M{"nombre": 1}))
+	if err != nil {
+		return nil, err
+	}
+	categorias := []model.Categoria{}
+	if err := cursor.All(context.TODO(), &categorias); err != nil {
+		return nil, err
+	}
+	return categorias, nil
+}
+
+func buscarCategoria(categorias []model.Categoria, id primitive.ObjectID) *model.Categoria {
+	for i := range categorias {
+		if categorias[i].Id == id {
+			return &categorias[i]
+		}
+	}
+	return nil
+}
+
+// conSubcategorias devuelve la categoría junto con todas las que cuelgan de ella, a cualquier profundidad
+func conSubcategorias(categorias []model.Categoria, id primitive.ObjectID) map[primitive.ObjectID]bool {
+	incluidas := map[primitive.ObjectID]bool{id: true}
+	for agregada := true; agregada; {
+		agregada = false
+		for _, categoria := range categorias {
+			if categoria.PadreID != nil && incluidas[*categoria.PadreID] && !incluidas[categoria.Id] {
+				incluidas[categoria.Id] = true
+				agregada = true
+			}
+		}
+	}
+	return incluidas
+}
+
+// conCategoriasPadre devuelve la categoría seguida de sus ancestros, de la más específica a la más general
+func conCategoriasPadre(categorias []model.Categoria, id primitive.ObjectID) []model.Categoria {
+	var cadena []model.Categoria
+	for categoria := buscarCategoria(categorias, id); categoria != nil && len(cadena) <= len(categorias); {
+		cadena = append(cadena, *categoria)
+		if categoria.PadreID == nil {
+			break
+		}
+		categoria = buscarCategoria(categorias, *categoria.PadreID)
+	}
+	return cadena
+}
+
+// verificarCategoria comprueba que la categoría del alimento sea del sistema o del usuario
+func verificarCategoria(db DB, usuarioID string, id primitive.ObjectID) error {
+	if id.IsZero() {
+		return nil
+	}
+	categorias, err := categoriasDelUsuario(db, usuarioID)
+	if err != nil {
+		return err
+	}
+	if buscarCategoria(categorias, id) == nil {
+		return errors.New("la categoría del alimento no existe")
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"gocooking-backend/model"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type ColeccionRepositoryInterface interface {
+	GetColecciones(usuarioID string) (*[]model.Coleccion, error)
+	GetColeccionByID(id primitive.ObjectID, usuarioID string) (*model.Coleccion, error)
+	InsertColeccion(coleccion model.Coleccion) (*mongo.InsertOneResult, error)
+	UpdateColeccion(coleccion model.Coleccion) (*mongo.UpdateResult, error)
+	DeleteColeccion(id primitive.ObjectID, usuarioID string) (*mongo.DeleteResult, error)
+}
+
+type ColeccionRepository struct {
+	db DB
+}
+
+func NewColeccionRepository(db DB) *ColeccionRepository {
+	return &ColeccionRepository{
+		db: db,
+	}
+}
+
+func (repository ColeccionRepository) GetColecciones(usuarioID string) (*[]model.Coleccion, error) {
+	collection := repository.db.GetClient().Database("gocooking").Collection("colecciones")
+	filtro := bson.M{
+		"id_usuario": usuarioID,
+	}
+	cursor, err := collection.Find(context.TODO(), filtro)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	var colecciones []model.Coleccion
+	for cursor.Next(context.Background()) {
+		var coleccion model.Coleccion
+		err = cursor.Decode(&coleccion)
+		if err != nil {
+			return nil, err
+		}
+		colecciones = append(colecciones, coleccion)
+	}
+	return &colecciones, nil
+}
+
+func (repository ColeccionRepository) GetColeccionByID(id primitive.ObjectID, usuarioID string) (*model.Coleccion, error) {
+	collection := repository.db.GetClient().Database("gocooking").Collection("colecciones")
+
+	var coleccion model.Coleccion
+	err := collection.FindOne(context.TODO(), bson.M{"_id": id, "id_usuario": usuarioID}).Decode(&coleccion)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("404")
+		}
+		return nil, err
+	}
+	return &coleccion, nil
+}
+
+func (repository ColeccionRepository) InsertColeccion(coleccion model.Coleccion) (*mongo.InsertOneResult, error) {
+	coleccion.FechaCreacion = time.Now()
+	err := repository.verificarRecetas(coleccion)
+	if err != nil {
+		return nil, err
+	}
+
+	collection := repository.db.GetClient().Database("gocooking").Collection("colecciones")
+	return collection.InsertOne(context.TODO(), coleccion)
+}
+
+func (repository ColeccionRepository) UpdateColeccion(coleccion model.Coleccion) (*mongo.UpdateResult, error) {
+	coleccion.FechaActualizacion = time.Now()
+	err := repository.verificarRecetas(coleccion)
+	if err != nil {
+		return nil, err
+	}
+
+	collection := repository.db.GetClient().Database("gocooking").Collection("colecciones")
+	filtro := bson.M{"_id": coleccion.Id, "id_usuario": coleccion.UsuarioID}
+	entidad := bson.M{
+		"$set": bson.M{
+			"nombre":              coleccion.Nombre,
+			"descripcion":         coleccion.Descripcion,
+			"recetas":             coleccion.Recetas,
+			"fecha_actualizacion": coleccion.FechaActualizacion,
+		},
+	}
+
+	resultado, err := collection.UpdateOne(context.TODO(), filtro, entidad)
+	if err != nil {
+		return nil, err
+	}
+
+	// Si no se encontró la colección del usuario, devolver un error 404
+	if resultado.MatchedCount == 0 {
+		return nil, errors.New("404")
+	}
+
+	return resultado, nil
+}
+
+func (repository ColeccionRepository) DeleteColeccion(id primitive.ObjectID, usuarioID string) (*mongo.DeleteResult, error) {
+	collection := repository.db.GetClient().Database("gocooking").Collection("colecciones")
+
+	resultado, err := collection.DeleteOne(context.TODO(), bson.M{"_id": id, "id_usuario": usuarioID})
+	if err != nil {
+		return nil, err
+	}
+
+	// Verificar si se eliminó algún documento
+	if resultado.DeletedCount == 0 {
+		return nil, errors.New("404")
+	}
+
+	return resultado, nil
+}
+
+// verificarRecetas controla que todas las recetas de la colección existan y pertenezcan al usuario
+func (repository ColeccionRepository) verificarRecetas(coleccion model.Coleccion) error {
+	if len(coleccion.Recetas) == 0 {
+		return nil
+	}
+
+	filtro := bson.M{
+		"_id":        bson.M{"$in": coleccion.Recetas},
+		"id_usuario": coleccion.UsuarioID,
+	}
+	cantidad, err := repository.db.GetClient().Database("gocooking").Collection("recetas").CountDocuments(context.TODO(), filtro)
+	if err != nil {
+		return err
+	}
+	if int(cantidad) != len(coleccion.Recetas) {
+		return errors.New("400")
+	}
+	return nil
+}
@@ -7,7 +7,6 @@ import (
 	"gocooking-backend/model"
 	"gocooking-backend/utils"
 	"log"
-	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -63,10 +62,10 @@ func (repository CompraRepository) GetProductosPorCantidadMinima(parametros dto.
 
 		// Crear un producto con la cantidad que falta para llegar al mínimo
 		producto := model.ProductoCompra{
-			AlimentoId: alimento.Id,
-			Cantidad:   alimento.CantidadMinima - alimento.CantidadActual,
-			Nombre:     alimento.Nombre,
-			Tipo:       alimento.Tipo,
+			AlimentoId:  alimento.Id,
+			Cantidad:    alimento.CantidadMinima - alimento.CantidadActual,
+			Nombre:      alimento.Nombre,
+			CategoriaID: alimento.CategoriaID,
 		}
 
 		// Agregar el producto a la lista
@@ -76,24 +75,33 @@ func (repository CompraRepository) GetProductosPorCantidadMinima(parametros dto.
 	// Aplicar filtros opcionales en Go, si se proporcionaron
 	var productosFiltrados []model.ProductoCompra
 
+	// La categoría pedida incluye a sus subcategorías
+	var categorias map[primitive.ObjectID]bool
+	if parametros.Categoria != "" {
+		todas, err := categoriasDelUsuario(repository.db, usuarioID)
+		if err != nil {
+			return nil, err
+		}
+		categorias = conSubcategorias(todas, utils.GetObjectIDFromStringID(parametros.Categoria))
+	}
+
 	for _, producto := range productos {
-		// Filtrar por tipo, solo si el parámetro `Tipo` es distinto de 0
-		log.Printf("producto tipo: %v, parametro tipo: %v", producto.Tipo, parametros.Tipo)
-		if parametros.Tipo != 0 && producto.Tipo != utils.TipoComida(parametros.Tipo) {
+		// Filtrar por categoría, solo si se indicó el parámetro `Categoria`
+		if parametros.Categoria != "" && !categorias[producto.CategoriaID] {
 			continue
 		}
 
 		// Filtrar por nombre usando aproximación, solo si el parámetro `Nombre` no está vacío
 		log.Printf("producto nombre: %v, parametro nombre: %v", producto.Nombre, parametros.Nombre)
-		if parametros.Nombre != "" && !strings.Contains(strings.ToLower(producto.Nombre), strings.ToLower(parametros.Nombre)) {
+		if parametros.Nombre != "" && !utils.ContieneTexto(producto.Nombre, parametros.Nombre) {
 			continue
 		}
 
 		productosFiltrados = append(productosFiltrados, producto)
 	}
 
-	// Si no hay filtros adicionales (tipo y nombre), devolvemos todos los productos que cumplen la condición inicial
-	if parametros.Tipo == 0 && parametros.Nombre == "" {
+	// Si no hay filtros adicionales (categoría y nombre), devolvemos todos los productos que cumplen la condición inicial
+	if parametros.Categoria == "" && parametros.Nombre == "" {
 		return &productos, nil
 	}
 
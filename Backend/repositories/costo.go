@@ -0,0 +1,39 @@
+package repositories
+
+import (
+	"gocooking-backend/model"
+	"sort"
+)
+
+// costoDeReceta suma el precio unitario de cada alimento por la cantidad que usa la receta, incluidos
+// los de sus subrecetas en proporción a las porciones usadas
+func costoDeReceta(verificador *verificadorStock, receta model.Receta) (*model.CostoReceta, error) {
+	ingredientes, err := verificador.ingredientes(receta)
+	if err != nil {
+		return nil, err
+	}
+	costo := &model.CostoReceta{IngredientesSinPrecio: []string{}}
+	for _, ingrediente := range ingredientes {
+		alimento, err := verificador.alimento(ingrediente.AlimentoId)
+		if err != nil && err.Error() != "404" {
+			return nil, err
+		}
+		if err != nil || alimento.PrecioUnitario <= 0 {
+			costo.IngredientesSinPrecio = append(costo.IngredientesSinPrecio, ingrediente.Nombre)
+			continue
+		}
+		costo.Total += alimento.PrecioUnitario * ingrediente.Cantidad
+	}
+	costo.PorPorcion = costo.Total
+	if receta.Porciones > 1 {
+		costo.PorPorcion = costo.Total / float64(receta.Porciones)
+	}
+	return costo, nil
+}
+
+// ordenarPorCosto ordena las recetas de la más barata a la más cara por porción; necesita el costo ya calculado
+func ordenarPorCosto(recetas []model.Receta) {
+	sort.SliceStable(recetas, func(i, j int) bool {
+		return recetas[i].Costo.PorPorcion < recetas[j].Costo.PorPorcion
+	})
+}
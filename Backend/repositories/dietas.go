@@ -0,0 +1,185 @@
+package repositories
+
+import (
+	"context"
+	"gocooking-backend/model"
+	"gocooking-backend/utils"
+	"slices"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Claves de las categorías del sistema que dicen algo de una dieta; valen también para sus subcategorías.
+// Una dieta solo se asigna si cada alimento prueba que la cumple: sin categoría ni atributos no se sabe.
+var (
+	clavesCarnicas  = []string{"carne", "pescado"}
+	clavesLacteas   = []string{"lacteo", "queso"}
+	clavesProteicas = []string{"carne", "pescado", "legumbre"}
+	clavesConGluten = []string{"cereal"} // Salvo los marcados sin gluten
+	clavesVegetales = []string{"verdura", "fruta", "legumbre", "cereal", "especia"}
+	clavesSinGluten = []string{"verdura", "fruta", "legumbre", "carne", "pescado", "lacteo", "queso"}
+)
+
+// rasgosAlimento resume lo que importa de un alimento para deducir las dietas: lo que contiene y lo que
+// se puede asegurar que no contiene
+type rasgosAlimento struct {
+	carnico         bool
+	lacteo          bool
+	animal          bool
+	gluten          bool
+	proteico        bool
+	sinCarneSeguro  bool
+	sinLacteoSeguro bool
+	sinAnimalSeguro bool
+	sinGlutenSeguro bool
+}
+
+func rasgosDeAlimento(alimento model.Alimento, categorias []model.Categoria) rasgosAlimento {
+	var claves []string
+	for _, categoria := range conCategoriasPadre(categorias, alimento.CategoriaID) {
+		if categoria.Clave != "" {
+			claves = append(claves, categoria.Clave)
+		}
+	}
+	contiene := func(buscadas []string) bool {
+		return slices.ContainsFunc(claves, func(clave string) bool { return slices.Contains(buscadas, clave) })
+	}
+	tiene := func(atributo utils.AtributoAlimento) bool {
+		return slices.Contains(alimento.Atributos, atributo)
+	}
+
+	rasgos := rasgosAlimento{
+		carnico:  contiene(clavesCarnicas) || tiene(utils.AtributoCarne),
+		lacteo:   contiene(clavesLacteas) || tiene(utils.AtributoLacteo),
+		gluten:   tiene(utils.AtributoContieneGluten) || (contiene(clavesConGluten) && !tiene(utils.AtributoSinGluten)),
+		proteico: contiene(clavesProteicas) || tiene(utils.AtributoRicoEnProteina),
+	}
+	rasgos.animal = rasgos.carnico || rasgos.lacteo || tiene(utils.AtributoOrigenAnimal)
+
+	vegetal := contiene(clavesVegetales)
+	rasgos.sinCarneSeguro = !rasgos.carnico && (vegetal || contiene(clavesLacteas))
+	rasgos.sinLacteoSeguro = !rasgos.lacteo && (vegetal || contiene(clavesCarnicas))
+	rasgos.sinAnimalSeguro = !rasgos.animal && vegetal
+	rasgos.sinGlutenSeguro = !rasgos.gluten && (contiene(clavesSinGluten) || tiene(utils.AtributoSinGluten))
+	return rasgos
+}
+
+// dietasDeReceta deduce las dietas de la receta a partir de las categorías y atributos de sus alimentos,
+// incluidos los de sus subrecetas. Vegetariano, vegano, sin lácteos y sin gluten se asignan solo si todos
+// los alimentos prueban que la cumplen. Si falta el alimento de algún ingrediente obligatorio la receta no
+// tiene dietas; los opcionales que ya no existen no cuentan.
+// Es alta en proteínas si al menos un tercio de sus ingredientes son proteicos.
+func dietasDeReceta(verificador *verificadorStock, categorias []model.Categoria, receta model.Receta) ([]utils.Dieta, error) {
+	ingredientes, err := verificador.ingredientes(receta)
+	if err != nil {
+		return nil, err
+	}
+	dietas := []utils.Dieta{}
+	vegetariana, vegana, sinLacteos, sinGluten := true, true, true, true
+	conocidos, proteicos := 0, 0
+	for _, ingrediente := range ingredientes {
+		alimento, err := verificador.alimento(ingrediente.AlimentoId)
+		if err != nil {
+			if err.Error() != "404" {
+				return nil, err
+			}
+			if ingrediente.Obligatorio() {
+				return dietas, nil
+			}
+			continue
+		}
+		rasgos := rasgosDeAlimento(*alimento, categorias)
+		vegetariana = vegetariana && rasgos.sinCarneSeguro
+		vegana = vegana && rasgos.sinAnimalSeguro
+		sinLacteos = sinLacteos && rasgos.sinLacteoSeguro
+		sinGluten = sinGluten && rasgos.sinGlutenSeguro
+		conocidos++
+		if rasgos.proteico {
+			proteicos++
+		}
+	}
+
+	if conocidos == 0 {
+		return dietas, nil
+	}
+	if vegetariana {
+		dietas = append(dietas, utils.DietaVegetariana)
+	}
+	if vegana {
+		dietas = append(dietas, utils.DietaVegana)
+	}
+	if sinLacteos {
+		dietas = append(dietas, utils.DietaSinLacteos)
+	}
+	if sinGluten {
+		dietas = append(dietas, utils.DietaSinGluten)
+	}
+	if proteicos*3 >= conocidos {
+		dietas = append(dietas, utils.DietaAltaEnProteinas)
+	}
+	return dietas, nil
+}
+
+// calcularDietas deduce las dietas de una receta con los alimentos y categorías de su dueño
+func calcularDietas(db DB, receta model.Receta) ([]utils.Dieta, error) {
+	verificador, err := nuevoVerificadorStock(db, receta.UsuarioID)
+	if err != nil {
+		return nil, err
+	}
+	categorias, err := categoriasDelUsuario(db, receta.UsuarioID)
+	if err != nil {
+		return nil, err
+	}
+	return dietasDeReceta(verificador, categorias, receta)
+}
+
+// actualizarDietas vuelve a deducir y guardar las dietas de las recetas del filtro, por ejemplo las que
+// usan un alimento al que le cambió la categoría, y después las de las recetas que las usan como subreceta.
+func actualizarDietas(db DB, filtro bson.M) error {
+	collection := db.GetClient().Database("gocooking").Collection("recetas")
+	cursor, err := collection.Find(context.TODO(), filtro)
+	if err != nil {
+		return err
+	}
+	var recetas []model.Receta
+	if err := cursor.All(context.TODO(), &recetas); err != nil {
+		return err
+	}
+
+	// Los alimentos y categorías se cargan una vez por dueño
+	verificadores := make(map[string]*verificadorStock)
+	categoriasPorUsuario := make(map[string][]model.Categoria)
+	revisadas := []primitive.ObjectID{}
+	for _, receta := range recetas {
+		verificador, cargado := verificadores[receta.UsuarioID]
+		if !cargado {
+			verificador, err = nuevoVerificadorStock(db, receta.UsuarioID)
+			if err != nil {
+				return err
+			}
+			verificadores[receta.UsuarioID] = verificador
+			categoriasPorUsuario[receta.UsuarioID], err = categoriasDelUsuario(db, receta.UsuarioID)
+			if err != nil {
+				return err
+			}
+		}
+		revisadas = append(revisadas, receta.Id)
+		dietas, err := dietasDeReceta(verificador, categoriasPorUsuario[receta.UsuarioID], receta)
+		if err != nil {
+			return err
+		}
+		if receta.Dietas != nil && slices.Equal(dietas, receta.Dietas) {
+			continue
+		}
+		_, err = collection.UpdateOne(context.TODO(), bson.M{"_id": receta.Id}, bson.M{"$set": bson.M{"dietas": dietas}})
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(revisadas) == 0 {
+		return nil
+	}
+	return actualizarDietas(db, bson.M{"ingredientes.id_receta": bson.M{"$in": revisadas}})
+}
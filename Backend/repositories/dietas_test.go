@@ -0,0 +1,97 @@
+package repositories
+
+import (
+	"gocooking-backend/model"
+	"gocooking-backend/utils"
+	"slices"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// categoriasDePrueba son las categorías del sistema que usan las pruebas de dietas
+func categoriasDePrueba() map[string]model.Categoria {
+	categorias := make(map[string]model.Categoria)
+	for _, clave := range []string{"verdura", "lacteo", "legumbre", "carne", "fruta", "cereal", "especia", "bebida"} {
+		categorias[clave] = model.Categoria{Id: primitive.NewObjectID(), Nombre: clave, Clave: clave}
+	}
+	lacteo := categorias["lacteo"].Id
+	categorias["queso"] = model.Categoria{Id: primitive.NewObjectID(), Nombre: "queso", Clave: "queso", PadreID: &lacteo}
+	return categorias
+}
+
+func TestDietasDeReceta(t *testing.T) {
+	categorias := categoriasDePrueba()
+	var todas []model.Categoria
+	for _, categoria := range categorias {
+		todas = append(todas, categoria)
+	}
+	alimento := func(clave string, atributos ...utils.AtributoAlimento) model.Alimento {
+		return model.Alimento{Id: primitive.NewObjectID(), Nombre: clave, CategoriaID: categorias[clave].Id, Atributos: atributos}
+	}
+
+	casos := []struct {
+		nombre    string
+		alimentos []model.Alimento
+		esperadas []utils.Dieta
+	}{
+		{
+			nombre:    "verduras y legumbres",
+			alimentos: []model.Alimento{alimento("verdura"), alimento("legumbre")},
+			esperadas: []utils.Dieta{utils.DietaVegetariana, utils.DietaVegana, utils.DietaSinLacteos, utils.DietaSinGluten, utils.DietaAltaEnProteinas},
+		},
+		{
+			nombre:    "un cereal tiene gluten",
+			alimentos: []model.Alimento{alimento("cereal"), alimento("verdura")},
+			esperadas: []utils.Dieta{utils.DietaVegetariana, utils.DietaVegana, utils.DietaSinLacteos},
+		},
+		{
+			nombre:    "un cereal marcado sin gluten",
+			alimentos: []model.Alimento{alimento("cereal", utils.AtributoSinGluten), alimento("verdura")},
+			esperadas: []utils.Dieta{utils.DietaVegetariana, utils.DietaVegana, utils.DietaSinLacteos, utils.DietaSinGluten},
+		},
+		{
+			nombre:    "un alimento sin categoría no prueba nada",
+			alimentos: []model.Alimento{{Id: primitive.NewObjectID(), Nombre: "huevo"}, alimento("verdura")},
+			esperadas: []utils.Dieta{},
+		},
+		{
+			nombre:    "el queso es vegetariano pero no vegano",
+			alimentos: []model.Alimento{alimento("queso"), alimento("verdura")},
+			esperadas: []utils.Dieta{utils.DietaVegetariana, utils.DietaSinGluten},
+		},
+		{
+			nombre:    "la carne no es vegetariana",
+			alimentos: []model.Alimento{alimento("carne"), alimento("verdura")},
+			esperadas: []utils.Dieta{utils.DietaSinLacteos, utils.DietaSinGluten, utils.DietaAltaEnProteinas},
+		},
+		{
+			nombre:    "una verdura de origen animal no es vegana",
+			alimentos: []model.Alimento{alimento("verdura", utils.AtributoOrigenAnimal)},
+			esperadas: []utils.Dieta{utils.DietaVegetariana, utils.DietaSinLacteos, utils.DietaSinGluten},
+		},
+		{
+			nombre:    "una bebida no prueba que no tenga gluten ni lácteos",
+			alimentos: []model.Alimento{alimento("bebida")},
+			esperadas: []utils.Dieta{},
+		},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nombre, func(t *testing.T) {
+			verificador := &verificadorStock{alimentos: make(map[primitive.ObjectID]model.Alimento), recetas: make(map[primitive.ObjectID]model.Receta)}
+			receta := model.Receta{Id: primitive.NewObjectID()}
+			for _, alimento := range caso.alimentos {
+				verificador.alimentos[alimento.Id] = alimento
+				receta.Ingredientes = append(receta.Ingredientes, model.Ingrediente{AlimentoId: alimento.Id, Nombre: alimento.Nombre, Cantidad: 1})
+			}
+			dietas, err := dietasDeReceta(verificador, todas, receta)
+			if err != nil {
+				t.Fatalf("error inesperado: %v", err)
+			}
+			if !slices.Equal(dietas, caso.esperadas) {
+				t.Errorf("dietas = %v, se esperaba %v", dietas, caso.esperadas)
+			}
+		})
+	}
+}
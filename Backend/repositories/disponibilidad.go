@@ -0,0 +1,309 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"gocooking-backend/model"
+	"gocooking-backend/utils"
+	"log"
+	"math"
+	"slices"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// verificadorStock resuelve si hay stock para las recetas de un usuario, usando sus sustituciones
+// cuando falta el alimento original. Carga los alimentos y sustituciones una sola vez por operación.
+type verificadorStock struct {
+	db            DB
+	usuarioID     string
+	alimentos     map[primitive.ObjectID]model.Alimento
+	recetas       map[primitive.ObjectID]model.Receta // Subrecetas ya cargadas
+	sustituciones []model.Sustitucion
+}
+
+func nuevoVerificadorStock(db DB, usuarioID string) (*verificadorStock, error) {
+	verificador := &verificadorStock{
+		db:        db,
+		usuarioID: usuarioID,
+		alimentos: make(map[primitive.ObjectID]model.Alimento),
+		recetas:   make(map[primitive.ObjectID]model.Receta),
+	}
+
+	cursor, err := db.GetClient().Database("gocooking").Collection("alimentos").Find(context.TODO(), bson.M{"id_usuario": usuarioID})
+	if err != nil {
+		return nil, err
+	}
+	var alimentos []model.Alimento
+	if err := cursor.All(context.TODO(), &alimentos); err != nil {
+		return nil, err
+	}
+	for _, alimento := range alimentos {
+		verificador.alimentos[alimento.Id] = alimento
+	}
+
+	cursor, err = db.GetClient().Database("gocooking").Collection("sustituciones").Find(context.TODO(), bson.M{"id_usuario": usuarioID})
+	if err != nil {
+		return nil, err
+	}
+	if err := cursor.All(context.TODO(), &verificador.sustituciones); err != nil {
+		return nil, err
+	}
+	return verificador, nil
+}
+
+// alimento busca el alimento entre los del usuario y, si no está, en la base (recetas con alimentos de otro dueño)
+func (verificador *verificadorStock) alimento(id primitive.ObjectID) (*model.Alimento, error) {
+	if alimento, existe := verificador.alimentos[id]; existe {
+		return &alimento, nil
+	}
+	var alimento model.Alimento
+	err := verificador.db.GetClient().Database("gocooking").Collection("alimentos").FindOne(context.TODO(), bson.M{"_id": id}).Decode(&alimento)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("404")
+		}
+		return nil, err
+	}
+	verificador.alimentos[id] = alimento
+	return &alimento, nil
+}
+
+// devolver suma al stock cargado lo que una receta ya había descontado, para resolverla de nuevo como si
+// todavía no se hubiera preparado. Los alimentos que ya no existen se ignoran.
+func (verificador *verificadorStock) devolver(consumo map[primitive.ObjectID]float64) error {
+	for alimentoID, cantidad := range consumo {
+		alimento, err := verificador.alimento(alimentoID)
+		if err != nil {
+			if err.Error() == "404" {
+				continue
+			}
+			return err
+		}
+		alimento.CantidadActual += cantidad
+		verificador.alimentos[alimentoID] = *alimento
+	}
+	return nil
+}
+
+// resolver indica si hay stock para todos los ingredientes obligatorios de la receta, incluidos los
+// de sus subrecetas, y con qué sustituciones.
+// El stock se va descontando mientras se resuelve, para que dos ingredientes no cuenten las mismas unidades.
+// Los opcionales y a gusto se resuelven al final con lo que sobra; los que no tienen stock ni sustituto
+// se devuelven como omitidos en lugar de impedir la receta.
+// Si no alcanza para un obligatorio devuelve el error con el primer alimento que falta.
+func (verificador *verificadorStock) resolver(receta model.Receta) ([]model.SustitucionUsada, []model.Ingrediente, error) {
+	restante := make(map[primitive.ObjectID]float64)
+	disponible := func(alimento *model.Alimento) float64 {
+		if cantidad, existe := restante[alimento.Id]; existe {
+			return cantidad
+		}
+		return alimento.CantidadActual
+	}
+
+	ingredientes, err := verificador.ingredientes(receta)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	usadas := []model.SustitucionUsada{}
+	omitidos := []model.Ingrediente{}
+	for _, obligatorios := range []bool{true, false} {
+		for _, ingrediente := range ingredientes {
+			if ingrediente.Obligatorio() != obligatorios {
+				continue
+			}
+			alimento, err := verificador.alimento(ingrediente.AlimentoId)
+			if err != nil {
+				if err.Error() != "404" {
+					return nil, nil, err
+				}
+				if !obligatorios {
+					omitidos = append(omitidos, ingrediente)
+					continue
+				}
+				return nil, nil, ErrorRecetaInvalida{Motivo: "el alimento " + ingrediente.Nombre + " no existe"}
+			}
+			if disponible(alimento) >= ingrediente.Cantidad {
+				restante[alimento.Id] = disponible(alimento) - ingrediente.Cantidad
+				continue
+			}
+
+			usada, err := verificador.buscarSustituto(receta.Id, *alimento, ingrediente.Cantidad, disponible)
+			if err != nil {
+				return nil, nil, err
+			}
+			if usada == nil {
+				if !obligatorios {
+					omitidos = append(omitidos, ingrediente)
+					continue
+				}
+				return nil, nil, ErrorRecetaInvalida{Motivo: "no hay suficiente cantidad del alimento " + alimento.Nombre}
+			}
+			sustituto, _ := verificador.alimento(usada.SustitutoId)
+			restante[sustituto.Id] = disponible(sustituto) - usada.CantidadSustituto
+			usadas = append(usadas, *usada)
+		}
+	}
+	return usadas, omitidos, nil
+}
+
+// buscarSustituto elige el primer sustituto con stock suficiente, prefiriendo los definidos para la receta
+func (verificador *verificadorStock) buscarSustituto(recetaID primitive.ObjectID, alimento model.Alimento, cantidad float64, disponible func(*model.Alimento) float64) (*model.SustitucionUsada, error) {
+	for _, deLaReceta := range []bool{true, false} {
+		for _, sustitucion := range verificador.sustituciones {
+			if (sustitucion.RecetaID != nil) != deLaReceta || (deLaReceta && *sustitucion.RecetaID != recetaID) {
+				continue
+			}
+
+			var sustitutoID primitive.ObjectID
+			var proporcion float64
+			switch {
+			case sustitucion.AlimentoID == alimento.Id:
+				sustitutoID, proporcion = sustitucion.SustitutoID, sustitucion.Proporcion
+			case sustitucion.Reciproca && sustitucion.SustitutoID == alimento.Id:
+				sustitutoID, proporcion = sustitucion.AlimentoID, 1/sustitucion.Proporcion
+			default:
+				continue
+			}
+
+			sustituto, err := verificador.alimento(sustitutoID)
+			if err != nil {
+				if err.Error() == "404" {
+					continue
+				}
+				return nil, err
+			}
+			if disponible(sustituto) >= cantidad*proporcion {
+				return &model.SustitucionUsada{
+					AlimentoId:        alimento.Id,
+					Nombre:            alimento.Nombre,
+					Cantidad:          cantidad,
+					SustitutoId:       sustituto.Id,
+					NombreSustituto:   sustituto.Nombre,
+					CantidadSustituto: cantidad * proporcion,
+				}, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// verificarMomentos comprueba que los momentos de la receta existan para el usuario y que cada alimento
+// sea adecuado para todos ellos, también los de las subrecetas. Recibe los nombres de los momentos del usuario
+// (ver nombresDeMomentos).
+func verificarMomentos(verificador *verificadorStock, receta model.Receta, nombres map[utils.Momento]string) error {
+	if err := verificarMomentosConNombre(receta.Momentos(), nombres); err != nil {
+		return err
+	}
+	ingredientes, err := verificador.ingredientes(receta)
+	if err != nil {
+		return err
+	}
+	for _, ingrediente := range ingredientes {
+		alimento, err := verificador.alimento(ingrediente.AlimentoId)
+		if err != nil {
+			if err.Error() != "404" {
+				return err
+			}
+			// Un opcional sin alimento se omite al prepararla, no hace falta que sea adecuado
+			if !ingrediente.Obligatorio() {
+				continue
+			}
+			return ErrorRecetaInvalida{Motivo: "el alimento " + ingrediente.Nombre + " no existe"}
+		}
+		for _, momento := range receta.Momentos() {
+			if !slices.Contains(alimento.MomentosDeConsumo, momento) {
+				return ErrorRecetaInvalida{Motivo: "el alimento " + alimento.Nombre + " no es adecuado para el momento de consumo " + nombres[momento]}
+			}
+		}
+	}
+	return nil
+}
+
+// consumoDeStock calcula cuánto se descuenta de cada alimento al preparar la receta con las sustituciones dadas,
+// sin contar los ingredientes opcionales omitidos por falta de stock
+func consumoDeStock(ingredientes []model.Ingrediente, sustituciones []model.SustitucionUsada, omitidos []model.Ingrediente) map[primitive.ObjectID]float64 {
+	consumo := make(map[primitive.ObjectID]float64)
+	for _, ingrediente := range ingredientes {
+		consumo[ingrediente.AlimentoId] += ingrediente.Cantidad
+	}
+	for _, omitido := range omitidos {
+		consumo[omitido.AlimentoId] -= omitido.Cantidad
+	}
+	for _, sustitucion := range sustituciones {
+		consumo[sustitucion.AlimentoId] -= sustitucion.Cantidad
+		consumo[sustitucion.SustitutoId] += sustitucion.CantidadSustituto
+	}
+	for alimentoID, cantidad := range consumo {
+		if cantidad == 0 {
+			delete(consumo, alimentoID)
+		}
+	}
+	return consumo
+}
+
+// diferenciaDeConsumo devuelve cuánto más (positivo) o menos (negativo) se descuenta de cada alimento con el
+// consumo nuevo que con el anterior; los alimentos sin diferencia no se incluyen
+func diferenciaDeConsumo(anterior map[primitive.ObjectID]float64, nuevo map[primitive.ObjectID]float64) map[primitive.ObjectID]float64 {
+	diferencia := make(map[primitive.ObjectID]float64)
+	for alimentoID, cantidad := range nuevo {
+		diferencia[alimentoID] += cantidad
+	}
+	for alimentoID, cantidad := range anterior {
+		diferencia[alimentoID] -= cantidad
+	}
+	for alimentoID, cantidad := range diferencia {
+		if math.Abs(cantidad) < 1e-9 {
+			delete(diferencia, alimentoID)
+		}
+	}
+	return diferencia
+}
+
+// descontarStock aplica la diferencia de consumo al stock de los alimentos. Cada descuento exige que el stock
+// todavía alcance, porque pudo haberse usado en otra operación desde que se verificó; si alguno falla se
+// reponen los ya aplicados y el stock queda como estaba.
+func (verificador *verificadorStock) descontarStock(ctx context.Context, diferencia map[primitive.ObjectID]float64) error {
+	collection := verificador.db.GetClient().Database("gocooking").Collection("alimentos")
+	aplicados := make(map[primitive.ObjectID]float64)
+	for alimentoID, cantidad := range diferencia {
+		filtro := bson.M{"_id": alimentoID}
+		if cantidad > 0 {
+			filtro["cantidad_actual"] = bson.M{"$gte": cantidad}
+		}
+		actualizado, err := collection.UpdateOne(ctx, filtro, bson.M{"$inc": bson.M{"cantidad_actual": -cantidad}})
+		if err != nil {
+			verificador.reponerStock(ctx, aplicados)
+			return errors.New("error al actualizar la cantidad de alimento: " + err.Error())
+		}
+		if actualizado.MatchedCount == 0 && cantidad > 0 {
+			verificador.reponerStock(ctx, aplicados)
+			alimento, _ := verificador.alimento(alimentoID)
+			nombre := alimentoID.Hex()
+			if alimento != nil {
+				nombre = alimento.Nombre
+			}
+			return ErrorRecetaInvalida{Motivo: "no hay suficiente cantidad del alimento " + nombre}
+		}
+		aplicados[alimentoID] = cantidad
+	}
+	return nil
+}
+
+// reponerStock deshace los descuentos de descontarStock. Dentro de una transacción no hace falta, porque se
+// aborta entera. Si la reposición falla solo se registra, para no ocultar el error que la provocó.
+func (verificador *verificadorStock) reponerStock(ctx context.Context, descontado map[primitive.ObjectID]float64) {
+	if enTransaccionActiva(ctx) {
+		return
+	}
+	collection := verificador.db.GetClient().Database("gocooking").Collection("alimentos")
+	for alimentoID, cantidad := range descontado {
+		_, err := collection.UpdateOne(ctx, bson.M{"_id": alimentoID}, bson.M{"$inc": bson.M{"cantidad_actual": cantidad}})
+		if err != nil {
+			log.Printf("No se pudo reponer %v del alimento %s: %v", cantidad, alimentoID.Hex(), err)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package repositories
+
+import (
+	"gocooking-backend/model"
+	"maps"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestDiferenciaDeConsumo(t *testing.T) {
+	harina := primitive.NewObjectID()
+	almidon := primitive.NewObjectID()
+	huevo := primitive.NewObjectID()
+	ingrediente := func(alimentoID primitive.ObjectID, cantidad float64) model.Ingrediente {
+		return model.Ingrediente{AlimentoId: alimentoID, Cantidad: cantidad}
+	}
+	porAlmidon := func(cantidad float64) model.SustitucionUsada {
+		return model.SustitucionUsada{AlimentoId: harina, Cantidad: cantidad, SustitutoId: almidon, CantidadSustituto: cantidad / 2}
+	}
+
+	casos := []struct {
+		nombre               string
+		anteriores, nuevos   []model.Ingrediente
+		sustitucionesAntes   []model.SustitucionUsada
+		sustitucionesDespues []model.SustitucionUsada
+		omitidosAntes        []model.Ingrediente
+		esperada             map[primitive.ObjectID]float64
+	}{
+		{
+			nombre:     "mismas cantidades",
+			anteriores: []model.Ingrediente{ingrediente(harina, 200), ingrediente(huevo, 2)},
+			nuevos:     []model.Ingrediente{ingrediente(harina, 200), ingrediente(huevo, 2)},
+			esperada:   map[primitive.ObjectID]float64{},
+		},
+		{
+			nombre:     "más cantidad descuenta la diferencia",
+			anteriores: []model.Ingrediente{ingrediente(harina, 200)},
+			nuevos:     []model.Ingrediente{ingrediente(harina, 250)},
+			esperada:   map[primitive.ObjectID]float64{harina: 50},
+		},
+		{
+			nombre:     "menos cantidad devuelve la diferencia",
+			anteriores: []model.Ingrediente{ingrediente(harina, 200)},
+			nuevos:     []model.Ingrediente{ingrediente(harina, 120)},
+			esperada:   map[primitive.ObjectID]float64{harina: -80},
+		},
+		{
+			nombre:     "un ingrediente quitado se devuelve y uno agregado se descuenta",
+			anteriores: []model.Ingrediente{ingrediente(harina, 200)},
+			nuevos:     []model.Ingrediente{ingrediente(huevo, 3)},
+			esperada:   map[primitive.ObjectID]float64{harina: -200, huevo: 3},
+		},
+		{
+			nombre:             "deja de usar el sustituto",
+			anteriores:         []model.Ingrediente{ingrediente(harina, 200)},
+			sustitucionesAntes: []model.SustitucionUsada{porAlmidon(200)},
+			nuevos:             []model.Ingrediente{ingrediente(harina, 200)},
+			esperada:           map[primitive.ObjectID]float64{harina: 200, almidon: -100},
+		},
+		{
+			nombre:               "pasa a usar el sustituto con más cantidad",
+			anteriores:           []model.Ingrediente{ingrediente(harina, 200)},
+			nuevos:               []model.Ingrediente{ingrediente(harina, 300)},
+			sustitucionesDespues: []model.SustitucionUsada{porAlmidon(300)},
+			esperada:             map[primitive.ObjectID]float64{harina: -200, almidon: 150},
+		},
+		{
+			nombre:        "un opcional omitido antes y usado ahora se descuenta",
+			anteriores:    []model.Ingrediente{ingrediente(harina, 200), ingrediente(huevo, 1)},
+			omitidosAntes: []model.Ingrediente{ingrediente(huevo, 1)},
+			nuevos:        []model.Ingrediente{ingrediente(harina, 200), ingrediente(huevo, 1)},
+			esperada:      map[primitive.ObjectID]float64{huevo: 1},
+		},
+		{
+			nombre:     "las diferencias de redondeo no cuentan",
+			anteriores: []model.Ingrediente{ingrediente(harina, 0.1), ingrediente(harina, 0.2)},
+			nuevos:     []model.Ingrediente{ingrediente(harina, 0.3)},
+			esperada:   map[primitive.ObjectID]float64{},
+		},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nombre, func(t *testing.T) {
+			anterior := consumoDeStock(caso.anteriores, caso.sustitucionesAntes, caso.omitidosAntes)
+			nuevo := consumoDeStock(caso.nuevos, caso.sustitucionesDespues, nil)
+			diferencia := diferenciaDeConsumo(anterior, nuevo)
+			if !maps.Equal(diferencia, caso.esperada) {
+				t.Errorf("diferencia = %v, se esperaba %v", diferencia, caso.esperada)
+			}
+		})
+	}
+}
+
+func TestDevolverAntesDeResolver(t *testing.T) {
+	harina := model.Alimento{Id: primitive.NewObjectID(), Nombre: "harina"}
+	almidon := model.Alimento{Id: primitive.NewObjectID(), Nombre: "almidón"}
+	sustituciones := []model.Sustitucion{{AlimentoID: harina.Id, SustitutoID: almidon.Id, Proporcion: 0.5}}
+
+	casos := []struct {
+		nombre              string
+		stockHarina         float64
+		stockAlmidon        float64
+		descontado          map[primitive.ObjectID]float64
+		cantidadNueva       float64
+		esperaError         bool
+		esperaSustituciones int
+	}{
+		{
+			nombre:        "la misma cantidad alcanza con lo devuelto",
+			stockHarina:   0,
+			descontado:    map[primitive.ObjectID]float64{harina.Id: 200},
+			cantidadNueva: 200,
+		},
+		{
+			nombre:        "más cantidad alcanza con lo devuelto y el stock",
+			stockHarina:   100,
+			descontado:    map[primitive.ObjectID]float64{harina.Id: 200},
+			cantidadNueva: 300,
+		},
+		{
+			nombre:        "más cantidad que lo devuelto y el stock sin sustituto",
+			stockHarina:   50,
+			descontado:    map[primitive.ObjectID]float64{harina.Id: 200},
+			cantidadNueva: 300,
+			esperaError:   true,
+		},
+		{
+			nombre:              "más cantidad se cubre con el sustituto",
+			stockHarina:         50,
+			stockAlmidon:        200,
+			descontado:          map[primitive.ObjectID]float64{harina.Id: 200},
+			cantidadNueva:       300,
+			esperaSustituciones: 1,
+		},
+		{
+			nombre:        "el sustituto devuelto vuelve a estar disponible",
+			stockHarina:   0,
+			stockAlmidon:  0,
+			descontado:    map[primitive.ObjectID]float64{almidon.Id: 100},
+			cantidadNueva: 200,
+			// Lo devuelto es almidón: la harina se cubre con él
+			esperaSustituciones: 1,
+		},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nombre, func(t *testing.T) {
+			conHarina, conAlmidon := harina, almidon
+			conHarina.CantidadActual = caso.stockHarina
+			conAlmidon.CantidadActual = caso.stockAlmidon
+			verificador := &verificadorStock{
+				alimentos:     map[primitive.ObjectID]model.Alimento{harina.Id: conHarina, almidon.Id: conAlmidon},
+				recetas:       make(map[primitive.ObjectID]model.Receta),
+				sustituciones: sustituciones,
+			}
+			if err := verificador.devolver(caso.descontado); err != nil {
+				t.Fatalf("error inesperado al devolver: %v", err)
+			}
+
+			receta := model.Receta{Id: primitive.NewObjectID(), Ingredientes: []model.Ingrediente{{AlimentoId: harina.Id, Nombre: "harina", Cantidad: caso.cantidadNueva}}}
+			usadas, _, err := verificador.resolver(receta)
+			if caso.esperaError {
+				if err == nil {
+					t.Fatal("se esperaba un error por falta de stock")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error inesperado: %v", err)
+			}
+			if len(usadas) != caso.esperaSustituciones {
+				t.Errorf("sustituciones = %v, se esperaban %d", usadas, caso.esperaSustituciones)
+			}
+		})
+	}
+}
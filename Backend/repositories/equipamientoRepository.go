@@ -0,0 +1,102 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"gocooking-backend/model"
+	"gocooking-backend/utils"
+	"slices"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type EquipamientoRepositoryInterface interface {
+	GetEquipamiento(usuarioID string) (*model.Equipamiento, error)
+	UpdateEquipamiento(equipamiento model.Equipamiento) (*model.Equipamiento, error)
+}
+
+type EquipamientoRepository struct {
+	db DB
+}
+
+func NewEquipamientoRepository(db DB) *EquipamientoRepository {
+	return &EquipamientoRepository{
+		db: db,
+	}
+}
+
+// GetEquipamiento devuelve los equipos del usuario, o "404" si todavía no los cargó
+func (repository EquipamientoRepository) GetEquipamiento(usuarioID string) (*model.Equipamiento, error) {
+	equipamiento, err := equipamientoDelUsuario(repository.db, usuarioID)
+	if err != nil {
+		return nil, err
+	}
+	if equipamiento == nil {
+		return nil, errors.New("404")
+	}
+	return equipamiento, nil
+}
+
+// UpdateEquipamiento reemplaza los equipos del usuario, creando su equipamiento la primera vez
+func (repository EquipamientoRepository) UpdateEquipamiento(equipamiento model.Equipamiento) (*model.Equipamiento, error) {
+	filtro := bson.M{"id_usuario": equipamiento.UsuarioID}
+	actualizacion := bson.M{"$set": bson.M{"equipos": equipamiento.Equipos, "fecha_actualizacion": time.Now()}}
+	opciones := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	var actualizado model.Equipamiento
+	err := repository.db.GetClient().Database("gocooking").Collection("equipamiento").FindOneAndUpdate(context.TODO(), filtro, actualizacion, opciones).Decode(&actualizado)
+	if err != nil {
+		return nil, err
+	}
+	return &actualizado, nil
+}
+
+// equipamientoDelUsuario devuelve el equipamiento cargado por el usuario, o nil si nunca lo cargó
+func equipamientoDelUsuario(db DB, usuarioID string) (*model.Equipamiento, error) {
+	var equipamiento model.Equipamiento
+	err := db.GetClient().Database("gocooking").Collection("equipamiento").FindOne(context.TODO(), bson.M{"id_usuario": usuarioID}).Decode(&equipamiento)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &equipamiento, nil
+}
+
+// equipamiento devuelve los equipos que requiere la receta, incluidos los de sus subrecetas obligatorias,
+// que se preparan junto con ella. Si una subreceta ya no existe se devuelven los de la receta sola.
+func (verificador *verificadorStock) equipamiento(receta model.Receta) []utils.Equipo {
+	return verificador.juntarEquipamiento(receta, []utils.Equipo{}, map[primitive.ObjectID]bool{receta.Id: true})
+}
+
+func (verificador *verificadorStock) juntarEquipamiento(receta model.Receta, equipos []utils.Equipo, enCurso map[primitive.ObjectID]bool) []utils.Equipo {
+	for _, equipo := range receta.Equipamiento {
+		if !slices.Contains(equipos, equipo) {
+			equipos = append(equipos, equipo)
+		}
+	}
+	for _, ingrediente := range receta.Ingredientes {
+		if !ingrediente.EsSubreceta() || !ingrediente.Obligatorio() || enCurso[ingrediente.RecetaId] {
+			continue
+		}
+		subreceta, err := verificador.receta(ingrediente.RecetaId)
+		if err != nil {
+			continue
+		}
+		enCurso[subreceta.Id] = true
+		equipos = verificador.juntarEquipamiento(*subreceta, equipos, enCurso)
+		delete(enCurso, subreceta.Id)
+	}
+	return equipos
+}
+
+// marcarEquipamientoFaltante marca en la receta los equipos que requiere y el usuario no tiene. Devuelve falso
+// si le falta alguno y se pidieron solo las recetas que se pueden preparar con el equipamiento del usuario.
+func (verificador *verificadorStock) marcarEquipamientoFaltante(receta *model.Receta, equipamiento *model.Equipamiento, soloConEquipamiento bool) bool {
+	receta.EquipamientoFaltante = equipamiento.Faltante(verificador.equipamiento(*receta))
+	return !soloConEquipamiento || len(receta.EquipamientoFaltante) == 0
+}
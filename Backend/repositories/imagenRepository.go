@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"gocooking-backend/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type ImagenRepositoryInterface interface {
+	GetRecetaDelUsuario(recetaID primitive.ObjectID, usuarioID string) (*model.Receta, error)
+	InsertImagen(recetaID primitive.ObjectID, imagen model.Imagen) (*mongo.UpdateResult, error)
+	DeleteImagen(recetaID primitive.ObjectID, imagenID primitive.ObjectID) (*mongo.UpdateResult, error)
+}
+
+type ImagenRepository struct {
+	db DB
+}
+
+func NewImagenRepository(db DB) *ImagenRepository {
+	return &ImagenRepository{
+		db: db,
+	}
+}
+
+func (repository ImagenRepository) GetRecetaDelUsuario(recetaID primitive.ObjectID, usuarioID string) (*model.Receta, error) {
+	var receta model.Receta
+	err := repository.db.GetClient().Database("gocooking").Collection("recetas").FindOne(context.TODO(), bson.M{"_id": recetaID, "id_usuario": usuarioID}).Decode(&receta)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("404")
+		}
+		return nil, err
+	}
+	return &receta, nil
+}
+
+func (repository ImagenRepository) InsertImagen(recetaID primitive.ObjectID, imagen model.Imagen) (*mongo.UpdateResult, error) {
+	resultado, err := repository.db.GetClient().Database("gocooking").Collection("recetas").UpdateOne(context.TODO(), bson.M{"_id": recetaID}, bson.M{"$push": bson.M{"imagenes": imagen}})
+	if err != nil {
+		return nil, err
+	}
+	if resultado.MatchedCount == 0 {
+		return nil, errors.New("404")
+	}
+	return resultado, nil
+}
+
+func (repository ImagenRepository) DeleteImagen(recetaID primitive.ObjectID, imagenID primitive.ObjectID) (*mongo.UpdateResult, error) {
+	filtro := bson.M{"_id": recetaID, "imagenes._id": imagenID}
+	resultado, err := repository.db.GetClient().Database("gocooking").Collection("recetas").UpdateOne(context.TODO(), filtro, bson.M{"$pull": bson.M{"imagenes": bson.M{"_id": imagenID}}})
+	if err != nil {
+		return nil, err
+	}
+	if resultado.MatchedCount == 0 {
+		return nil, errors.New("404")
+	}
+	return resultado, nil
+}
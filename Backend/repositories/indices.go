@@ -0,0 +1,79 @@
+package repositories
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CrearIndices crea los índices que usan las búsquedas y el que evita equipamientos repetidos. Si ya existen, Mongo no hace nada.
+// Los índices de texto en español aplican stemming y no distinguen mayúsculas ni acentos.
+func CrearIndices(db DB) error {
+	database := db.GetClient().Database("gocooking")
+
+	_, err := database.Collection("recetas").Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "nombre", Value: "text"},
+			{Key: "ingredientes.nombre", Value: "text"},
+			{Key: "pasos", Value: "text"},
+		},
+		Options: options.Index().
+			SetName("busqueda_recetas").
+			SetDefaultLanguage("spanish").
+			SetLanguageOverride("idioma_busqueda").
+			SetWeights(bson.M{"nombre": 10, "ingredientes.nombre": 5, "pasos": 1}),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = database.Collection("alimentos").Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys: bson.D{{Key: "nombre", Value: "text"}},
+		Options: options.Index().
+			SetName("busqueda_alimentos").
+			SetDefaultLanguage("spanish").
+			SetLanguageOverride("idioma_busqueda"),
+	})
+	if err != nil {
+		return err
+	}
+
+	// Cada usuario tiene un solo equipamiento, que se crea con un upsert
+	_, err = database.Collection("equipamiento").Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "id_usuario", Value: 1}},
+		Options: options.Index().SetName("equipamiento_usuario").SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	// Dos modificaciones simultáneas de una receta no pueden registrar el mismo número de versión. Va al
+	// final porque falla si el historial ya tiene números repetidos, y no debe impedir crear los demás.
+	_, err = database.Collection("versiones_receta").Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "id_receta", Value: 1}, {Key: "numero", Value: 1}},
+		Options: options.Index().SetName("versiones_receta_numero").SetUnique(true),
+	})
+	return err
+}
+
+// buscarPorTexto arma la consulta $text del usuario ordenada por relevancia
+func buscarPorTexto(collection *mongo.Collection, usuarioID string, texto string, limite int, resultado interface{}) error {
+	filtro := bson.M{
+		"id_usuario": usuarioID,
+		"$text":      bson.M{"$search": texto},
+	}
+	relevancia := bson.M{"$meta": "textScore"}
+	opciones := options.Find().
+		SetProjection(bson.M{"relevancia": relevancia}).
+		SetSort(bson.D{{Key: "relevancia", Value: relevancia}}).
+		SetLimit(int64(limite))
+
+	cursor, err := collection.Find(context.TODO(), filtro, opciones)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(context.TODO())
+	return cursor.All(context.TODO(), resultado)
+}
@@ -0,0 +1,115 @@
+package repositories
+
+import (
+	"context"
+	"gocooking-backend/utils"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// categoriaDelSistema describe una categoría que existe para todos los usuarios
+type categoriaDelSistema struct {
+	clave      string
+	nombre     string
+	clavePadre string
+	tipo       int // Valor del antiguo enum TipoComida que se migra a esta categoría, 0 si no había
+}
+
+// Las categorías padre van antes que sus hijas
+var categoriasDelSistema = []categoriaDelSistema{
+	{clave: "verdura", nombre: "Verdura", tipo: 1},
+	{clave: "lacteo", nombre: "Lácteo", tipo: 2},
+	{clave: "queso", nombre: "Queso", clavePadre: "lacteo", tipo: 3},
+	{clave: "legumbre", nombre: "Legumbre", tipo: 4},
+	{clave: "carne", nombre: "Carne", tipo: 5},
+	{clave: "fruta", nombre: "Fruta", tipo: 6},
+	{clave: "pescado", nombre: "Pescado"},
+	{clave: "cereal", nombre: "Cereal"},
+	{clave: "especia", nombre: "Especia"},
+	{clave: "bebida", nombre: "Bebida"},
+}
+
+// MigrarCategorias crea las categorías del sistema que falten y pasa los alimentos y compras que todavía
+// tienen el tipo numérico a la categoría equivalente. Se puede ejecutar en cada inicio.
+func MigrarCategorias(db DB) error {
+	database := db.GetClient().Database("gocooking")
+	ids := make(map[string]primitive.ObjectID)
+	for _, categoria := range categoriasDelSistema {
+		valores := bson.M{"nombre": categoria.nombre, "fecha_creacion": time.Now()}
+		if categoria.clavePadre != "" {
+			valores["id_padre"] = ids[categoria.clavePadre]
+		}
+		filtro := bson.M{"clave": categoria.clave, "id_usuario": ""}
+		_, err := database.Collection("categorias").UpdateOne(context.TODO(), filtro, bson.M{"$setOnInsert": valores}, options.Update().SetUpsert(true))
+		if err != nil {
+			return err
+		}
+		var guardada struct {
+			Id primitive.ObjectID `bson:"_id"`
+		}
+		if err := database.Collection("categorias").FindOne(context.TODO(), filtro).Decode(&guardada); err != nil {
+			return err
+		}
+		ids[categoria.clave] = guardada.Id
+	}
+
+	for _, categoria := range categoriasDelSistema {
+		if categoria.tipo == 0 {
+			continue
+		}
+		resultado, err := database.Collection("alimentos").UpdateMany(context.TODO(),
+			bson.M{"tipo": categoria.tipo},
+			bson.M{"$set": bson.M{"id_categoria": ids[categoria.clave]}, "$unset": bson.M{"tipo": ""}})
+		if err != nil {
+			return err
+		}
+		if resultado.ModifiedCount > 0 {
+			log.Printf("Migrados %d alimentos del tipo %d a la categoría %s", resultado.ModifiedCount, categoria.tipo, categoria.nombre)
+		}
+
+		_, err = database.Collection("compras").UpdateMany(context.TODO(),
+			bson.M{"lista_productos.tipo": categoria.tipo},
+			bson.M{"$set": bson.M{"lista_productos.$[p].id_categoria": ids[categoria.clave]}, "$unset": bson.M{"lista_productos.$[p].tipo": ""}},
+			options.Update().SetArrayFilters(options.ArrayFilters{Filters: []interface{}{bson.M{"p.tipo": categoria.tipo}}}))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// migracionDietas identifica en la colección migraciones la revisión de dietas ya hecha. Si cambian las reglas
+// de dietasDeReceta hay que cambiarla para que las recetas guardadas se vuelvan a revisar.
+const migracionDietas = "dietas_v2"
+
+// MigrarDietas deduce las dietas de las recetas guardadas antes de que existieran y vuelve a revisar las que
+// tienen dietas que exigen pruebas, que antes se asignaban a falta de datos en contra. Se ejecuta una sola vez:
+// al terminar se registra en la colección migraciones y los inicios siguientes no hacen nada.
+func MigrarDietas(db DB) error {
+	migraciones := db.GetClient().Database("gocooking").Collection("migraciones")
+	hechas, err := migraciones.CountDocuments(context.TODO(), bson.M{"_id": migracionDietas})
+	if err != nil {
+		return err
+	}
+	if hechas > 0 {
+		return nil
+	}
+
+	err = actualizarDietas(db, bson.M{"$or": bson.A{
+		bson.M{"dietas": bson.M{"$exists": false}},
+		bson.M{"dietas": bson.M{"$in": bson.A{utils.DietaVegetariana, utils.DietaVegana, utils.DietaSinLacteos, utils.DietaSinGluten}}},
+	}})
+	if err != nil {
+		return err
+	}
+	_, err = migraciones.UpdateOne(context.TODO(), bson.M{"_id": migracionDietas},
+		bson.M{"$setOnInsert": bson.M{"fecha": time.Now()}}, options.Update().SetUpsert(true))
+	if err == nil {
+		log.Printf("Revisadas las dietas de las recetas (migración %s)", migracionDietas)
+	}
+	return err
+}
@@ -0,0 +1,137 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"gocooking-backend/model"
+	"gocooking-backend/utils"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MomentoRepositoryInterface interface {
+	GetMomentos(usuarioID string) ([]model.MomentoPersonalizado, error)
+	InsertMomento(momento model.MomentoPersonalizado) (*model.MomentoPersonalizado, error)
+	DeleteMomento(id primitive.ObjectID, usuarioID string) (*mongo.DeleteResult, error)
+}
+
+type MomentoRepository struct {
+	db DB
+}
+
+func NewMomentoRepository(db DB) *MomentoRepository {
+	return &MomentoRepository{
+		db: db,
+	}
+}
+
+// GetMomentos devuelve los momentos personalizados del usuario, en el orden en que los creó
+func (repository MomentoRepository) GetMomentos(usuarioID string) ([]model.MomentoPersonalizado, error) {
+	return momentosPersonalizados(repository.db, usuarioID)
+}
+
+// InsertMomento guarda el momento con el siguiente código libre del usuario. Devuelve "400" si ya tiene uno con ese nombre.
+func (repository MomentoRepository) InsertMomento(momento model.MomentoPersonalizado) (*model.MomentoPersonalizado, error) {
+	momentos, err := momentosPersonalizados(repository.db, momento.UsuarioID)
+	if err != nil {
+		return nil, err
+	}
+	momento.Codigo = utils.PrimerMomentoPersonalizado
+	for _, existente := range momentos {
+		if utils.ClaveNombre(existente.Nombre) == utils.ClaveNombre(momento.Nombre) {
+			return nil, errors.New("400")
+		}
+		if existente.Codigo >= momento.Codigo {
+			momento.Codigo = existente.Codigo + 1
+		}
+	}
+
+	momento.FechaCreacion = time.Now()
+	resultado, err := repository.db.GetClient().Database("gocooking").Collection("momentos").InsertOne(context.TODO(), momento)
+	if err != nil {
+		return nil, err
+	}
+	momento.Id = resultado.InsertedID.(primitive.ObjectID)
+	return &momento, nil
+}
+
+// DeleteMomento elimina el momento y lo quita de los alimentos. Devuelve "400" si alguna receta todavía lo usa.
+func (repository MomentoRepository) DeleteMomento(id primitive.ObjectID, usuarioID string) (*mongo.DeleteResult, error) {
+	database := repository.db.GetClient().Database("gocooking")
+	var momento model.MomentoPersonalizado
+	err := database.Collection("momentos").FindOne(context.TODO(), bson.M{"_id": id, "id_usuario": usuarioID}).Decode(&momento)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("404")
+		}
+		return nil, err
+	}
+
+	enUso, err := database.Collection("recetas").CountDocuments(context.TODO(), bson.M{
+		"id_usuario": usuarioID,
+		"$or":        bson.A{bson.M{"momentos_consumo": momento.Codigo}, bson.M{"momento_consumo": momento.Codigo}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if enUso > 0 {
+		return nil, errors.New("400")
+	}
+
+	_, err = database.Collection("alimentos").UpdateMany(context.TODO(), bson.M{"id_usuario": usuarioID}, bson.M{"$pull": bson.M{"momento": momento.Codigo}})
+	if err != nil {
+		return nil, err
+	}
+	return database.Collection("momentos").DeleteOne(context.TODO(), bson.M{"_id": id})
+}
+
+func momentosPersonalizados(db DB, usuarioID string) ([]model.MomentoPersonalizado, error) {
+	cursor, err := db.GetClient().Database("gocooking").Collection("momentos").Find(context.TODO(), bson.M{"id_usuario": usuarioID}, options.Find().SetSort(bson.M{"codigo": 1}))
+	if err != nil {
+		return nil, err
+	}
+	momentos := []model.MomentoPersonalizado{}
+	if err := cursor.All(context.TODO(), &momentos); err != nil {
+		return nil, err
+	}
+	return momentos, nil
+}
+
+// nombresDeMomentos devuelve el nombre de cada momento que puede usar el usuario: los predefinidos y los suyos
+func nombresDeMomentos(db DB, usuarioID string) (map[utils.Momento]string, error) {
+	momentos, err := momentosPersonalizados(db, usuarioID)
+	if err != nil {
+		return nil, err
+	}
+	nombres := make(map[utils.Momento]string)
+	for _, momento := range utils.MomentosPredefinidos() {
+		nombres[momento] = momento.String()
+	}
+	for _, momento := range momentos {
+		nombres[momento.Codigo] = momento.Nombre
+	}
+	return nombres, nil
+}
+
+// verificarMomentosExistentes comprueba que los momentos personalizados indicados sean del usuario
+func verificarMomentosExistentes(db DB, usuarioID string, momentos []utils.Momento) error {
+	nombres, err := nombresDeMomentos(db, usuarioID)
+	if err != nil {
+		return err
+	}
+	return verificarMomentosConNombre(momentos, nombres)
+}
+
+func verificarMomentosConNombre(momentos []utils.Momento, nombres map[utils.Momento]string) error {
+	for _, momento := range momentos {
+		if _, existe := nombres[momento]; !existe {
+			return ErrorRecetaInvalida{Motivo: "el momento de consumo " + strconv.Itoa(int(momento)) + " no existe"}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"gocooking-backend/model"
+	"gocooking-backend/utils"
+	"math"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type PlanRepositoryInterface interface {
+	GetPlanes(usuarioID string) ([]model.PlanSemanal, error)
+	GetPlanByID(id primitive.ObjectID, usuarioID string) (*model.PlanSemanal, error)
+	GenerarPlan(plan model.PlanSemanal) (*model.PlanSemanal, error)
+	CambiarComida(plan model.PlanSemanal, dia int, momento utils.Momento, orden int, recetaID primitive.ObjectID) (*model.PlanSemanal, error)
+	AceptarPlan(id primitive.ObjectID, usuarioID string) (*model.PlanSemanal, error)
+	DeletePlan(id primitive.ObjectID, usuarioID string) (*mongo.DeleteResult, error)
+}
+
+type PlanRepository struct {
+	db DB
+}
+
+func NewPlanRepository(db DB) *PlanRepository {
+	return &PlanRepository{
+		db: db,
+	}
+}
+
+// GetPlanes devuelve los planes del usuario, primero los que empiezan más tarde
+func (repository PlanRepository) GetPlanes(usuarioID string) ([]model.PlanSemanal, error) {
+	collection := repository.db.GetClient().Database("gocooking").Collection("planes")
+	cursor, err := collection.Find(context.TODO(), bson.M{"id_usuario": usuarioID}, options.Find().SetSort(bson.M{"fecha_inicio": -1}))
+	if err != nil {
+		return nil, err
+	}
+	planes := []model.PlanSemanal{}
+	if err := cursor.All(context.TODO(), &planes); err != nil {
+		return nil, err
+	}
+	return planes, nil
+}
+
+func (repository PlanRepository) GetPlanByID(id primitive.ObjectID, usuarioID string) (*model.PlanSemanal, error) {
+	collection := repository.db.GetClient().Database("gocooking").Collection("planes")
+	var plan model.PlanSemanal
+	err := collection.FindOne(context.TODO(), bson.M{"_id": id, "id_usuario": usuarioID}).Decode(&plan)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("404")
+		}
+		return nil, err
+	}
+	return &plan, nil
+}
+
+// GenerarPlan arma la semana con las recetas propias del usuario según las restricciones del plan y
+// la guarda como borrador. Las compras se calculan con el stock y las sobras de hoy; generar el plan
+// no descuenta nada. Devuelve ErrorRestriccionesPlan si alguna comida no se puede cubrir.
+func (repository PlanRepository) GenerarPlan(plan model.PlanSemanal) (*model.PlanSemanal, error) {
+	nombres, err := nombresDeMomentos(repository.db, plan.UsuarioID)
+	if err != nil {
+		return nil, err
+	}
+	if err := verificarMomentosConNombre(plan.Restricciones.Momentos, nombres); err != nil {
+		return nil, ErrorRestriccionesPlan{Motivo: err.Error()}
+	}
+
+	cursor, err := repository.db.GetClient().Database("gocooking").Collection("recetas").Find(context.TODO(), bson.M{"id_usuario": plan.UsuarioID})
+	if err != nil {
+		return nil, err
+	}
+	var recetas []model.Receta
+	if err := cursor.All(context.TODO(), &recetas); err != nil {
+		return nil, err
+	}
+
+	planificador, err := nuevoPlanificador(repository.db, plan)
+	if err != nil {
+		return nil, err
+	}
+	plan.Comidas, err = planificador.generar(recetas, nombres)
+	if err != nil {
+		return nil, err
+	}
+	plan.Compras = planificador.compras
+	plan.CostoCompras = math.Round(planificador.costo*100) / 100
+
+	plan.Estado = utils.EstadoPlanBorrador
+	plan.FechaCreacion = time.Now()
+	plan.FechaActualizacion = plan.FechaCreacion
+	resultado, err := repository.db.GetClient().Database("gocooking").Collection("planes").InsertOne(context.TODO(), plan)
+	if err != nil {
+		return nil, err
+	}
+	plan.Id = resultado.InsertedID.(primitive.ObjectID)
+	return &plan, nil
+}
+
+// CambiarComida reemplaza la receta de una comida del plan por otra del usuario ("404" si no existe) y
+// recalcula las compras de toda la semana. La receta elegida a mano no tiene que cumplir las restricciones
+// ni tener el equipamiento disponible.
+func (repository PlanRepository) CambiarComida(plan model.PlanSemanal, dia int, momento utils.Momento, orden int, recetaID primitive.ObjectID) (*model.PlanSemanal, error) {
+	planificador, err := nuevoPlanificador(repository.db, plan)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := planificador.verificador.receta(recetaID); err != nil {
+		return nil, err
+	}
+	for i, comida := range plan.Comidas {
+		if comida.Dia == dia && comida.Momento == momento && comida.Orden == orden {
+			plan.Comidas[i].RecetaID = recetaID
+		}
+	}
+
+	// Se vuelven a simular todas las comidas en orden, porque el cambio afecta el stock de las siguientes
+	for i, comida := range plan.Comidas {
+		receta, err := planificador.verificador.receta(comida.RecetaID)
+		if err != nil {
+			if err.Error() == "404" {
+				return nil, ErrorRestriccionesPlan{Motivo: "la receta " + comida.NombreReceta + " del día " + strconv.Itoa(comida.Dia+1) + " ya no existe"}
+			}
+			return nil, err
+		}
+		opcion, err := planificador.evaluar(*receta, comida.Dia)
+		if err != nil {
+			return nil, err
+		}
+		if opcion == nil {
+			return nil, ErrorRestriccionesPlan{Motivo: "la receta " + receta.Nombre + " usa alimentos o recetas que ya no existen"}
+		}
+		plan.Comidas[i] = planificador.aplicar(*opcion, comida.Dia, comida.Momento, comida.Orden)
+	}
+	plan.Compras = planificador.compras
+	plan.CostoCompras = math.Round(planificador.costo*100) / 100
+	plan.FechaActualizacion = time.Now()
+
+	filtro := bson.M{"_id": plan.Id, "id_usuario": plan.UsuarioID, "estado": utils.EstadoPlanBorrador}
+	actualizacion := bson.M{"$set": bson.M{
+		"comidas":             plan.Comidas,
+		"compras":             plan.Compras,
+		"costo_compras":       plan.CostoCompras,
+		"fecha_actualizacion": plan.FechaActualizacion,
+	}}
+	resultado, err := repository.db.GetClient().Database("gocooking").Collection("planes").UpdateOne(context.TODO(), filtro, actualizacion)
+	if err != nil {
+		return nil, err
+	}
+	if resultado.MatchedCount == 0 {
+		return nil, errors.New("400")
+	}
+	return &plan, nil
+}
+
+// AceptarPlan confirma un plan en borrador; después ya no se puede modificar ("400" si ya estaba aceptado)
+func (repository PlanRepository) AceptarPlan(id primitive.ObjectID, usuarioID string) (*model.PlanSemanal, error) {
+	collection := repository.db.GetClient().Database("gocooking").Collection("planes")
+	filtro := bson.M{"_id": id, "id_usuario": usuarioID, "estado": utils.EstadoPlanBorrador}
+	actualizacion := bson.M{"$set": bson.M{"estado": utils.EstadoPlanAceptado, "fecha_actualizacion": time.Now()}}
+	var plan model.PlanSemanal
+	err := collection.FindOneAndUpdate(context.TODO(), filtro, actualizacion, options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&plan)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("400")
+		}
+		return nil, err
+	}
+	return &plan, nil
+}
+
+func (repository PlanRepository) DeletePlan(id primitive.ObjectID, usuarioID string) (*mongo.DeleteResult, error) {
+	resultado, err := repository.db.GetClient().Database("gocooking").Collection("planes").DeleteOne(context.TODO(), bson.M{"_id": id, "id_usuario": usuarioID})
+	if err != nil {
+		return nil, err
+	}
+	if resultado.DeletedCount == 0 {
+		return nil, errors.New("404")
+	}
+	return resultado, nil
+}
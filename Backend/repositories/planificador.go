@@ -0,0 +1,325 @@
+package repositories
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"gocooking-backend/dto"
+	"gocooking-backend/model"
+	"gocooking-backend/utils"
+	"math"
+	"slices"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Desvío aceptado entre las calorías de una comida y las que le corresponden según el objetivo diario
+const toleranciaCalorias = 0.2
+
+// ErrorRestriccionesPlan indica que no se puede armar el plan con las restricciones pedidas,
+// a diferencia de un error al acceder a la base
+type ErrorRestriccionesPlan struct {
+	Motivo string
+}
+
+func (err ErrorRestriccionesPlan) Error() string {
+	return err.Motivo
+}
+
+// planificador simula la semana comida por comida: descuenta del stock lo que usa cada receta elegida,
+// acumula lo que hay que comprar y consume las sobras guardadas
+type planificador struct {
+	verificador   *verificadorStock
+	restricciones model.RestriccionesPlan
+	equipamiento  *model.Equipamiento // nil si el usuario no lo cargó: no se descarta ninguna receta
+	inicio        time.Time
+	restante      map[primitive.ObjectID]float64 // Stock que queda después de las comidas ya planificadas
+	sobras        []model.Sobra                  // Con las porciones que quedan después de las comidas ya planificadas
+	repeticiones  map[primitive.ObjectID]int
+	compras       []model.ProductoCompra
+	costo         float64
+}
+
+// opcionComida es una receta evaluada para una comida, con el stock que quedó de las anteriores
+type opcionComida struct {
+	receta   model.Receta
+	calorias float64                        // Por porción
+	costo    float64                        // De lo que habría que comprar
+	consumo  map[primitive.ObjectID]float64 // Lo que se usa del stock
+	compras  map[primitive.ObjectID]float64 // Lo que falta, por alimento
+	sobra    int                            // Índice de la sobra que se come, -1 si se cocina
+}
+
+func nuevoPlanificador(db DB, plan model.PlanSemanal) (*planificador, error) {
+	verificador, err := nuevoVerificadorStock(db, plan.UsuarioID)
+	if err != nil {
+		return nil, err
+	}
+	filtro := bson.M{"id_usuario": plan.UsuarioID, "porciones": bson.M{"$gt": 0}, "fecha_vencimiento": bson.M{"$gt": plan.FechaInicio}}
+	cursor, err := db.GetClient().Database("gocooking").Collection("sobras").Find(context.TODO(), filtro)
+	if err != nil {
+		return nil, err
+	}
+	var sobras []model.Sobra
+	if err := cursor.All(context.TODO(), &sobras); err != nil {
+		return nil, err
+	}
+	// Primero las que vencen antes, para no desperdiciarlas
+	slices.SortStableFunc(sobras, func(a, b model.Sobra) int { return a.FechaVencimiento.Compare(b.FechaVencimiento) })
+	equipamiento, err := equipamientoDelUsuario(db, plan.UsuarioID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &planificador{
+		verificador:   verificador,
+		restricciones: plan.Restricciones,
+		equipamiento:  equipamiento,
+		inicio:        plan.FechaInicio,
+		restante:      make(map[primitive.ObjectID]float64),
+		sobras:        sobras,
+		repeticiones:  make(map[primitive.ObjectID]int),
+		compras:       []model.ProductoCompra{},
+	}, nil
+}
+
+// generar elige las recetas de cada momento de cada día. En cada una se queda con la que menos compras
+// requiere entre las que cumplen las restricciones, priorizando las que se acercan al objetivo de calorías
+// y, a igual costo, las que menos se repitieron. Solo usa recetas que se pueden preparar con el equipamiento
+// del usuario, y en un mismo momento no elige dos que usen el horno.
+func (planificador *planificador) generar(recetas []model.Receta, nombres map[utils.Momento]string) ([]model.ComidaPlan, error) {
+	restricciones := planificador.restricciones
+	recetasPorMomento := restricciones.CantidadRecetasPorMomento()
+	comidas := []model.ComidaPlan{}
+	for dia := 0; dia < dto.DiasPlan; dia++ {
+		caloriasDelDia := 0.0
+		for i, momento := range restricciones.Momentos {
+			var elegidasEnMomento []model.Receta
+			for orden := 0; orden < recetasPorMomento; orden++ {
+				objetivo := 0.0
+				if restricciones.CaloriasDiarias > 0 {
+					restantes := (len(restricciones.Momentos)-i)*recetasPorMomento - orden
+					objetivo = math.Max(restricciones.CaloriasDiarias-caloriasDelDia, 0) / float64(restantes)
+				}
+
+				var elegida *opcionComida
+				elegidaEnRango := false
+				for _, receta := range recetas {
+					if !slices.Contains(receta.Momentos(), momento) || !cumpleDietas(receta, restricciones.Dietas) {
+						continue
+					}
+					if restricciones.MaxRepeticiones > 0 && planificador.repeticiones[receta.Id] >= restricciones.MaxRepeticiones {
+						continue
+					}
+					enMomento := slices.ContainsFunc(elegidasEnMomento, func(elegida model.Receta) bool { return elegida.Id == receta.Id })
+					if enMomento || !planificador.cumpleEquipamiento(receta, elegidasEnMomento) {
+						continue
+					}
+					opcion, err := planificador.evaluar(receta, dia)
+					if err != nil {
+						return nil, err
+					}
+					if opcion == nil || (restricciones.Presupuesto > 0 && planificador.costo+opcion.costo > restricciones.Presupuesto) {
+						continue
+					}
+
+					enRango := objetivo <= 0 || math.Abs(opcion.calorias-objetivo) <= objetivo*toleranciaCalorias
+					if elegida == nil || planificador.mejor(*opcion, enRango, *elegida, elegidaEnRango, objetivo) {
+						elegida, elegidaEnRango = opcion, enRango
+					}
+				}
+				if elegida == nil {
+					return nil, ErrorRestriccionesPlan{Motivo: "no hay recetas para " + nombres[momento] + " del día " + strconv.Itoa(dia+1) +
+						" que cumplan las dietas, el equipamiento, las repeticiones y el presupuesto pedidos"}
+				}
+
+				comidas = append(comidas, planificador.aplicar(*elegida, dia, momento, orden))
+				elegidasEnMomento = append(elegidasEnMomento, elegida.receta)
+				caloriasDelDia += elegida.calorias
+			}
+		}
+	}
+	return comidas, nil
+}
+
+// cumpleEquipamiento indica si la receta se puede preparar con el equipamiento del usuario y sin usar el horno
+// si ya lo usa otra de las recetas elegidas para el mismo momento
+func (planificador *planificador) cumpleEquipamiento(receta model.Receta, elegidasEnMomento []model.Receta) bool {
+	equipos := planificador.verificador.equipamiento(receta)
+	if len(planificador.equipamiento.Faltante(equipos)) > 0 {
+		return false
+	}
+	for _, elegida := range elegidasEnMomento {
+		if slices.Contains(equipos, utils.EquipoHorno) && slices.Contains(planificador.verificador.equipamiento(elegida), utils.EquipoHorno) {
+			return false
+		}
+	}
+	return true
+}
+
+// mejor indica si la opción a conviene más que la b: primero las que están en el rango de calorías
+// (o, si ninguna lo está, la más cercana), después la más barata y después la menos repetida
+func (planificador *planificador) mejor(a opcionComida, aEnRango bool, b opcionComida, bEnRango bool, objetivo float64) bool {
+	if aEnRango != bEnRango {
+		return aEnRango
+	}
+	if !aEnRango {
+		desvioA, desvioB := math.Abs(a.calorias-objetivo), math.Abs(b.calorias-objetivo)
+		if desvioA != desvioB {
+			return desvioA < desvioB
+		}
+	}
+	if a.costo != b.costo {
+		return a.costo < b.costo
+	}
+	repeticionesA, repeticionesB := planificador.repeticiones[a.receta.Id], planificador.repeticiones[b.receta.Id]
+	if repeticionesA != repeticionesB {
+		return repeticionesA < repeticionesB
+	}
+	return cmp.Less(a.receta.Nombre, b.receta.Nombre)
+}
+
+// evaluar calcula qué implica comer la receta en el día dado. Si hay sobras suficientes se comen esas;
+// si no, se cocina con el stock que queda, usando sustitutos si hace falta, y se compra lo que falte.
+// Los opcionales se usan solo si hay. Devuelve nil si la receta usa alimentos o subrecetas que ya no existen.
+func (planificador *planificador) evaluar(receta model.Receta, dia int) (*opcionComida, error) {
+	personas := planificador.restricciones.Personas
+	ingredientes, err := planificador.verificador.ingredientes(receta)
+	if err != nil {
+		// Una subreceta que ya no existe: la receta no se puede preparar
+		var invalida ErrorRecetaInvalida
+		if errors.As(err, &invalida) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	opcion := &opcionComida{
+		receta:  receta,
+		consumo: make(map[primitive.ObjectID]float64),
+		compras: make(map[primitive.ObjectID]float64),
+		sobra:   -1,
+	}
+
+	porciones := float64(max(receta.Porciones, 1))
+	for _, ingrediente := range ingredientes {
+		alimento, err := planificador.verificador.alimento(ingrediente.AlimentoId)
+		if err != nil {
+			if err.Error() != "404" {
+				return nil, err
+			}
+			if ingrediente.Obligatorio() {
+				return nil, nil
+			}
+			continue
+		}
+		opcion.calorias += alimento.Calorias * ingrediente.Cantidad / porciones
+	}
+
+	fecha := planificador.inicio.AddDate(0, 0, dia)
+	for i, sobra := range planificador.sobras {
+		if sobra.RecetaID == receta.Id && sobra.Porciones >= personas && !sobra.Vencida(fecha) {
+			opcion.sobra = i
+			return opcion, nil
+		}
+	}
+
+	disponible := func(alimento *model.Alimento) float64 {
+		cantidad, usado := planificador.restante[alimento.Id]
+		if !usado {
+			cantidad = alimento.CantidadActual
+		}
+		return math.Max(cantidad-opcion.consumo[alimento.Id], 0)
+	}
+	factor := float64(personas) / porciones
+	for _, obligatorios := range []bool{true, false} {
+		for _, ingrediente := range agruparIngredientes(ingredientes) {
+			if ingrediente.Obligatorio() != obligatorios {
+				continue
+			}
+			alimento, err := planificador.verificador.alimento(ingrediente.AlimentoId)
+			if err != nil {
+				continue // Un opcional que ya no existe; los obligatorios se descartaron antes
+			}
+			cantidad := ingrediente.Cantidad * factor
+			if disponible(alimento) >= cantidad {
+				opcion.consumo[alimento.Id] += cantidad
+				continue
+			}
+			sustitucion, err := planificador.verificador.buscarSustituto(receta.Id, *alimento, cantidad, disponible)
+			if err != nil {
+				return nil, err
+			}
+			if sustitucion != nil {
+				opcion.consumo[sustitucion.SustitutoId] += sustitucion.CantidadSustituto
+				continue
+			}
+			if !obligatorios {
+				continue
+			}
+			falta := cantidad - disponible(alimento)
+			opcion.consumo[alimento.Id] += disponible(alimento)
+			opcion.compras[alimento.Id] += falta
+			opcion.costo += falta * alimento.PrecioUnitario
+		}
+	}
+	return opcion, nil
+}
+
+// aplicar descuenta lo que usa la opción elegida y suma lo que hay que comprar a las compras del plan
+func (planificador *planificador) aplicar(opcion opcionComida, dia int, momento utils.Momento, orden int) model.ComidaPlan {
+	planificador.repeticiones[opcion.receta.Id]++
+	if opcion.sobra >= 0 {
+		planificador.sobras[opcion.sobra].Porciones -= planificador.restricciones.Personas
+	}
+	for alimentoID, cantidad := range opcion.consumo {
+		if _, usado := planificador.restante[alimentoID]; !usado {
+			alimento, _ := planificador.verificador.alimento(alimentoID)
+			planificador.restante[alimentoID] = alimento.CantidadActual
+		}
+		planificador.restante[alimentoID] -= cantidad
+	}
+	for alimentoID, cantidad := range opcion.compras {
+		planificador.agregarCompra(alimentoID, cantidad)
+	}
+	planificador.costo += opcion.costo
+
+	return model.ComidaPlan{
+		Dia:          dia,
+		Momento:      momento,
+		Orden:        orden,
+		RecetaID:     opcion.receta.Id,
+		NombreReceta: opcion.receta.Nombre,
+		Calorias:     math.Round(opcion.calorias*100) / 100,
+		CostoCompras: math.Round(opcion.costo*100) / 100,
+		ConSobras:    opcion.sobra >= 0,
+	}
+}
+
+func (planificador *planificador) agregarCompra(alimentoID primitive.ObjectID, cantidad float64) {
+	for i := range planificador.compras {
+		if planificador.compras[i].AlimentoId == alimentoID {
+			planificador.compras[i].Cantidad += cantidad
+			return
+		}
+	}
+	alimento, _ := planificador.verificador.alimento(alimentoID)
+	planificador.compras = append(planificador.compras, model.ProductoCompra{
+		AlimentoId:  alimento.Id,
+		Cantidad:    cantidad,
+		Nombre:      alimento.Nombre,
+		CategoriaID: alimento.CategoriaID,
+	})
+}
+
+// cumpleDietas indica si la receta tiene todas las dietas pedidas
+func cumpleDietas(receta model.Receta, dietas []utils.Dieta) bool {
+	for _, dieta := range dietas {
+		if !slices.Contains(receta.Dietas, dieta) {
+			return false
+		}
+	}
+	return true
+}
@@ -5,24 +5,36 @@ import (
 	"errors"
 	"gocooking-backend/dto"
 	"gocooking-backend/model"
+	"gocooking-backend/utils"
 	"log"
+	"sort"
 	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type RecetaRepositoryInterface interface {
-	GetRecetas(usuarioID string) (*[]model.Receta, error)
+	GetRecetas(usuarioID string, parametros dto.ParametrosListadoRecetas) (*[]model.Receta, error)
 	GetRecetaById(id primitive.ObjectID) (*model.Receta, error)
 	InsertReceta(receta model.Receta) (*mongo.InsertOneResult, error)
-	UpdateReceta(receta model.Receta) (*mongo.UpdateResult, error)
+	UpdateReceta(receta model.Receta) ([]model.SustitucionUsada, error)
 	DeleteReceta(id primitive.ObjectID) (*mongo.DeleteResult, error)
 	GetRecetasByParameters(parametros dto.ParametrosReceta, usuarioID string) ([]model.Receta, error)
 	GetCantidadRecetasPorMomento(usuarioID string) (map[string]int, error)
 	GetCantidadRecetasPorTipoAlimento(usuarioID string) (map[string]int, error)
+	GetRecetasPublicas(parametros dto.ParametrosListadoRecetas) ([]model.Receta, error)
+	GetRecetasCompartidas(usuarioID string) ([]model.Receta, error)
+	CopiarReceta(receta model.Receta) (*mongo.InsertOneResult, error)
+	BuscarRecetas(usuarioID string, texto string, limite int) ([]model.RecetaEncontrada, error)
+	GetCostoReceta(receta model.Receta) (*model.CostoReceta, error)
+	GetIngredientesBase(receta model.Receta) ([]model.Ingrediente, error)
+	GetRecetasRecomendadas(usuarioID string) ([]model.RecetaRecomendada, error)
+	ValidarReceta(receta model.Receta) (*model.ValidacionReceta, error)
+	GetRecetasSimilares(receta model.Receta, usuarioID string, conCatalogo bool, limite int) ([]model.RecetaSimilar, error)
 }
 
 type RecetaRepository struct {
@@ -35,13 +47,16 @@ func NewRecetaRepository(db DB) *RecetaRepository {
 	}
 }
 
-func (repository RecetaRepository) GetRecetas(usuarioID string) (*[]model.Receta, error) {
+func (repository RecetaRepository) GetRecetas(usuarioID string, parametros dto.ParametrosListadoRecetas) (*[]model.Receta, error) {
 	log.Printf("Iniciando la obtención de recetas para el usuario ID: %s", usuarioID) // Log de inicio
 
 	// Construcción del filtro para la consulta
 	filtro := bson.M{
 		"id_usuario": usuarioID,
 	}
+	if parametros.Etiqueta != "" {
+		filtro["etiquetas"] = strings.ToLower(strings.TrimSpace(parametros.Etiqueta))
+	}
 
 	// Realizar la consulta a la base de datos
 	cursor, err := repository.db.GetClient().Database("gocooking").Collection("recetas").Find(context.TODO(), filtro)
@@ -51,6 +66,19 @@ func (repository RecetaRepository) GetRecetas(usuarioID string) (*[]model.Receta
 	}
 	defer cursor.Close(context.TODO())
 
+	verificador, err := nuevoVerificadorStock(repository.db, usuarioID)
+	if err != nil {
+		return nil, err
+	}
+	sobras, err := porcionesSobrantes(repository.db, usuarioID)
+	if err != nil {
+		return nil, err
+	}
+	equipamiento, err := equipamientoDelUsuario(repository.db, usuarioID)
+	if err != nil {
+		return nil, err
+	}
+
 	var recetas []model.Receta
 	for cursor.Next(context.Background()) {
 		var receta model.Receta
@@ -59,27 +87,29 @@ func (repository RecetaRepository) GetRecetas(usuarioID string) (*[]model.Receta
 			return nil, err
 		}
 
-		// Por cada receta, verificamos si los ingredientes están disponibles en la colección de alimentos
-		disponible := true
-		for _, ingrediente := range receta.Ingredientes {
-			var alimento model.Alimento
-			err := repository.db.GetClient().Database("gocooking").Collection("alimentos").FindOne(context.TODO(), bson.M{"_id": ingrediente.AlimentoId}).Decode(&alimento)
+		// Por cada receta, verificamos si los ingredientes (o sus sustitutos) están disponibles en la colección de alimentos
+		// Sin stock, la receta igual se puede comer si quedan sobras guardadas
+		sustituciones, _, err := verificador.resolver(receta)
+		if err != nil && sobras[receta.Id] == 0 {
+			log.Printf("Receta no disponible para el usuario ID %s: %s: %v", usuarioID, receta.Nombre, err) // Log de falta de ingrediente
+			continue
+		}
+
+		if !verificador.marcarEquipamientoFaltante(&receta, equipamiento, parametros.ConEquipamiento) {
+			continue
+		}
+
+		// Solo agregamos la receta si todos los ingredientes están disponibles o hay sobras
+		receta.Sustituciones = sustituciones
+		receta.PorcionesSobrantes = sobras[receta.Id]
+		if parametros.IncluyeCosto() {
+			receta.Costo, err = costoDeReceta(verificador, receta)
 			if err != nil {
-				log.Printf("Error al obtener alimento con ID %s para la receta del usuario ID %s: %v", ingrediente.AlimentoId, usuarioID, err) // Log de error al obtener alimento
 				return nil, err
 			}
-			if alimento.CantidadActual < ingrediente.Cantidad {
-				log.Printf("Ingrediente no disponible. ID alimento: %s, cantidad requerida: %d, cantidad disponible: %d", ingrediente.AlimentoId, ingrediente.Cantidad, alimento.CantidadActual) // Log de falta de ingrediente
-				disponible = false
-				break
-			}
-		}
-
-		// Solo agregamos la receta si todos los ingredientes están disponibles
-		if disponible {
-			recetas = append(recetas, receta)
-			log.Printf("Receta agregada a la lista de recetas disponibles para el usuario ID %s: %s", usuarioID, receta.Nombre) // Log de receta agregada
 		}
+		recetas = append(recetas, receta)
+		log.Printf("Receta agregada a la lista de recetas disponibles para el usuario ID %s: %s", usuarioID, receta.Nombre) // Log de receta agregada
 	}
 
 	// Verificamos si hubo un error durante la iteración del cursor
@@ -88,6 +118,16 @@ func (repository RecetaRepository) GetRecetas(usuarioID string) (*[]model.Receta
 		return nil, err
 	}
 
+	switch parametros.Orden {
+	case dto.OrdenPuntuacion:
+		err := repository.ordenarPorPuntuacion(recetas, usuarioID)
+		if err != nil {
+			return nil, err
+		}
+	case dto.OrdenCosto:
+		ordenarPorCosto(recetas)
+	}
+
 	log.Printf("Recetas obtenidas para el usuario ID %s: %d recetas encontradas", usuarioID, len(recetas)) // Log de éxito con cantidad de recetas
 	return &recetas, nil
 }
@@ -110,30 +150,44 @@ func (repository RecetaRepository) GetRecetaById(id primitive.ObjectID) (*model.
 
 func (repository RecetaRepository) InsertReceta(receta model.Receta) (*mongo.InsertOneResult, error) {
 	receta.FechaCreacion = time.Now()
-	for _, ingrediente := range receta.Ingredientes {
-		var alimento model.Alimento
+	verificador, err := nuevoVerificadorStock(repository.db, receta.UsuarioID)
+	if err != nil {
+		return nil, err
+	}
 
-		// Buscar el alimento correspondiente al ingrediente en la colección "alimentos"
-		err := repository.db.GetClient().Database("gocooking").Collection("alimentos").FindOne(context.TODO(), bson.M{"_id": ingrediente.AlimentoId}).Decode(&alimento)
+	// Verificar que los alimentos sean adecuados para los momentos de consumo de la receta
+	nombresMomentos, err := nombresDeMomentos(repository.db, receta.UsuarioID)
+	if err != nil {
+		return nil, err
+	}
+	err = verificarMomentos(verificador, receta, nombresMomentos)
+	if err != nil {
+		return nil, err
+	}
+
+	// Verificar si hay suficiente cantidad de cada alimento o de algún sustituto
+	sustituciones, omitidos, err := verificador.resolver(receta)
+	if err != nil {
+		return nil, err
+	}
+	receta.SustitucionesConsumidas = sustituciones
+	receta.OpcionalesOmitidos = omitidos
+
+	// Con subrecetas se guardan los ingredientes descontados, que no cambian aunque después se edite la subreceta
+	if receta.TieneSubrecetas() {
+		receta.IngredientesConsumidos, err = verificador.ingredientes(receta)
 		if err != nil {
 			return nil, err
 		}
+	}
 
-		// Verificar si hay suficiente cantidad de alimento
-		if alimento.CantidadActual < ingrediente.Cantidad {
-			return nil, errors.New("no hay suficiente cantidad del alimento " + alimento.Nombre)
-		}
-
-		// Verificar que el alimento es adecuado para el momento de consumo de la receta
-		alimentoAdecuado := false
-		for _, momento := range alimento.MomentosDeConsumo {
-			if momento == receta.MomentoDeConsumo {
-				alimentoAdecuado = true
-			}
-		}
-		if !alimentoAdecuado {
-			return nil, errors.New("el alimento " + alimento.Nombre + " no es adecuado para el momento de consumo de la receta")
-		}
+	categorias, err := categoriasDelUsuario(repository.db, receta.UsuarioID)
+	if err != nil {
+		return nil, err
+	}
+	receta.Dietas, err = dietasDeReceta(verificador, categorias, receta)
+	if err != nil {
+		return nil, err
 	}
 
 	// Realizar la inserción de la receta en la colección "recetas"
@@ -143,56 +197,153 @@ func (repository RecetaRepository) InsertReceta(receta model.Receta) (*mongo.Ins
 	}
 
 	// Restar las cantidades utilizadas a los alimentos en el almacén
-	for _, ingrediente := range receta.Ingredientes {
-		_, err := repository.db.GetClient().Database("gocooking").Collection("alimentos").UpdateOne(context.TODO(), bson.M{"_id": ingrediente.AlimentoId}, bson.M{"$inc": bson.M{"cantidad_actual": -ingrediente.Cantidad}})
+	for alimentoID, cantidad := range consumoDeStock(receta.IngredientesDescontados(), receta.SustitucionesConsumidas, receta.OpcionalesOmitidos) {
+		_, err := repository.db.GetClient().Database("gocooking").Collection("alimentos").UpdateOne(context.TODO(), bson.M{"_id": alimentoID}, bson.M{"$inc": bson.M{"cantidad_actual": -cantidad}})
 		if err != nil {
 			return nil, errors.New("error al actualizar la cantidad de alimento: " + err.Error())
 		}
 	}
 
+	// La receta recién creada es la primera versión del historial
+	receta.Id = resultado.InsertedID.(primitive.ObjectID)
+	if err := registrarVersion(context.TODO(), repository.db, nil, receta); err != nil {
+		return nil, errors.New("error al guardar la versión de la receta: " + err.Error())
+	}
+
 	return resultado, nil
 }
 
-func (repository RecetaRepository) UpdateReceta(receta model.Receta) (*mongo.UpdateResult, error) {
+// UpdateReceta guarda los cambios de la receta del usuario. Si la receta descontó stock al crearse, se vuelve
+// a resolver con lo que había descontado devuelto y el stock se ajusta solo por la diferencia, en la misma
+// transacción que la receta y su versión (ver enTransaccion); si el stock no alcanza para la nueva versión no se
+// guarda nada. Devuelve las sustituciones con las que se resolvió.
+func (repository RecetaRepository) UpdateReceta(receta model.Receta) ([]model.SustitucionUsada, error) {
 	receta.FechaActualizacion = time.Now()
-	for _, ingrediente := range receta.Ingredientes {
-		var alimento model.Alimento
-		err := repository.db.GetClient().Database("gocooking").Collection("alimentos").FindOne(context.TODO(), bson.M{"_id": ingrediente.AlimentoId}).Decode(&alimento)
-		if err != nil {
-			if err == mongo.ErrNoDocuments {
-				return nil, errors.New("404")
-			}
+
+	// Guardar el estado anterior por si la receta todavía no tiene historial
+	anterior, err := repository.GetRecetaById(receta.Id)
+	if err != nil {
+		return nil, err
+	}
+	if anterior.UsuarioID != receta.UsuarioID {
+		return nil, errors.New("404")
+	}
+
+	verificador, err := nuevoVerificadorStock(repository.db, receta.UsuarioID)
+	if err != nil {
+		return nil, err
+	}
+	nombresMomentos, err := nombresDeMomentos(repository.db, receta.UsuarioID)
+	if err != nil {
+		return nil, err
+	}
+	err = verificarMomentos(verificador, receta, nombresMomentos)
+	if err != nil {
+		return nil, err
+	}
+
+	// Las copias del catálogo no descontaron stock: solo se verifica que alcance, como antes de guardarlas
+	var descontadoAntes map[primitive.ObjectID]float64
+	if !anterior.SinConsumoDeStock {
+		descontadoAntes = consumoDeStock(anterior.IngredientesDescontados(), anterior.SustitucionesConsumidas, anterior.OpcionalesOmitidos)
+		if err := verificador.devolver(descontadoAntes); err != nil {
 			return nil, err
 		}
+	}
+	sustituciones, omitidos, err := verificador.resolver(receta)
+	if err != nil {
+		return nil, err
+	}
 
-		// Verificar que el alimento tenga suficiente cantidad
-		if alimento.CantidadActual < ingrediente.Cantidad {
-			return nil, errors.New("no hay suficiente cantidad del alimento " + alimento.Nombre)
-		}
+	// Los ingredientes pueden haber cambiado, y con ellos las dietas
+	categorias, err := categoriasDelUsuario(repository.db, receta.UsuarioID)
+	if err != nil {
+		return nil, err
+	}
+	receta.Dietas, err = dietasDeReceta(verificador, categorias, receta)
+	if err != nil {
+		return nil, err
+	}
+
+	// Actualizar receta en la base de datos, solo si pertenece al usuario
+	filter := bson.M{"_id": receta.Id, "id_usuario": receta.UsuarioID}
+	// Se actualizan solo los campos editables para no pisar la fecha de creación ni las imágenes
+	cambios := bson.M{
+		"nombre":              receta.Nombre,
+		"momento_consumo":     receta.MomentoDeConsumo,
+		"momentos_consumo":    receta.MomentosDeConsumo,
+		"ingredientes":        receta.Ingredientes,
+		"pasos":               receta.Pasos,
+		"porciones":           receta.Porciones,
+		"tiempo_preparacion":  receta.TiempoPreparacion,
+		"tiempo_coccion":      receta.TiempoCoccion,
+		"etiquetas":           receta.Etiquetas,
+		"equipamiento":        receta.Equipamiento,
+		"dietas":              receta.Dietas,
+		"visibilidad":         receta.Visibilidad,
+		"compartida_con":      receta.CompartidaCon,
+		"fecha_actualizacion": receta.FechaActualizacion,
+	}
 
-		// Verificar que el alimento sea adecuado para el momento de la receta
-		alimentoAdecuado := false
-		for _, momento := range alimento.MomentosDeConsumo {
-			if momento == receta.MomentoDeConsumo {
-				alimentoAdecuado = true
+	// Lo descontado pasa a ser lo de la nueva versión, para que al eliminarla se devuelva lo correcto
+	var diferencia map[primitive.ObjectID]float64
+	if !anterior.SinConsumoDeStock {
+		receta.SustitucionesConsumidas = sustituciones
+		receta.OpcionalesOmitidos = omitidos
+		if receta.TieneSubrecetas() {
+			receta.IngredientesConsumidos, err = verificador.ingredientes(receta)
+			if err != nil {
+				return nil, err
 			}
 		}
-		if !alimentoAdecuado {
-			return nil, errors.New("el alimento " + alimento.Nombre + " no es adecuado para el momento de consumo de la receta")
-		}
+		diferencia = diferenciaDeConsumo(descontadoAntes, consumoDeStock(receta.IngredientesDescontados(), receta.SustitucionesConsumidas, receta.OpcionalesOmitidos))
+		cambios["sustituciones_consumidas"] = receta.SustitucionesConsumidas
+		cambios["opcionales_omitidos"] = receta.OpcionalesOmitidos
+		cambios["ingredientes_consumidos"] = receta.IngredientesConsumidos
 	}
 
-	// Actualizar receta en la base de datos
-	filter := bson.M{"_id": receta.Id}
-	update := bson.M{
-		"$set": receta,
-	}
-	result, err := repository.db.GetClient().Database("gocooking").Collection("recetas").UpdateOne(context.TODO(), filter, update)
+	// Con transacción el stock, la receta y su versión se guardan juntos. Sin ella (mongod sin réplicas) el stock
+	// descontado se repone si la receta no se puede guardar, y una vez guardada la modificación se informa como
+	// hecha aunque falle el registro de la versión, para que no se vuelva a pedir un cambio ya aplicado
+	err = enTransaccion(repository.db, func(ctx context.Context) error {
+		if err := verificador.descontarStock(ctx, diferencia); err != nil {
+			return err
+		}
+		collection := repository.db.GetClient().Database("gocooking").Collection("recetas")
+		result, err := collection.UpdateOne(ctx, filter, bson.M{"$set": cambios})
+		if err == nil && result.MatchedCount == 0 {
+			err = errors.New("404")
+		}
+		if err != nil {
+			verificador.reponerStock(ctx, diferencia)
+			return err
+		}
+
+		// Registrar la receta modificada como una nueva versión
+		var actual model.Receta
+		err = collection.FindOne(ctx, bson.M{"_id": receta.Id}).Decode(&actual)
+		if err == nil {
+			err = registrarVersion(ctx, repository.db, anterior, actual)
+		}
+		if err != nil {
+			if enTransaccionActiva(ctx) {
+				return errors.New("error al guardar la versión de la receta: " + err.Error())
+			}
+			log.Printf("La receta %s se guardó sin registrar su versión: %v", receta.Id.Hex(), err)
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	return result, nil
+	// Las recetas que la usan como subreceta pueden cambiar de dietas. La receta ya quedó guardada: si falla solo
+	// se registra, y las dietas se vuelven a calcular en la próxima modificación
+	if err := actualizarDietas(repository.db, bson.M{"ingredientes.id_receta": receta.Id}); err != nil {
+		log.Printf("No se pudieron actualizar las dietas de las recetas que usan la receta %s: %v", receta.Id.Hex(), err)
+	}
+
+	return sustituciones, nil
 }
 
 func (repository RecetaRepository) DeleteReceta(id primitive.ObjectID) (*mongo.DeleteResult, error) {
@@ -205,17 +356,28 @@ func (repository RecetaRepository) DeleteReceta(id primitive.ObjectID) (*mongo.D
 		return nil, err
 	}
 
-	// Devolver las cantidades de los ingredientes al stock
-	for _, ingrediente := range receta.Ingredientes {
-		filter := bson.M{"_id": ingrediente.AlimentoId}
-		update := bson.M{
-			"$inc": bson.M{
-				"cantidad_actual": ingrediente.Cantidad, // Sumar la cantidad utilizada
-			},
-		}
-		_, err := repository.db.GetClient().Database("gocooking").Collection("alimentos").UpdateOne(context.TODO(), filter, update)
-		if err != nil {
-			return nil, err
+	// No se puede eliminar una receta que otras usan como ingrediente
+	enUso, err := repository.db.GetClient().Database("gocooking").Collection("recetas").CountDocuments(context.TODO(), bson.M{"ingredientes.id_receta": id})
+	if err != nil {
+		return nil, err
+	}
+	if enUso > 0 {
+		return nil, errors.New("400")
+	}
+
+	// Devolver las cantidades consumidas al stock, salvo que la receta no las haya descontado
+	if !receta.SinConsumoDeStock {
+		for alimentoID, cantidad := range consumoDeStock(receta.IngredientesDescontados(), receta.SustitucionesConsumidas, receta.OpcionalesOmitidos) {
+			filter := bson.M{"_id": alimentoID}
+			update := bson.M{
+				"$inc": bson.M{
+					"cantidad_actual": cantidad, // Sumar la cantidad utilizada
+				},
+			}
+			_, err := repository.db.GetClient().Database("gocooking").Collection("alimentos").UpdateOne(context.TODO(), filter, update)
+			if err != nil {
+				return nil, err
+			}
 		}
 	}
 
@@ -225,6 +387,30 @@ func (repository RecetaRepository) DeleteReceta(id primitive.ObjectID) (*mongo.D
 		return nil, err
 	}
 
+	// Quitar la receta de las colecciones que la contengan
+	_, err = repository.db.GetClient().Database("gocooking").Collection("colecciones").UpdateMany(context.TODO(), bson.M{"recetas": id}, bson.M{"$pull": bson.M{"recetas": id}})
+	if err != nil {
+		return nil, err
+	}
+
+	// Eliminar las valoraciones y notas de la receta
+	_, err = repository.db.GetClient().Database("gocooking").Collection("valoraciones").DeleteMany(context.TODO(), bson.M{"id_receta": id})
+	if err != nil {
+		return nil, err
+	}
+
+	// Eliminar el historial de versiones
+	_, err = repository.db.GetClient().Database("gocooking").Collection("versiones_receta").DeleteMany(context.TODO(), bson.M{"id_receta": id})
+	if err != nil {
+		return nil, err
+	}
+
+	// Eliminar las sustituciones propias de la receta
+	_, err = repository.db.GetClient().Database("gocooking").Collection("sustituciones").DeleteMany(context.TODO(), bson.M{"id_receta": id})
+	if err != nil {
+		return nil, err
+	}
+
 	return result, nil
 }
 
@@ -235,8 +421,15 @@ func (repository RecetaRepository) GetRecetasByParameters(parametros dto.Paramet
 	}
 	log.Printf("momento parámetro: %v , nombre parametro: %v", parametros.Momento, parametros.Nombre)
 	// Filtros opcionales
-	if parametros.Momento >= 1 && parametros.Momento <= 4 {
-		filter["momento_consumo"] = parametros.Momento // Usar el valor entero directamente
+	if parametros.TieneMomento() {
+		// Las recetas anteriores solo tienen momento_consumo
+		filter["$or"] = bson.A{
+			bson.M{"momentos_consumo": parametros.Momento},
+			bson.M{"momento_consumo": parametros.Momento},
+		}
+	}
+	if parametros.Dieta != "" {
+		filter["dietas"] = parametros.Dieta
 	}
 
 	cursor, err := repository.db.GetClient().Database("gocooking").Collection("recetas").Find(context.TODO(), filter)
@@ -246,7 +439,30 @@ func (repository RecetaRepository) GetRecetasByParameters(parametros dto.Paramet
 	}
 	defer cursor.Close(context.TODO())
 
-	// Validar que las recetas tengan suficientes cantidades de alimentos en stock
+	verificador, err := nuevoVerificadorStock(repository.db, usuarioID)
+	if err != nil {
+		return nil, err
+	}
+	sobras, err := porcionesSobrantes(repository.db, usuarioID)
+	if err != nil {
+		return nil, err
+	}
+	equipamiento, err := equipamientoDelUsuario(repository.db, usuarioID)
+	if err != nil {
+		return nil, err
+	}
+
+	// La categoría pedida incluye a sus subcategorías
+	var categorias map[primitive.ObjectID]bool
+	if parametros.Categoria != "" {
+		todas, err := categoriasDelUsuario(repository.db, usuarioID)
+		if err != nil {
+			return nil, err
+		}
+		categorias = conSubcategorias(todas, utils.GetObjectIDFromStringID(parametros.Categoria))
+	}
+
+	// Validar que las recetas tengan suficientes cantidades de alimentos (o sustitutos) en stock
 	for cursor.Next(context.TODO()) {
 		var receta model.Receta
 		err := cursor.Decode(&receta)
@@ -255,67 +471,59 @@ func (repository RecetaRepository) GetRecetasByParameters(parametros dto.Paramet
 		}
 
 		// Verificar que haya stock suficiente para cada ingrediente de la receta
-		disponible := true
-		tipoCoincide := false   // Variable para comprobar si al menos un ingrediente coincide
-		nombreCoincide := false // Inicialmente asumimos que coincide con el nombre
-
-		// Recoger los IDs de los alimentos a buscar
-		alimentoIDs := make([]primitive.ObjectID, len(receta.Ingredientes))
-		for i, ingrediente := range receta.Ingredientes {
-			alimentoIDs[i] = ingrediente.AlimentoId
-		}
+		sustituciones, _, errStock := verificador.resolver(receta)
+		disponible := errStock == nil
+		categoriaCoincide := false // Variable para comprobar si al menos un ingrediente coincide
+		nombreCoincide := false    // Inicialmente asumimos que coincide con el nombre
 
-		// Buscar todos los alimentos de una vez
-		var alimentos []model.Alimento
-		cursorAlimentos, err := repository.db.GetClient().Database("gocooking").Collection("alimentos").Find(context.TODO(), bson.M{"_id": bson.M{"$in": alimentoIDs}})
+		ingredientes, err := verificador.ingredientes(receta)
 		if err != nil {
-			return nil, err
+			disponible = false
 		}
-		defer cursorAlimentos.Close(context.TODO())
-
-		for cursorAlimentos.Next(context.TODO()) {
-			var alimento model.Alimento
-			err := cursorAlimentos.Decode(&alimento)
+		for _, ingrediente := range ingredientes {
+			alimento, err := verificador.alimento(ingrediente.AlimentoId)
 			if err != nil {
-				return nil, err
+				// Un opcional que ya no existe no impide la receta
+				if ingrediente.Obligatorio() {
+					disponible = false
+					break
+				}
+				continue
 			}
-			alimentos = append(alimentos, alimento)
-		}
 
-		// Crear un mapa para acceder fácilmente a los alimentos por ID
-		alimentoMap := make(map[primitive.ObjectID]model.Alimento)
-		for _, alimento := range alimentos {
-			alimentoMap[alimento.Id] = alimento
-		}
-
-		for _, ingrediente := range receta.Ingredientes {
-			alimento, exists := alimentoMap[ingrediente.AlimentoId]
-			if !exists {
-				disponible = false
-				break
+			// Comprobar categoría de alimento
+			if categorias[alimento.CategoriaID] {
+				categoriaCoincide = true
 			}
 
-			// Verificar stock
-			if alimento.CantidadActual < ingrediente.Cantidad {
-				disponible = false
-				break
-			}
-			// Comprobar tipo de alimento
-			if parametros.Tipo >= 1 && parametros.Tipo <= 6 {
-				if int(alimento.Tipo) == parametros.Tipo {
-					tipoCoincide = true
-				}
+			// Comprobar nombre de ingrediente, sin importar mayúsculas ni acentos
+			if parametros.Nombre != "" && utils.ContieneTexto(alimento.Nombre, parametros.Nombre) {
+				nombreCoincide = true
 			}
+		}
 
-			// Comprobar nombre de ingrediente
-			if parametros.Nombre != "" {
-				if strings.Contains(strings.ToLower(alimento.Nombre), strings.ToLower(parametros.Nombre)) {
-					nombreCoincide = true
+		// El nombre también puede coincidir con el de la receta
+		if parametros.Nombre != "" && utils.ContieneTexto(receta.Nombre, parametros.Nombre) {
+			nombreCoincide = true
+		}
+		// Las sobras guardadas también permiten comerla aunque falte stock
+		disponible = disponible || sobras[receta.Id] > 0
+		log.Printf("Receta: %s - disponible: %v, categoriaCoincide: %v, nombreCoincide: %v", receta.Nombre, disponible, categoriaCoincide, nombreCoincide)
+		if !verificador.marcarEquipamientoFaltante(&receta, equipamiento, parametros.ConEquipamiento) {
+			continue
+		}
+		if disponible && (parametros.Categoria == "" || categoriaCoincide) && (parametros.Nombre == "" || nombreCoincide) {
+			if parametros.IncluyeCosto() {
+				receta.Costo, err = costoDeReceta(verificador, receta)
+				if err != nil {
+					return nil, err
+				}
+				if parametros.CostoMaximo > 0 && receta.Costo.PorPorcion > parametros.CostoMaximo {
+					continue
 				}
 			}
-		}
-		log.Printf("Receta: %s - disponible: %v, tipoCoincide: %v, nombreCoincide: %v", receta.Nombre, disponible, tipoCoincide, nombreCoincide)
-		if disponible && (parametros.Tipo == 0 || tipoCoincide) && (parametros.Nombre == "" || nombreCoincide) {
+			receta.Sustituciones = sustituciones
+			receta.PorcionesSobrantes = sobras[receta.Id]
 			recetas = append(recetas, receta)
 		}
 	}
@@ -324,25 +532,52 @@ func (repository RecetaRepository) GetRecetasByParameters(parametros dto.Paramet
 		return nil, err
 	}
 
+	switch parametros.Orden {
+	case dto.OrdenPuntuacion:
+		err := repository.ordenarPorPuntuacion(recetas, usuarioID)
+		if err != nil {
+			return nil, err
+		}
+	case dto.OrdenCosto:
+		ordenarPorCosto(recetas)
+	}
+
 	return recetas, nil
 }
 
 func (repository RecetaRepository) GetCantidadRecetasPorMomento(usuarioID string) (map[string]int, error) {
-	recetas, err := repository.GetRecetas(usuarioID)
+	recetas, err := repository.GetRecetas(usuarioID, dto.ParametrosListadoRecetas{})
 	if err != nil {
 		return nil, err
 	}
 
+	nombres, err := nombresDeMomentos(repository.db, usuarioID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Una receta con varios momentos cuenta en cada uno
 	cantidadRecetasPorMomento := make(map[string]int)
 	for _, receta := range *recetas {
-		cantidadRecetasPorMomento[receta.MomentoDeConsumo.String()]++
+		for _, nombre := range utils.NombresDeMomentos(receta.Momentos(), nombres) {
+			cantidadRecetasPorMomento[nombre]++
+		}
 	}
 	return cantidadRecetasPorMomento, nil
 }
 
 func (repository RecetaRepository) GetCantidadRecetasPorTipoAlimento(usuarioID string) (map[string]int, error) {
 	// Obtener las recetas del usuario
-	recetas, err := repository.GetRecetas(usuarioID)
+	recetas, err := repository.GetRecetas(usuarioID, dto.ParametrosListadoRecetas{})
+	if err != nil {
+		return nil, err
+	}
+
+	categorias, err := categoriasDelUsuario(repository.db, usuarioID)
+	if err != nil {
+		return nil, err
+	}
+	verificador, err := nuevoVerificadorStock(repository.db, usuarioID)
 	if err != nil {
 		return nil, err
 	}
@@ -351,27 +586,153 @@ func (repository RecetaRepository) GetCantidadRecetasPorTipoAlimento(usuarioID s
 	cantidadRecetasPorTipoAlimento := make(map[string]int)
 
 	for _, receta := range *recetas {
-		// Usar un mapa local para evitar contar tipos duplicados en una receta
+		// Usar un mapa local para evitar contar categorías duplicadas en una receta
 		tiposContados := make(map[string]bool)
 
-		for _, ingrediente := range receta.Ingredientes {
-			var alimento model.Alimento
-			// Obtener el alimento de la base de datos
-			err := repository.db.GetClient().Database("gocooking").Collection("alimentos").FindOne(
-				context.TODO(),
-				bson.M{"_id": ingrediente.AlimentoId},
-			).Decode(&alimento)
+		// Las subrecetas cuentan con los alimentos que usan
+		ingredientes, err := verificador.ingredientes(receta)
+		if err != nil {
+			return nil, err
+		}
+		for _, ingrediente := range ingredientes {
+			alimento, err := verificador.alimento(ingrediente.AlimentoId)
 			if err != nil {
+				// Los opcionales sin alimento no se usan al prepararla
+				if err.Error() == "404" && !ingrediente.Obligatorio() {
+					continue
+				}
 				return nil, err
 			}
 
-			// Contar el tipo de alimento si no fue contado en esta receta
-			if !tiposContados[alimento.Tipo.String()] {
-				cantidadRecetasPorTipoAlimento[alimento.Tipo.String()]++
-				tiposContados[alimento.Tipo.String()] = true
+			// Contar la categoría del alimento y las que la contienen (Pollo también cuenta como Carne),
+			// si no fueron contadas en esta receta
+			nombres := []string{}
+			for _, categoria := range conCategoriasPadre(categorias, alimento.CategoriaID) {
+				nombres = append(nombres, categoria.Nombre)
+			}
+			if len(nombres) == 0 {
+				nombres = append(nombres, "Sin categoría")
+			}
+			for _, nombre := range nombres {
+				if !tiposContados[nombre] {
+					cantidadRecetasPorTipoAlimento[nombre]++
+					tiposContados[nombre] = true
+				}
 			}
 		}
 	}
 
 	return cantidadRecetasPorTipoAlimento, nil
 }
+
+// GetCostoReceta calcula el costo de la receta con los precios de los alimentos de su dueño
+func (repository RecetaRepository) GetCostoReceta(receta model.Receta) (*model.CostoReceta, error) {
+	verificador, err := nuevoVerificadorStock(repository.db, receta.UsuarioID)
+	if err != nil {
+		return nil, err
+	}
+	return costoDeReceta(verificador, receta)
+}
+
+// GetIngredientesBase devuelve los alimentos que usa la receta con sus subrecetas expandidas,
+// sumando las cantidades del mismo alimento
+func (repository RecetaRepository) GetIngredientesBase(receta model.Receta) ([]model.Ingrediente, error) {
+	verificador, err := nuevoVerificadorStock(repository.db, receta.UsuarioID)
+	if err != nil {
+		return nil, err
+	}
+	ingredientes, err := verificador.ingredientes(receta)
+	if err != nil {
+		return nil, err
+	}
+	return agruparIngredientes(ingredientes), nil
+}
+
+// BuscarRecetas busca el texto en el nombre, los ingredientes y los pasos de las recetas del usuario
+func (repository RecetaRepository) BuscarRecetas(usuarioID string, texto string, limite int) ([]model.RecetaEncontrada, error) {
+	recetas := []model.RecetaEncontrada{}
+	err := buscarPorTexto(repository.db.GetClient().Database("gocooking").Collection("recetas"), usuarioID, texto, limite, &recetas)
+	if err != nil {
+		return nil, err
+	}
+	return recetas, nil
+}
+
+// GetRecetasPublicas devuelve las recetas del catálogo, de todos los usuarios, de la más nueva a la más vieja
+func (repository RecetaRepository) GetRecetasPublicas(parametros dto.ParametrosListadoRecetas) ([]model.Receta, error) {
+	filtro := bson.M{
+		"visibilidad": utils.VisibilidadPublica,
+	}
+	if parametros.Etiqueta != "" {
+		filtro["etiquetas"] = strings.ToLower(strings.TrimSpace(parametros.Etiqueta))
+	}
+	return repository.buscarRecetas(filtro, options.Find().SetSort(bson.M{"fecha_creacion": -1}))
+}
+
+// GetRecetasCompartidas devuelve las recetas de otros usuarios compartidas con el usuario
+func (repository RecetaRepository) GetRecetasCompartidas(usuarioID string) ([]model.Receta, error) {
+	filtro := bson.M{
+		"visibilidad":    utils.VisibilidadCompartida,
+		"compartida_con": usuarioID,
+	}
+	return repository.buscarRecetas(filtro, options.Find().SetSort(bson.M{"nombre": 1}))
+}
+
+// CopiarReceta guarda una receta copiada de otro usuario sin verificar ni descontar stock:
+// es una receta nueva en el recetario, no algo que se cocinó
+func (repository RecetaRepository) CopiarReceta(receta model.Receta) (*mongo.InsertOneResult, error) {
+	receta.FechaCreacion = time.Now()
+	receta.SinConsumoDeStock = true
+	dietas, err := calcularDietas(repository.db, receta)
+	if err != nil {
+		return nil, err
+	}
+	receta.Dietas = dietas
+	resultado, err := repository.db.GetClient().Database("gocooking").Collection("recetas").InsertOne(context.TODO(), receta)
+	if err != nil {
+		return nil, err
+	}
+
+	receta.Id = resultado.InsertedID.(primitive.ObjectID)
+	if err := registrarVersion(context.TODO(), repository.db, nil, receta); err != nil {
+		return nil, errors.New("error al guardar la versión de la receta: " + err.Error())
+	}
+	return resultado, nil
+}
+
+func (repository RecetaRepository) buscarRecetas(filtro bson.M, opciones *options.FindOptions) ([]model.Receta, error) {
+	cursor, err := repository.db.GetClient().Database("gocooking").Collection("recetas").Find(context.TODO(), filtro, opciones)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	recetas := []model.Receta{}
+	if err := cursor.All(context.TODO(), &recetas); err != nil {
+		return nil, err
+	}
+	return recetas, nil
+}
+
+// ordenarPorPuntuacion ordena las recetas de mayor a menor puntuación del usuario, dejando al final las que no tienen puntuación
+func (repository RecetaRepository) ordenarPorPuntuacion(recetas []model.Receta, usuarioID string) error {
+	cursor, err := repository.db.GetClient().Database("gocooking").Collection("valoraciones").Find(context.TODO(), bson.M{"id_usuario": usuarioID})
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(context.TODO())
+
+	puntuaciones := make(map[primitive.ObjectID]int)
+	for cursor.Next(context.TODO()) {
+		var valoracion model.Valoracion
+		if err := cursor.Decode(&valoracion); err != nil {
+			return err
+		}
+		puntuaciones[valoracion.RecetaID] = valoracion.Puntuacion
+	}
+
+	sort.SliceStable(recetas, func(i, j int) bool {
+		return puntuaciones[recetas[i].Id] > puntuaciones[recetas[j].Id]
+	})
+	return nil
+}
@@ -0,0 +1,185 @@
+package repositories
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"gocooking-backend/model"
+	"log"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Parámetros del puntaje de las recomendaciones
+const (
+	diasVencimientoCercano  = 7.0 // Un alimento que vence antes suma puntos, más cuanto antes venza
+	diasPreparacionReciente = 7.0 // Una receta preparada hace menos resta puntos, más cuanto más reciente
+	pesoVencimiento         = 10.0
+	pesoSobrestock          = 4.0
+	penalizacionReciente    = 6.0
+	penalizacionCompra      = 3.0 // Por cada alimento que habría que comprar
+)
+
+// GetRecetasRecomendadas puntúa las recetas del usuario según cuánto aprovechan los alimentos que están por
+// vencer o que sobran en la despensa, restando las preparadas hace poco y las que requieren comprar algo.
+// Devuelve todas las recetas que se pueden preparar con el equipamiento del usuario, de la más recomendada a la menos.
+func (repository RecetaRepository) GetRecetasRecomendadas(usuarioID string) ([]model.RecetaRecomendada, error) {
+	recetas, err := repository.buscarRecetas(bson.M{"id_usuario": usuarioID}, nil)
+	if err != nil {
+		return nil, err
+	}
+	verificador, err := nuevoVerificadorStock(repository.db, usuarioID)
+	if err != nil {
+		return nil, err
+	}
+	preparaciones, err := ultimasPreparaciones(repository.db, usuarioID, recetas)
+	if err != nil {
+		return nil, err
+	}
+	equipamiento, err := equipamientoDelUsuario(repository.db, usuarioID)
+	if err != nil {
+		return nil, err
+	}
+
+	ahora := time.Now()
+	recomendadas := []model.RecetaRecomendada{}
+	for _, receta := range recetas {
+		if len(equipamiento.Faltante(verificador.equipamiento(receta))) > 0 {
+			continue
+		}
+		recomendada, err := puntuarReceta(verificador, receta, ahora, preparaciones[receta.Id])
+		if err != nil {
+			// Una subreceta que ya no existe no debe impedir recomendar las demás
+			var invalida ErrorRecetaInvalida
+			if errors.As(err, &invalida) {
+				log.Printf("No se pudo puntuar la receta %s: %v", receta.Nombre, err)
+				continue
+			}
+			return nil, err
+		}
+		recomendadas = append(recomendadas, *recomendada)
+	}
+
+	slices.SortStableFunc(recomendadas, func(a, b model.RecetaRecomendada) int {
+		if a.Puntaje != b.Puntaje {
+			return cmp.Compare(b.Puntaje, a.Puntaje)
+		}
+		return cmp.Compare(a.Nombre, b.Nombre)
+	})
+	return recomendadas, nil
+}
+
+// puntuarReceta calcula el puntaje de la receta con el stock actual. ultimaPreparacion es cero si no se sabe.
+func puntuarReceta(verificador *verificadorStock, receta model.Receta, ahora time.Time, ultimaPreparacion time.Time) (*model.RecetaRecomendada, error) {
+	ingredientes, err := verificador.ingredientes(receta)
+	if err != nil {
+		return nil, err
+	}
+
+	recomendada := &model.RecetaRecomendada{Receta: receta, Motivos: []string{}}
+	var faltantes []string
+	for _, ingrediente := range agruparIngredientes(ingredientes) {
+		alimento, err := verificador.alimento(ingrediente.AlimentoId)
+		if err != nil {
+			if err.Error() != "404" {
+				return nil, err
+			}
+			if ingrediente.Obligatorio() {
+				faltantes = append(faltantes, ingrediente.Nombre)
+			}
+			continue
+		}
+
+		// Cuánto del stock que está por vencer se aprovecha; lo ya vencido no se recomienda usar
+		if alimento.FechaVencimiento != nil && alimento.CantidadActual > 0 {
+			dias := alimento.FechaVencimiento.Sub(ahora).Hours() / 24
+			if dias >= 0 && dias < diasVencimientoCercano {
+				uso := math.Min(ingrediente.Cantidad/alimento.CantidadActual, 1)
+				recomendada.Puntaje += pesoVencimiento * (1 - dias/diasVencimientoCercano) * uso
+				recomendada.Motivos = append(recomendada.Motivos, "usa "+alimento.Nombre+", que vence "+textoDias(dias, "en"))
+			}
+		}
+
+		// Cuánto del excedente se aprovecha, si hay más del doble del mínimo; cuenta más cuanto mayor sea el excedente
+		if alimento.CantidadMinima > 0 && alimento.CantidadActual > 2*alimento.CantidadMinima {
+			exceso := alimento.CantidadActual - alimento.CantidadMinima
+			intensidad := math.Min((alimento.CantidadActual/alimento.CantidadMinima-1)/4, 1)
+			uso := math.Min(ingrediente.Cantidad/exceso, 1)
+			recomendada.Puntaje += pesoSobrestock * intensidad * uso
+			recomendada.Motivos = append(recomendada.Motivos, "usa "+alimento.Nombre+", que tenés de sobra")
+		}
+
+		// Los obligatorios sin stock ni sustituto hay que comprarlos
+		if ingrediente.Obligatorio() && alimento.CantidadActual < ingrediente.Cantidad {
+			disponible := func(alimento *model.Alimento) float64 { return alimento.CantidadActual }
+			sustituto, err := verificador.buscarSustituto(receta.Id, *alimento, ingrediente.Cantidad, disponible)
+			if err != nil {
+				return nil, err
+			}
+			if sustituto == nil {
+				faltantes = append(faltantes, alimento.Nombre)
+			}
+		}
+	}
+
+	if len(faltantes) > 0 {
+		recomendada.Puntaje -= penalizacionCompra * float64(len(faltantes))
+		recomendada.Motivos = append(recomendada.Motivos, "hay que comprar "+strings.Join(faltantes, ", "))
+	}
+	if !ultimaPreparacion.IsZero() {
+		dias := ahora.Sub(ultimaPreparacion).Hours() / 24
+		if dias < diasPreparacionReciente {
+			recomendada.Puntaje -= penalizacionReciente * (1 - math.Max(dias, 0)/diasPreparacionReciente)
+			recomendada.Motivos = append(recomendada.Motivos, "se preparó "+textoDias(dias, "hace"))
+		}
+	}
+	recomendada.Puntaje = math.Round(recomendada.Puntaje*100) / 100
+	return recomendada, nil
+}
+
+// ultimasPreparaciones devuelve cuándo se preparó por última vez cada receta: al crearla, si descontó stock,
+// o al guardar sobras de ella
+func ultimasPreparaciones(db DB, usuarioID string, recetas []model.Receta) (map[primitive.ObjectID]time.Time, error) {
+	preparaciones := make(map[primitive.ObjectID]time.Time)
+	for _, receta := range recetas {
+		if !receta.SinConsumoDeStock {
+			preparaciones[receta.Id] = receta.FechaCreacion
+		}
+	}
+
+	cursor, err := db.GetClient().Database("gocooking").Collection("sobras").Find(context.TODO(), bson.M{"id_usuario": usuarioID})
+	if err != nil {
+		return nil, err
+	}
+	var sobras []model.Sobra
+	if err := cursor.All(context.TODO(), &sobras); err != nil {
+		return nil, err
+	}
+	for _, sobra := range sobras {
+		if sobra.FechaCreacion.After(preparaciones[sobra.RecetaID]) {
+			preparaciones[sobra.RecetaID] = sobra.FechaCreacion
+		}
+	}
+	return preparaciones, nil
+}
+
+// textoDias escribe una cantidad de días como "hoy", "en 1 día" o "hace 3 días"
+func textoDias(dias float64, preposicion string) string {
+	enteros := int(math.Floor(dias))
+	if preposicion == "en" {
+		enteros = int(math.Ceil(dias))
+	}
+	switch {
+	case enteros <= 0:
+		return "hoy"
+	case enteros == 1:
+		return preposicion + " 1 día"
+	}
+	return preposicion + " " + strconv.Itoa(enteros) + " días"
+}
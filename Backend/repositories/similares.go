@@ -0,0 +1,223 @@
+package repositories
+
+import (
+	"cmp"
+	"gocooking-backend/model"
+	"gocooking-backend/utils"
+	"math"
+	"slices"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Peso de cada parte en la similitud entre dos recetas
+const (
+	pesoSimilitudAlimentos  = 0.6
+	pesoSimilitudCategorias = 0.3
+	pesoMismoMomento        = 0.1
+)
+
+// perfilReceta resume lo que usa una receta para compararla con otras, también de otros usuarios: por eso
+// los alimentos se identifican por nombre y las categorías del sistema por su clave.
+// Los ingredientes opcionales y a gusto pesan la mitad.
+type perfilReceta struct {
+	alimentos    map[string]float64
+	nombres      map[string]string // Nombre de cada alimento del perfil, para informar los que se comparten
+	categorias   map[string]float64
+	momentos     []utils.Momento
+	equipamiento []utils.Equipo
+}
+
+// perfilador arma los perfiles cargando una sola vez los alimentos y categorías de cada dueño
+type perfilador struct {
+	db            DB
+	verificadores map[string]*verificadorStock
+	categorias    map[string][]model.Categoria
+}
+
+func (perfilador *perfilador) perfil(receta model.Receta) (*perfilReceta, error) {
+	verificador, cargado := perfilador.verificadores[receta.UsuarioID]
+	if !cargado {
+		var err error
+		verificador, err = nuevoVerificadorStock(perfilador.db, receta.UsuarioID)
+		if err != nil {
+			return nil, err
+		}
+		perfilador.verificadores[receta.UsuarioID] = verificador
+		perfilador.categorias[receta.UsuarioID], err = categoriasDelUsuario(perfilador.db, receta.UsuarioID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Si una subreceta ya no existe se compara con los alimentos propios de la receta
+	ingredientes, err := verificador.ingredientes(receta)
+	if err != nil {
+		ingredientes = slices.DeleteFunc(slices.Clone(receta.Ingredientes), model.Ingrediente.EsSubreceta)
+	}
+
+	perfil := &perfilReceta{
+		alimentos:    make(map[string]float64),
+		nombres:      make(map[string]string),
+		categorias:   make(map[string]float64),
+		momentos:     receta.Momentos(),
+		equipamiento: verificador.equipamiento(receta),
+	}
+	for _, ingrediente := range ingredientes {
+		peso := 1.0
+		if !ingrediente.Obligatorio() {
+			peso = 0.5
+		}
+		nombre := ingrediente.Nombre
+		alimento, err := verificador.alimento(ingrediente.AlimentoId)
+		if err != nil && err.Error() != "404" {
+			return nil, err
+		}
+		if err == nil {
+			nombre = alimento.Nombre
+			for _, categoria := range conCategoriasPadre(perfilador.categorias[receta.UsuarioID], alimento.CategoriaID) {
+				clave := categoria.Clave
+				if clave == "" {
+					clave = categoria.Id.Hex()
+				}
+				perfil.categorias[clave] += peso
+			}
+		}
+
+		clave := utils.ClaveNombre(nombre)
+		if clave == "" {
+			continue
+		}
+		perfil.alimentos[clave] = math.Max(perfil.alimentos[clave], peso)
+		perfil.nombres[clave] = nombre
+	}
+	return perfil, nil
+}
+
+// jaccardPonderado es la suma de los mínimos sobre la suma de los máximos de cada clave
+func jaccardPonderado(a map[string]float64, b map[string]float64) float64 {
+	minimos, maximos := 0.0, 0.0
+	for clave, pesoA := range a {
+		minimos += math.Min(pesoA, b[clave])
+		maximos += math.Max(pesoA, b[clave])
+	}
+	for clave, pesoB := range b {
+		if _, existe := a[clave]; !existe {
+			maximos += pesoB
+		}
+	}
+	if maximos == 0 {
+		return 0
+	}
+	return minimos / maximos
+}
+
+// maxCandidatasCatalogo es cuántas recetas públicas de otros usuarios se comparan como máximo
+const maxCandidatasCatalogo = 200
+
+// GetRecetasSimilares ordena las recetas del usuario, y si se pide las públicas de otros, según cuánto se
+// parecen sus alimentos y categorías a los de la receta y si comparten algún momento de consumo, y devuelve
+// las primeras hasta el límite. No incluye la receta misma ni las que no comparten ningún alimento o
+// categoría; las que requieren equipos que el usuario no tiene se marcan.
+func (repository RecetaRepository) GetRecetasSimilares(receta model.Receta, usuarioID string, conCatalogo bool, limite int) ([]model.RecetaSimilar, error) {
+	candidatas, err := repository.buscarRecetas(bson.M{"_id": bson.M{"$ne": receta.Id}, "id_usuario": usuarioID}, nil)
+	if err != nil {
+		return nil, err
+	}
+	if conCatalogo {
+		publicas, err := repository.candidatasDelCatalogo(receta, usuarioID)
+		if err != nil {
+			return nil, err
+		}
+		candidatas = append(candidatas, publicas...)
+	}
+
+	perfilador := &perfilador{
+		db:            repository.db,
+		verificadores: make(map[string]*verificadorStock),
+		categorias:    make(map[string][]model.Categoria),
+	}
+	original, err := perfilador.perfil(receta)
+	if err != nil {
+		return nil, err
+	}
+	equipamiento, err := equipamientoDelUsuario(repository.db, usuarioID)
+	if err != nil {
+		return nil, err
+	}
+
+	similares := []model.RecetaSimilar{}
+	for _, candidata := range candidatas {
+		perfil, err := perfilador.perfil(candidata)
+		if err != nil {
+			return nil, err
+		}
+		alimentos := jaccardPonderado(original.alimentos, perfil.alimentos)
+		categorias := jaccardPonderado(original.categorias, perfil.categorias)
+		if alimentos == 0 && categorias == 0 {
+			continue
+		}
+
+		candidata.EquipamientoFaltante = equipamiento.Faltante(perfil.equipamiento)
+		similar := model.RecetaSimilar{
+			Receta:           candidata,
+			AlimentosEnComun: []string{},
+			MismoMomento:     slices.ContainsFunc(perfil.momentos, func(momento utils.Momento) bool { return slices.Contains(original.momentos, momento) }),
+		}
+		similitud := pesoSimilitudAlimentos*alimentos + pesoSimilitudCategorias*categorias
+		if similar.MismoMomento {
+			similitud += pesoMismoMomento
+		}
+		similar.Similitud = math.Round(similitud*1000) / 1000
+		for clave := range perfil.alimentos {
+			if _, comun := original.alimentos[clave]; comun {
+				similar.AlimentosEnComun = append(similar.AlimentosEnComun, original.nombres[clave])
+			}
+		}
+		slices.Sort(similar.AlimentosEnComun)
+		similares = append(similares, similar)
+	}
+
+	slices.SortStableFunc(similares, func(a, b model.RecetaSimilar) int {
+		if a.Similitud != b.Similitud {
+			return cmp.Compare(b.Similitud, a.Similitud)
+		}
+		return cmp.Compare(a.Nombre, b.Nombre)
+	})
+	if len(similares) > limite {
+		similares = similares[:limite]
+	}
+	return similares, nil
+}
+
+// candidatasDelCatalogo busca en la base las recetas públicas de otros usuarios que comparten algún
+// ingrediente, sin importar mayúsculas ni acentos, o algún momento de consumo con la receta, para no
+// cargar todo el catálogo. Se toman las más recientes hasta maxCandidatasCatalogo.
+func (repository RecetaRepository) candidatasDelCatalogo(receta model.Receta, usuarioID string) ([]model.Receta, error) {
+	nombres := bson.A{}
+	for _, ingrediente := range receta.Ingredientes {
+		if ingrediente.Nombre != "" {
+			nombres = append(nombres, ingrediente.Nombre)
+		}
+	}
+	momentos := bson.A{}
+	for _, momento := range receta.Momentos() {
+		momentos = append(momentos, momento)
+	}
+	filtro := bson.M{
+		"_id":         bson.M{"$ne": receta.Id},
+		"id_usuario":  bson.M{"$ne": usuarioID},
+		"visibilidad": utils.VisibilidadPublica,
+		"$or": bson.A{
+			bson.M{"ingredientes.nombre": bson.M{"$in": nombres}},
+			bson.M{"momentos_consumo": bson.M{"$in": momentos}},
+			bson.M{"momento_consumo": bson.M{"$in": momentos}},
+		},
+	}
+	opciones := options.Find().
+		SetCollation(&options.Collation{Locale: "es", Strength: 1}).
+		SetSort(bson.M{"fecha_creacion": -1}).
+		SetLimit(maxCandidatasCatalogo)
+	return repository.buscarRecetas(filtro, opciones)
+}
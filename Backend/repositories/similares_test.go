@@ -0,0 +1,59 @@
+package repositories
+
+import (
+	"math"
+	"testing"
+)
+
+func TestJaccardPonderado(t *testing.T) {
+	casos := []struct {
+		nombre   string
+		a, b     map[string]float64
+		esperado float64
+	}{
+		{
+			nombre:   "iguales",
+			a:        map[string]float64{"harina": 1, "huevo": 1},
+			b:        map[string]float64{"harina": 1, "huevo": 1},
+			esperado: 1,
+		},
+		{
+			nombre:   "sin nada en común",
+			a:        map[string]float64{"harina": 1},
+			b:        map[string]float64{"tomate": 1},
+			esperado: 0,
+		},
+		{
+			nombre:   "la mitad en común",
+			a:        map[string]float64{"harina": 1, "huevo": 1},
+			b:        map[string]float64{"harina": 1, "tomate": 1},
+			esperado: 1.0 / 3,
+		},
+		{
+			nombre:   "un opcional pesa la mitad",
+			a:        map[string]float64{"harina": 1, "huevo": 1},
+			b:        map[string]float64{"harina": 1, "huevo": 0.5},
+			esperado: 1.5 / 2,
+		},
+		{
+			nombre:   "es simétrico",
+			a:        map[string]float64{"harina": 1, "huevo": 0.5},
+			b:        map[string]float64{"harina": 1, "huevo": 1},
+			esperado: 1.5 / 2,
+		},
+		{
+			nombre:   "vacíos",
+			a:        map[string]float64{},
+			b:        map[string]float64{},
+			esperado: 0,
+		},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nombre, func(t *testing.T) {
+			if similitud := jaccardPonderado(caso.a, caso.b); math.Abs(similitud-caso.esperado) > 1e-9 {
+				t.Errorf("similitud = %v, se esperaba %v", similitud, caso.esperado)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"gocooking-backend/model"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type SobraRepositoryInterface interface {
+	GetSobras(usuarioID string) ([]model.Sobra, error)
+	GetSobraByID(id primitive.ObjectID, usuarioID string) (*model.Sobra, error)
+	InsertSobra(sobra model.Sobra) (*model.Sobra, error)
+	ComerSobra(id primitive.ObjectID, usuarioID string, porciones int) (*model.Sobra, error)
+	DeleteSobra(id primitive.ObjectID, usuarioID string) (*mongo.DeleteResult, error)
+}
+
+type SobraRepository struct {
+	db DB
+}
+
+func NewSobraRepository(db DB) *SobraRepository {
+	return &SobraRepository{
+		db: db,
+	}
+}
+
+// GetSobras devuelve las sobras del usuario, primero las que vencen antes
+func (repository SobraRepository) GetSobras(usuarioID string) ([]model.Sobra, error) {
+	collection := repository.db.GetClient().Database("gocooking").Collection("sobras")
+	cursor, err := collection.Find(context.TODO(), bson.M{"id_usuario": usuarioID}, options.Find().SetSort(bson.M{"fecha_vencimiento": 1}))
+	if err != nil {
+		return nil, err
+	}
+	sobras := []model.Sobra{}
+	if err := cursor.All(context.TODO(), &sobras); err != nil {
+		return nil, err
+	}
+	return sobras, nil
+}
+
+func (repository SobraRepository) GetSobraByID(id primitive.ObjectID, usuarioID string) (*model.Sobra, error) {
+	collection := repository.db.GetClient().Database("gocooking").Collection("sobras")
+	var sobra model.Sobra
+	err := collection.FindOne(context.TODO(), bson.M{"_id": id, "id_usuario": usuarioID}).Decode(&sobra)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("404")
+		}
+		return nil, err
+	}
+	return &sobra, nil
+}
+
+// InsertSobra guarda porciones de una receta propia; la receta tiene que existir ("404" si no)
+func (repository SobraRepository) InsertSobra(sobra model.Sobra) (*model.Sobra, error) {
+	database := repository.db.GetClient().Database("gocooking")
+	var receta model.Receta
+	err := database.Collection("recetas").FindOne(context.TODO(), bson.M{"_id": sobra.RecetaID, "id_usuario": sobra.UsuarioID}).Decode(&receta)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("404")
+		}
+		return nil, err
+	}
+
+	sobra.NombreReceta = receta.Nombre
+	sobra.FechaCreacion = time.Now()
+	resultado, err := database.Collection("sobras").InsertOne(context.TODO(), sobra)
+	if err != nil {
+		return nil, err
+	}
+	sobra.Id = resultado.InsertedID.(primitive.ObjectID)
+	return &sobra, nil
+}
+
+// ComerSobra descuenta las porciones comidas y elimina la sobra cuando no queda ninguna.
+// Devuelve "400" si entretanto quedaron menos porciones de las pedidas.
+func (repository SobraRepository) ComerSobra(id primitive.ObjectID, usuarioID string, porciones int) (*model.Sobra, error) {
+	collection := repository.db.GetClient().Database("gocooking").Collection("sobras")
+	filtro := bson.M{"_id": id, "id_usuario": usuarioID, "porciones": bson.M{"$gte": porciones}}
+	var sobra model.Sobra
+	err := collection.FindOneAndUpdate(context.TODO(), filtro, bson.M{"$inc": bson.M{"porciones": -porciones}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&sobra)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("400")
+		}
+		return nil, err
+	}
+
+	if sobra.Porciones <= 0 {
+		if _, err := collection.DeleteOne(context.TODO(), bson.M{"_id": id}); err != nil {
+			return nil, err
+		}
+	}
+	return &sobra, nil
+}
+
+func (repository SobraRepository) DeleteSobra(id primitive.ObjectID, usuarioID string) (*mongo.DeleteResult, error) {
+	resultado, err := repository.db.GetClient().Database("gocooking").Collection("sobras").DeleteOne(context.TODO(), bson.M{"_id": id, "id_usuario": usuarioID})
+	if err != nil {
+		return nil, err
+	}
+	if resultado.DeletedCount == 0 {
+		return nil, errors.New("404")
+	}
+	return resultado, nil
+}
+
+// porcionesSobrantes suma, por receta, las porciones de sobras del usuario que todavía no vencieron
+func porcionesSobrantes(db DB, usuarioID string) (map[primitive.ObjectID]int, error) {
+	filtro := bson.M{"id_usuario": usuarioID, "porciones": bson.M{"$gt": 0}, "fecha_vencimiento": bson.M{"$gt": time.Now()}}
+	cursor, err := db.GetClient().Database("gocooking").Collection("sobras").Find(context.TODO(), filtro)
+	if err != nil {
+		return nil, err
+	}
+	var sobras []model.Sobra
+	if err := cursor.All(context.TODO(), &sobras); err != nil {
+		return nil, err
+	}
+	porciones := make(map[primitive.ObjectID]int)
+	for _, sobra := range sobras {
+		porciones[sobra.RecetaID] += sobra.Porciones
+	}
+	return porciones, nil
+}
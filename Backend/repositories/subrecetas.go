@@ -0,0 +1,102 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"gocooking-backend/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ErrorRecetaInvalida indica que la receta no se puede guardar ni preparar por cómo está armada: usa una
+// subreceta o un alimento que no existe, un momento de consumo ajeno o más stock del que hay. No es un
+// fallo al acceder a la base de datos
+type ErrorRecetaInvalida struct {
+	Motivo string
+}
+
+func (err ErrorRecetaInvalida) Error() string {
+	return err.Motivo
+}
+
+// receta busca una receta del usuario para usarla como subreceta; las de otros usuarios no se pueden usar
+// porque sus ingredientes son alimentos ajenos
+func (verificador *verificadorStock) receta(id primitive.ObjectID) (*model.Receta, error) {
+	if receta, existe := verificador.recetas[id]; existe {
+		return &receta, nil
+	}
+	var receta model.Receta
+	filtro := bson.M{"_id": id, "id_usuario": verificador.usuarioID}
+	err := verificador.db.GetClient().Database("gocooking").Collection("recetas").FindOne(context.TODO(), filtro).Decode(&receta)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("404")
+		}
+		return nil, err
+	}
+	verificador.recetas[id] = receta
+	return &receta, nil
+}
+
+// ingredientes devuelve los ingredientes de la receta con cada subreceta reemplazada por sus propios
+// ingredientes, a cualquier profundidad, escalados según las porciones que se usan de ella.
+// Devuelve ErrorRecetaInvalida si una subreceta no existe o si la receta termina usándose a sí misma.
+func (verificador *verificadorStock) ingredientes(receta model.Receta) ([]model.Ingrediente, error) {
+	return verificador.expandir(receta, 1, false, map[primitive.ObjectID]bool{receta.Id: true})
+}
+
+// expandir agrega los ingredientes de la receta multiplicados por factor. Los de una subreceta opcional
+// son opcionales también. enCurso tiene las recetas que se están expandiendo, para detectar ciclos.
+func (verificador *verificadorStock) expandir(receta model.Receta, factor float64, opcional bool, enCurso map[primitive.ObjectID]bool) ([]model.Ingrediente, error) {
+	ingredientes := []model.Ingrediente{}
+	for _, ingrediente := range receta.Ingredientes {
+		if !ingrediente.EsSubreceta() {
+			ingrediente.Cantidad *= factor
+			ingrediente.Opcional = ingrediente.Opcional || opcional
+			ingredientes = append(ingredientes, ingrediente)
+			continue
+		}
+
+		if enCurso[ingrediente.RecetaId] {
+			return nil, ErrorRecetaInvalida{Motivo: "la receta " + ingrediente.Nombre + " no puede usarse como ingrediente de sí misma"}
+		}
+		subreceta, err := verificador.receta(ingrediente.RecetaId)
+		if err != nil {
+			if err.Error() == "404" {
+				return nil, ErrorRecetaInvalida{Motivo: "la receta " + ingrediente.Nombre + " usada como ingrediente no existe"}
+			}
+			return nil, err
+		}
+
+		porciones := float64(max(subreceta.Porciones, 1))
+		enCurso[subreceta.Id] = true
+		expandidos, err := verificador.expandir(*subreceta, factor*ingrediente.Cantidad/porciones, opcional || !ingrediente.Obligatorio(), enCurso)
+		delete(enCurso, subreceta.Id)
+		if err != nil {
+			return nil, err
+		}
+		ingredientes = append(ingredientes, expandidos...)
+	}
+	return ingredientes, nil
+}
+
+// agruparIngredientes suma las cantidades de los ingredientes que usan el mismo alimento, respetando el orden
+// de aparición. El resultado es opcional solo si todas sus apariciones lo son.
+func agruparIngredientes(ingredientes []model.Ingrediente) []model.Ingrediente {
+	agrupados := []model.Ingrediente{}
+	indices := make(map[primitive.ObjectID]int)
+	for _, ingrediente := range ingredientes {
+		indice, existe := indices[ingrediente.AlimentoId]
+		if !existe {
+			indices[ingrediente.AlimentoId] = len(agrupados)
+			agrupados = append(agrupados, ingrediente)
+			continue
+		}
+		agrupados[indice].Cantidad += ingrediente.Cantidad
+		agrupados[indice].Opcional = agrupados[indice].Opcional && ingrediente.Opcional
+		agrupados[indice].AGusto = agrupados[indice].AGusto && ingrediente.AGusto
+	}
+	return agrupados
+}
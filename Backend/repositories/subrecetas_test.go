@@ -0,0 +1,97 @@
+package repositories
+
+import (
+	"gocooking-backend/model"
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestIngredientesConSubrecetas(t *testing.T) {
+	harina := primitive.NewObjectID()
+	tomate := primitive.NewObjectID()
+	masa := model.Receta{Id: primitive.NewObjectID(), Nombre: "masa", Porciones: 2,
+		Ingredientes: []model.Ingrediente{{AlimentoId: harina, Nombre: "harina", Cantidad: 500}}}
+	salsa := model.Receta{Id: primitive.NewObjectID(), Nombre: "salsa", Porciones: 1,
+		Ingredientes: []model.Ingrediente{{AlimentoId: tomate, Nombre: "tomate", Cantidad: 3}}}
+	subreceta := func(receta model.Receta, porciones float64) model.Ingrediente {
+		return model.Ingrediente{RecetaId: receta.Id, Nombre: receta.Nombre, Cantidad: porciones}
+	}
+
+	// Recetas que se usan entre sí: a usa b, b usa a; c se usa a sí misma
+	a := model.Receta{Id: primitive.NewObjectID(), Nombre: "a"}
+	b := model.Receta{Id: primitive.NewObjectID(), Nombre: "b"}
+	c := model.Receta{Id: primitive.NewObjectID(), Nombre: "c"}
+	a.Ingredientes = []model.Ingrediente{subreceta(b, 1)}
+	b.Ingredientes = []model.Ingrediente{subreceta(a, 1)}
+	c.Ingredientes = []model.Ingrediente{subreceta(c, 1)}
+	// La misma subreceta dos veces en ramas distintas no es un ciclo
+	pizza := model.Receta{Id: primitive.NewObjectID(), Nombre: "pizza", Ingredientes: []model.Ingrediente{subreceta(masa, 1), subreceta(salsa, 1)}}
+	doble := model.Receta{Id: primitive.NewObjectID(), Nombre: "doble", Ingredientes: []model.Ingrediente{subreceta(pizza, 1), subreceta(masa, 2)}}
+
+	casos := []struct {
+		nombre    string
+		receta    model.Receta
+		esperados []model.Ingrediente
+		errorCon  string
+	}{
+		{
+			nombre:    "la subreceta se escala por las porciones usadas",
+			receta:    pizza,
+			esperados: []model.Ingrediente{{AlimentoId: harina, Nombre: "harina", Cantidad: 250}, {AlimentoId: tomate, Nombre: "tomate", Cantidad: 3}},
+		},
+		{
+			nombre: "una subreceta repetida en otra rama",
+			receta: doble,
+			esperados: []model.Ingrediente{{AlimentoId: harina, Nombre: "harina", Cantidad: 250}, {AlimentoId: tomate, Nombre: "tomate", Cantidad: 3},
+				{AlimentoId: harina, Nombre: "harina", Cantidad: 500}},
+		},
+		{
+			nombre:    "una subreceta opcional deja opcionales sus ingredientes",
+			receta:    model.Receta{Id: primitive.NewObjectID(), Ingredientes: []model.Ingrediente{{RecetaId: salsa.Id, Nombre: "salsa", Cantidad: 2, Opcional: true}}},
+			esperados: []model.Ingrediente{{AlimentoId: tomate, Nombre: "tomate", Cantidad: 6, Opcional: true}},
+		},
+		{
+			nombre:   "un ciclo entre dos recetas",
+			receta:   a,
+			errorCon: "no puede usarse como ingrediente de sí misma",
+		},
+		{
+			nombre:   "una receta que se usa a sí misma",
+			receta:   c,
+			errorCon: "no puede usarse como ingrediente de sí misma",
+		},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nombre, func(t *testing.T) {
+			verificador := &verificadorStock{
+				alimentos: make(map[primitive.ObjectID]model.Alimento),
+				recetas:   make(map[primitive.ObjectID]model.Receta),
+			}
+			for _, receta := range []model.Receta{masa, salsa, a, b, c, pizza, doble} {
+				verificador.recetas[receta.Id] = receta
+			}
+
+			ingredientes, err := verificador.ingredientes(caso.receta)
+			if caso.errorCon != "" {
+				if err == nil || !strings.Contains(err.Error(), caso.errorCon) {
+					t.Fatalf("error = %v, se esperaba uno con %q", err, caso.errorCon)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error inesperado: %v", err)
+			}
+			if len(ingredientes) != len(caso.esperados) {
+				t.Fatalf("ingredientes = %v, se esperaban %v", ingredientes, caso.esperados)
+			}
+			for i, esperado := range caso.esperados {
+				if ingredientes[i] != esperado {
+					t.Errorf("ingrediente %d = %+v, se esperaba %+v", i, ingredientes[i], esperado)
+				}
+			}
+		})
+	}
+}
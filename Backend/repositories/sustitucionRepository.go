@@ -0,0 +1,106 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"gocooking-backend/model"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type SustitucionRepositoryInterface interface {
+	GetSustituciones(usuarioID string, recetaID *primitive.ObjectID) ([]model.Sustitucion, error)
+	InsertSustitucion(sustitucion model.Sustitucion) (*mongo.InsertOneResult, error)
+	DeleteSustitucion(id primitive.ObjectID, usuarioID string) (*mongo.DeleteResult, error)
+}
+
+type SustitucionRepository struct {
+	db DB
+}
+
+func NewSustitucionRepository(db DB) *SustitucionRepository {
+	return &SustitucionRepository{
+		db: db,
+	}
+}
+
+// GetSustituciones devuelve las sustituciones del usuario; con receta, solo las globales y las de esa receta
+func (repository SustitucionRepository) GetSustituciones(usuarioID string, recetaID *primitive.ObjectID) ([]model.Sustitucion, error) {
+	filtro := bson.M{
+		"id_usuario": usuarioID,
+	}
+	if recetaID != nil {
+		filtro["$or"] = bson.A{
+			bson.M{"id_receta": bson.M{"$exists": false}},
+			bson.M{"id_receta": *recetaID},
+		}
+	}
+	cursor, err := repository.db.GetClient().Database("gocooking").Collection("sustituciones").Find(context.TODO(), filtro)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	sustituciones := []model.Sustitucion{}
+	if err := cursor.All(context.TODO(), &sustituciones); err != nil {
+		return nil, err
+	}
+	return sustituciones, nil
+}
+
+func (repository SustitucionRepository) InsertSustitucion(sustitucion model.Sustitucion) (*mongo.InsertOneResult, error) {
+	sustitucion.FechaCreacion = time.Now()
+	err := repository.verificarPertenencia(sustitucion)
+	if err != nil {
+		return nil, err
+	}
+
+	collection := repository.db.GetClient().Database("gocooking").Collection("sustituciones")
+	return collection.InsertOne(context.TODO(), sustitucion)
+}
+
+func (repository SustitucionRepository) DeleteSustitucion(id primitive.ObjectID, usuarioID string) (*mongo.DeleteResult, error) {
+	collection := repository.db.GetClient().Database("gocooking").Collection("sustituciones")
+
+	resultado, err := collection.DeleteOne(context.TODO(), bson.M{"_id": id, "id_usuario": usuarioID})
+	if err != nil {
+		return nil, err
+	}
+
+	// Verificar si se eliminó algún documento
+	if resultado.DeletedCount == 0 {
+		return nil, errors.New("404")
+	}
+
+	return resultado, nil
+}
+
+// verificarPertenencia controla que los dos alimentos y la receta, si se indica, sean del usuario
+func (repository SustitucionRepository) verificarPertenencia(sustitucion model.Sustitucion) error {
+	filtro := bson.M{
+		"_id":        bson.M{"$in": []primitive.ObjectID{sustitucion.AlimentoID, sustitucion.SustitutoID}},
+		"id_usuario": sustitucion.UsuarioID,
+	}
+	cantidad, err := repository.db.GetClient().Database("gocooking").Collection("alimentos").CountDocuments(context.TODO(), filtro)
+	if err != nil {
+		return err
+	}
+	if cantidad != 2 {
+		return errors.New("400")
+	}
+
+	if sustitucion.RecetaID == nil {
+		return nil
+	}
+	cantidad, err = repository.db.GetClient().Database("gocooking").Collection("recetas").CountDocuments(context.TODO(), bson.M{"_id": *sustitucion.RecetaID, "id_usuario": sustitucion.UsuarioID})
+	if err != nil {
+		return err
+	}
+	if cantidad == 0 {
+		return errors.New("400")
+	}
+	return nil
+}
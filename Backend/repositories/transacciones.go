@@ -0,0 +1,55 @@
+package repositories
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	topologiaVerificada sync.Once
+	conTransacciones    bool
+)
+
+// AdmiteTransacciones indica si el servidor es un replica set o un mongos, los únicos que admiten transacciones.
+// Se consulta una sola vez. Un mongod sin réplicas se puede iniciar como réplica de un solo nodo
+// (mongod --replSet rs0 y después rs.initiate()) para tener las operaciones de varios documentos en transacción.
+func AdmiteTransacciones(db DB) bool {
+	topologiaVerificada.Do(func() {
+		var hello bson.M
+		err := db.GetClient().Database("admin").RunCommand(context.TODO(), bson.D{{Key: "hello", Value: 1}}).Decode(&hello)
+		if err != nil {
+			log.Printf("No se pudo consultar la topología de MongoDB: %v", err)
+			return
+		}
+		_, esReplica := hello["setName"]
+		conTransacciones = esReplica || hello["msg"] == "isdbgrid"
+	})
+	return conTransacciones
+}
+
+// enTransaccion ejecuta la operación en una transacción cuando el servidor las admite. Con un mongod sin réplicas
+// se ejecuta igual pero sin ella, así que la operación debe poder repetirse sin efecto si falla a mitad de camino.
+// La operación tiene que usar el contexto que recibe para que sus lecturas y escrituras queden en la transacción.
+func enTransaccion(db DB, operacion func(ctx context.Context) error) error {
+	if !AdmiteTransacciones(db) {
+		return operacion(context.TODO())
+	}
+	session, err := db.GetClient().StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(context.TODO())
+	_, err = session.WithTransaction(context.TODO(), func(sc mongo.SessionContext) (interface{}, error) {
+		return nil, operacion(sc)
+	})
+	return err
+}
+
+// enTransaccionActiva indica si el contexto es el de una transacción de enTransaccion
+func enTransaccionActiva(ctx context.Context) bool {
+	return mongo.SessionFromContext(ctx) != nil
+}
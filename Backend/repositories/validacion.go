@@ -0,0 +1,149 @@
+package repositories
+
+import (
+	"gocooking-backend/model"
+	"gocooking-backend/utils"
+	"math"
+	"slices"
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ValidarReceta revisa la receta como lo haría InsertReceta pero sin guardarla ni detenerse en el primer
+// problema: momentos que no existen, subrecetas y alimentos inexistentes, alimentos no adecuados para
+// los momentos y los obligatorios sin stock suficiente ni sustituto, con la cantidad que falta.
+// Los datos inválidos de la receta se revisan antes, en el dto; los ingredientes sin IDs se ignoran.
+// Si la receta ya existe, el stock que descontó cuenta como disponible.
+func (repository RecetaRepository) ValidarReceta(receta model.Receta) (*model.ValidacionReceta, error) {
+	verificador, err := nuevoVerificadorStock(repository.db, receta.UsuarioID)
+	if err != nil {
+		return nil, err
+	}
+	nombresMomentos, err := nombresDeMomentos(repository.db, receta.UsuarioID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Al validar cambios a una receta guardada, lo que ella ya descontó vuelve a estar disponible, como en UpdateReceta
+	if !receta.Id.IsZero() {
+		guardada, err := repository.GetRecetaById(receta.Id)
+		if err != nil && err.Error() != "404" {
+			return nil, err
+		}
+		if guardada != nil && guardada.UsuarioID == receta.UsuarioID && !guardada.SinConsumoDeStock {
+			if err := verificador.devolver(consumoDeStock(guardada.IngredientesDescontados(), guardada.SustitucionesConsumidas, guardada.OpcionalesOmitidos)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	validacion := &model.ValidacionReceta{Problemas: []model.ProblemaReceta{}, Sustituciones: []model.SustitucionUsada{}}
+	agregar := func(problema model.ProblemaReceta) {
+		validacion.Problemas = append(validacion.Problemas, problema)
+	}
+
+	momentos := []utils.Momento{}
+	for _, momento := range receta.Momentos() {
+		if !momento.EsPredefinido() && !momento.EsPersonalizado() {
+			continue
+		}
+		if _, existe := nombresMomentos[momento]; !existe {
+			agregar(model.ProblemaReceta{Tipo: model.ProblemaMomentoInexistente, Campo: "momentos_consumo", Momento: momento,
+				Mensaje: "el momento de consumo " + strconv.Itoa(int(momento)) + " no existe"})
+			continue
+		}
+		momentos = append(momentos, momento)
+	}
+
+	// Cada ingrediente se expande por separado para saber de qué posición de la receta viene cada alimento
+	var ingredientes []model.Ingrediente
+	indices := make(map[primitive.ObjectID]int)
+	incompatibles := make(map[primitive.ObjectID]bool)
+	for i, ingrediente := range receta.Ingredientes {
+		if ingrediente.AlimentoId.IsZero() && !ingrediente.EsSubreceta() {
+			continue
+		}
+		indice := i
+		expandidos, err := verificador.expandir(model.Receta{Id: receta.Id, Ingredientes: []model.Ingrediente{ingrediente}}, 1, false,
+			map[primitive.ObjectID]bool{receta.Id: true})
+		if err != nil {
+			agregar(model.ProblemaReceta{Tipo: model.ProblemaSubreceta, Campo: "ingredientes", Indice: &indice, Mensaje: err.Error()})
+			continue
+		}
+
+		for _, expandido := range expandidos {
+			alimento, err := verificador.alimento(expandido.AlimentoId)
+			if err != nil {
+				if err.Error() != "404" {
+					return nil, err
+				}
+				// Un opcional sin alimento se omite al prepararla
+				if expandido.Obligatorio() {
+					agregar(model.ProblemaReceta{Tipo: model.ProblemaAlimentoInexistente, Campo: "ingredientes", Indice: &indice,
+						AlimentoId: expandido.AlimentoId, Mensaje: "el alimento " + expandido.Nombre + " no existe"})
+				}
+				continue
+			}
+			if _, visto := indices[alimento.Id]; !visto {
+				indices[alimento.Id] = indice
+			}
+			ingredientes = append(ingredientes, expandido)
+
+			if incompatibles[alimento.Id] {
+				continue
+			}
+			for _, momento := range receta.Momentos() {
+				if slices.Contains(momentos, momento) && !slices.Contains(alimento.MomentosDeConsumo, momento) {
+					incompatibles[alimento.Id] = true
+					agregar(model.ProblemaReceta{Tipo: model.ProblemaMomentoIncompatible, Campo: "ingredientes", Indice: &indice,
+						AlimentoId: alimento.Id, Momento: momento,
+						Mensaje: "el alimento " + alimento.Nombre + " no es adecuado para el momento de consumo " + nombresMomentos[momento]})
+				}
+			}
+		}
+	}
+
+	// El stock se revisa por alimento, sumando todo lo que la receta usa de cada uno, y se va descontando
+	// como en resolver para que un sustituto no cubra dos faltantes con las mismas unidades
+	restante := make(map[primitive.ObjectID]float64)
+	disponible := func(alimento *model.Alimento) float64 {
+		if cantidad, existe := restante[alimento.Id]; existe {
+			return cantidad
+		}
+		return alimento.CantidadActual
+	}
+	agrupados := agruparIngredientes(ingredientes)
+	for _, obligatorios := range []bool{true, false} {
+		for _, ingrediente := range agrupados {
+			if ingrediente.Obligatorio() != obligatorios {
+				continue
+			}
+			alimento, _ := verificador.alimento(ingrediente.AlimentoId)
+			if disponible(alimento) >= ingrediente.Cantidad {
+				restante[alimento.Id] = disponible(alimento) - ingrediente.Cantidad
+				continue
+			}
+			usada, err := verificador.buscarSustituto(receta.Id, *alimento, ingrediente.Cantidad, disponible)
+			if err != nil {
+				return nil, err
+			}
+			if usada != nil {
+				sustituto, _ := verificador.alimento(usada.SustitutoId)
+				restante[sustituto.Id] = disponible(sustituto) - usada.CantidadSustituto
+				validacion.Sustituciones = append(validacion.Sustituciones, *usada)
+				continue
+			}
+			if !obligatorios {
+				continue
+			}
+			indice := indices[alimento.Id]
+			faltante := math.Round((ingrediente.Cantidad-disponible(alimento))*1000) / 1000
+			agregar(model.ProblemaReceta{Tipo: model.ProblemaStockInsuficiente, Campo: "ingredientes", Indice: &indice,
+				AlimentoId: alimento.Id, Faltante: faltante,
+				Mensaje: "no hay suficiente cantidad del alimento " + alimento.Nombre + ": faltan " + strconv.FormatFloat(faltante, 'f', -1, 64)})
+			restante[alimento.Id] = 0
+		}
+	}
+	return validacion, nil
+}
@@ -0,0 +1,161 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"gocooking-backend/model"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ValoracionRepositoryInterface interface {
+	GetValoracion(recetaID primitive.ObjectID, usuarioID string) (*model.Valoracion, error)
+	UpsertValoracion(valoracion model.Valoracion) (*mongo.UpdateResult, error)
+	InsertNota(recetaID primitive.ObjectID, usuarioID string, nota model.Nota) (*model.Nota, error)
+	DeleteNota(recetaID primitive.ObjectID, usuarioID string, notaID primitive.ObjectID) (*mongo.UpdateResult, error)
+	GetRecetasFavoritas(usuarioID string) (*[]model.Receta, error)
+}
+
+type ValoracionRepository struct {
+	db DB
+}
+
+func NewValoracionRepository(db DB) *ValoracionRepository {
+	return &ValoracionRepository{
+		db: db,
+	}
+}
+
+func (repository ValoracionRepository) GetValoracion(recetaID primitive.ObjectID, usuarioID string) (*model.Valoracion, error) {
+	err := repository.verificarReceta(recetaID, usuarioID)
+	if err != nil {
+		return nil, err
+	}
+
+	var valoracion model.Valoracion
+	err = repository.db.GetClient().Database("gocooking").Collection("valoraciones").FindOne(context.TODO(), bson.M{"id_receta": recetaID, "id_usuario": usuarioID}).Decode(&valoracion)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			// La receta existe pero el usuario todavía no la valoró
+			return &model.Valoracion{RecetaID: recetaID, UsuarioID: usuarioID, Notas: []model.Nota{}}, nil
+		}
+		return nil, err
+	}
+	return &valoracion, nil
+}
+
+func (repository ValoracionRepository) UpsertValoracion(valoracion model.Valoracion) (*mongo.UpdateResult, error) {
+	err := repository.verificarReceta(valoracion.RecetaID, valoracion.UsuarioID)
+	if err != nil {
+		return nil, err
+	}
+
+	ahora := time.Now()
+	filtro := bson.M{"id_receta": valoracion.RecetaID, "id_usuario": valoracion.UsuarioID}
+	entidad := bson.M{
+		"$set": bson.M{
+			"puntuacion":          valoracion.Puntuacion,
+			"favorita":            valoracion.Favorita,
+			"fecha_actualizacion": ahora,
+		},
+		"$setOnInsert": bson.M{
+			"notas":          []model.Nota{},
+			"fecha_creacion": ahora,
+		},
+	}
+	return repository.db.GetClient().Database("gocooking").Collection("valoraciones").UpdateOne(context.TODO(), filtro, entidad, options.Update().SetUpsert(true))
+}
+
+func (repository ValoracionRepository) InsertNota(recetaID primitive.ObjectID, usuarioID string, nota model.Nota) (*model.Nota, error) {
+	err := repository.verificarReceta(recetaID, usuarioID)
+	if err != nil {
+		return nil, err
+	}
+
+	nota.Id = primitive.NewObjectID()
+	nota.FechaCreacion = time.Now()
+	filtro := bson.M{"id_receta": recetaID, "id_usuario": usuarioID}
+	entidad := bson.M{
+		"$push": bson.M{"notas": nota},
+		"$set":  bson.M{"fecha_actualizacion": nota.FechaCreacion},
+		"$setOnInsert": bson.M{
+			"puntuacion":     0,
+			"favorita":       false,
+			"fecha_creacion": nota.FechaCreacion,
+		},
+	}
+	_, err = repository.db.GetClient().Database("gocooking").Collection("valoraciones").UpdateOne(context.TODO(), filtro, entidad, options.Update().SetUpsert(true))
+	if err != nil {
+		return nil, err
+	}
+	return &nota, nil
+}
+
+func (repository ValoracionRepository) DeleteNota(recetaID primitive.ObjectID, usuarioID string, notaID primitive.ObjectID) (*mongo.UpdateResult, error) {
+	filtro := bson.M{"id_receta": recetaID, "id_usuario": usuarioID, "notas._id": notaID}
+	entidad := bson.M{
+		"$pull": bson.M{"notas": bson.M{"_id": notaID}},
+		"$set":  bson.M{"fecha_actualizacion": time.Now()},
+	}
+	resultado, err := repository.db.GetClient().Database("gocooking").Collection("valoraciones").UpdateOne(context.TODO(), filtro, entidad)
+	if err != nil {
+		return nil, err
+	}
+	if resultado.MatchedCount == 0 {
+		return nil, errors.New("404")
+	}
+	return resultado, nil
+}
+
+func (repository ValoracionRepository) GetRecetasFavoritas(usuarioID string) (*[]model.Receta, error) {
+	cursor, err := repository.db.GetClient().Database("gocooking").Collection("valoraciones").Find(context.TODO(), bson.M{"id_usuario": usuarioID, "favorita": true})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	var recetaIDs []primitive.ObjectID
+	for cursor.Next(context.Background()) {
+		var valoracion model.Valoracion
+		if err := cursor.Decode(&valoracion); err != nil {
+			return nil, err
+		}
+		recetaIDs = append(recetaIDs, valoracion.RecetaID)
+	}
+
+	recetas := []model.Receta{}
+	if len(recetaIDs) == 0 {
+		return &recetas, nil
+	}
+
+	cursorRecetas, err := repository.db.GetClient().Database("gocooking").Collection("recetas").Find(context.TODO(), bson.M{"_id": bson.M{"$in": recetaIDs}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursorRecetas.Close(context.TODO())
+
+	for cursorRecetas.Next(context.Background()) {
+		var receta model.Receta
+		if err := cursorRecetas.Decode(&receta); err != nil {
+			return nil, err
+		}
+		recetas = append(recetas, receta)
+	}
+	return &recetas, nil
+}
+
+// verificarReceta controla que la receta exista y sea del usuario antes de valorarla
+func (repository ValoracionRepository) verificarReceta(recetaID primitive.ObjectID, usuarioID string) error {
+	cantidad, err := repository.db.GetClient().Database("gocooking").Collection("recetas").CountDocuments(context.TODO(), bson.M{"_id": recetaID, "id_usuario": usuarioID})
+	if err != nil {
+		return err
+	}
+	if cantidad == 0 {
+		return errors.New("404")
+	}
+	return nil
+}
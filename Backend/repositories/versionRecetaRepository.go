@@ -0,0 +1,102 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"gocooking-backend/model"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type VersionRecetaRepositoryInterface interface {
+	GetVersiones(recetaID primitive.ObjectID) ([]model.VersionReceta, error)
+	GetVersion(recetaID primitive.ObjectID, numero int) (*model.VersionReceta, error)
+}
+
+type VersionRecetaRepository struct {
+	db DB
+}
+
+func NewVersionRecetaRepository(db DB) *VersionRecetaRepository {
+	return &VersionRecetaRepository{
+		db: db,
+	}
+}
+
+func (repository VersionRecetaRepository) GetVersiones(recetaID primitive.ObjectID) ([]model.VersionReceta, error) {
+	cursor, err := repository.db.GetClient().Database("gocooking").Collection("versiones_receta").Find(context.TODO(), bson.M{"id_receta": recetaID}, options.Find().SetSort(bson.M{"numero": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	versiones := []model.VersionReceta{}
+	if err := cursor.All(context.TODO(), &versiones); err != nil {
+		return nil, err
+	}
+	return versiones, nil
+}
+
+func (repository VersionRecetaRepository) GetVersion(recetaID primitive.ObjectID, numero int) (*model.VersionReceta, error) {
+	var version model.VersionReceta
+	err := repository.db.GetClient().Database("gocooking").Collection("versiones_receta").FindOne(context.TODO(), bson.M{"id_receta": recetaID, "numero": numero}).Decode(&version)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("404")
+		}
+		return nil, err
+	}
+	return &version, nil
+}
+
+// intentosRegistrarVersion es cuántas veces se vuelve a numerar una versión que otra modificación
+// simultánea de la misma receta ya ocupó
+const intentosRegistrarVersion = 5
+
+// registrarVersion guarda el estado actual de la receta como una nueva versión. Las recetas creadas antes
+// de que existiera el historial no tienen versiones: en ese caso se guarda primero el estado anterior.
+// El índice único de (id_receta, numero) evita que dos modificaciones simultáneas usen el mismo número;
+// la que llega segunda vuelve a numerar su versión. Dentro de una transacción no se reintenta: el error la
+// aborta, y las modificaciones simultáneas de la receta ya hacen que WithTransaction la repita entera.
+func registrarVersion(ctx context.Context, db DB, anterior *model.Receta, actual model.Receta) error {
+	if enTransaccionActiva(ctx) {
+		return insertarVersion(ctx, db, anterior, actual)
+	}
+	var err error
+	for intento := 0; intento < intentosRegistrarVersion; intento++ {
+		err = insertarVersion(ctx, db, anterior, actual)
+		if !mongo.IsDuplicateKeyError(err) {
+			return err
+		}
+	}
+	return err
+}
+
+func insertarVersion(ctx context.Context, db DB, anterior *model.Receta, actual model.Receta) error {
+	collection := db.GetClient().Database("gocooking").Collection("versiones_receta")
+	var ultima model.VersionReceta
+	numero := 0
+	err := collection.FindOne(ctx, bson.M{"id_receta": actual.Id}, options.FindOne().SetSort(bson.M{"numero": -1})).Decode(&ultima)
+	if err == nil {
+		numero = ultima.Numero
+	} else if err != mongo.ErrNoDocuments {
+		return err
+	}
+
+	var versiones []interface{}
+	if numero == 0 && anterior != nil {
+		fecha := anterior.FechaActualizacion
+		if fecha.IsZero() {
+			fecha = anterior.FechaCreacion
+		}
+		versiones = append(versiones, model.VersionReceta{RecetaID: anterior.Id, Numero: 1, Receta: *anterior, UsuarioID: anterior.UsuarioID, FechaCreacion: fecha})
+		numero++
+	}
+	versiones = append(versiones, model.VersionReceta{RecetaID: actual.Id, Numero: numero + 1, Receta: actual, UsuarioID: actual.UsuarioID, FechaCreacion: time.Now()})
+	_, err = collection.InsertMany(ctx, versiones)
+	return err
+}
@@ -1,9 +1,13 @@
 package service
 
 import (
+	"cmp"
 	"gocooking-backend/dto"
 	"gocooking-backend/repositories"
 	"gocooking-backend/utils"
+	"slices"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type AlimentoInterface interface {
@@ -12,6 +16,10 @@ type AlimentoInterface interface {
 	InsertAlimento(alimento *dto.Alimento) (bool, *utils.AppError)
 	UpdateAlimento(alimento *dto.Alimento) (bool, *utils.AppError)
 	DeleteAlimento(id string) (bool, *utils.AppError)
+	BuscarAlimentos(parametros dto.ParametrosBusqueda, usuarioID string) ([]*dto.AlimentoEncontrado, *utils.AppError)
+	Autocompletar(parametros dto.ParametrosAutocompletado, usuarioID string) ([]*dto.SugerenciaAlimento, *utils.AppError)
+	GetDuplicados(usuarioID string) ([]*dto.GrupoDuplicados, *utils.AppError)
+	FusionarAlimentos(fusion dto.FusionAlimentos, usuarioID string) (*dto.Alimento, *utils.AppError)
 }
 type AlimentoService struct {
 	alimentoRepository repositories.AlimentoRepositoryInterface
@@ -92,3 +100,151 @@ func (service *AlimentoService) DeleteAlimento(id string) (bool, *utils.AppError
 	}
 	return true, nil
 }
+
+// BuscarAlimentos devuelve los alimentos de la despensa que coinciden con el texto, del más al menos relevante
+func (service *AlimentoService) BuscarAlimentos(parametros dto.ParametrosBusqueda, usuarioID string) ([]*dto.AlimentoEncontrado, *utils.AppError) {
+	err := parametros.Validate()
+	if err != nil {
+		return nil, utils.NewAppError("ERR_400", err.Error())
+	}
+	alimentosDB, err := service.alimentoRepository.BuscarAlimentos(usuarioID, parametros.Texto, parametros.GetLimite())
+	if err != nil {
+		return nil, utils.NewAppError("ERR_500", "Error al buscar los alimentos: "+err.Error())
+	}
+	alimentos := []*dto.AlimentoEncontrado{}
+	for _, alimentoDB := range alimentosDB {
+		alimentos = append(alimentos, dto.NewAlimentoEncontrado(alimentoDB))
+	}
+	return alimentos, nil
+}
+
+// Autocompletar sugiere los alimentos que mejor completan lo escrito, tolerando errores de tipeo.
+// Los nombres del catálogo solo se sugieren si el usuario no tiene ya un alimento equivalente.
+func (service *AlimentoService) Autocompletar(parametros dto.ParametrosAutocompletado, usuarioID string) ([]*dto.SugerenciaAlimento, *utils.AppError) {
+	err := parametros.Validate()
+	if err != nil {
+		return nil, utils.NewAppError("ERR_400", err.Error())
+	}
+	alimentosDB, err := service.alimentoRepository.GetAlimentos(usuarioID)
+	if err != nil {
+		return nil, utils.NewAppError("ERR_500", "Error al obtener los alimentos: "+err.Error())
+	}
+
+	sugerencias := []*dto.SugerenciaAlimento{}
+	nombresVistos := make(map[string]bool)
+	for _, alimento := range *alimentosDB {
+		nombresVistos[utils.ClaveNombre(alimento.Nombre)] = true
+		if puntaje := utils.PuntajeAutocompletado(parametros.Texto, alimento.Nombre); puntaje > 0 {
+			sugerencias = append(sugerencias, &dto.SugerenciaAlimento{
+				Id:      utils.GetStringIDFromObjectID(alimento.Id),
+				Nombre:  alimento.Nombre,
+				Origen:  dto.OrigenDespensa,
+				Puntaje: puntaje,
+			})
+		}
+	}
+
+	if parametros.Catalogo {
+		nombres, err := service.alimentoRepository.GetNombresIngredientesCatalogo()
+		if err != nil {
+			return nil, utils.NewAppError("ERR_500", "Error al obtener los ingredientes del catálogo: "+err.Error())
+		}
+		for _, nombre := range nombres {
+			clave := utils.ClaveNombre(nombre)
+			if nombresVistos[clave] {
+				continue
+			}
+			nombresVistos[clave] = true
+			if puntaje := utils.PuntajeAutocompletado(parametros.Texto, nombre); puntaje > 0 {
+				sugerencias = append(sugerencias, &dto.SugerenciaAlimento{
+					Nombre:  nombre,
+					Origen:  dto.OrigenCatalogo,
+					Puntaje: puntaje,
+				})
+			}
+		}
+	}
+
+	// A igual puntaje van primero los alimentos que el usuario ya tiene
+	slices.SortStableFunc(sugerencias, func(a, b *dto.SugerenciaAlimento) int {
+		if a.Puntaje != b.Puntaje {
+			return cmp.Compare(b.Puntaje, a.Puntaje)
+		}
+		if a.Origen != b.Origen {
+			return cmp.Compare(b.Origen, a.Origen) // "despensa" antes que "catalogo"
+		}
+		return cmp.Compare(a.Nombre, b.Nombre)
+	})
+	if len(sugerencias) > parametros.GetLimite() {
+		sugerencias = sugerencias[:parametros.GetLimite()]
+	}
+	return sugerencias, nil
+}
+
+// GetDuplicados agrupa los alimentos de la misma categoría cuyos nombres son iguales o casi iguales ("Tomate", "tomates", "Tomatte")
+func (service *AlimentoService) GetDuplicados(usuarioID string) ([]*dto.GrupoDuplicados, *utils.AppError) {
+	alimentosDB, err := service.alimentoRepository.GetAlimentos(usuarioID)
+	if err != nil {
+		return nil, utils.NewAppError("ERR_500", "Error al obtener los alimentos: "+err.Error())
+	}
+	alimentos := *alimentosDB
+
+	// Cada alimento apunta a otro de su grupo; la raíz identifica al grupo
+	grupo := make([]int, len(alimentos))
+	for i := range grupo {
+		grupo[i] = i
+	}
+	var raiz func(i int) int
+	raiz = func(i int) int {
+		if grupo[i] != i {
+			grupo[i] = raiz(grupo[i])
+		}
+		return grupo[i]
+	}
+	for i := range alimentos {
+		for j := i + 1; j < len(alimentos); j++ {
+			if alimentos[i].CategoriaID == alimentos[j].CategoriaID && utils.Similitud(alimentos[i].Nombre, alimentos[j].Nombre) >= similitudMinimaAlimento {
+				grupo[raiz(j)] = raiz(i)
+			}
+		}
+	}
+
+	grupos := []*dto.GrupoDuplicados{}
+	indiceDeGrupo := make(map[int]int)
+	for i, alimento := range alimentos {
+		indice, existe := indiceDeGrupo[raiz(i)]
+		if !existe {
+			indice = len(grupos)
+			indiceDeGrupo[raiz(i)] = indice
+			grupos = append(grupos, &dto.GrupoDuplicados{})
+		}
+		grupos[indice].Alimentos = append(grupos[indice].Alimentos, dto.NewAlimento(alimento))
+	}
+	duplicados := []*dto.GrupoDuplicados{}
+	for _, grupo := range grupos {
+		if len(grupo.Alimentos) > 1 {
+			duplicados = append(duplicados, grupo)
+		}
+	}
+	return duplicados, nil
+}
+
+// FusionarAlimentos deja un único alimento con el stock de todos y las recetas, compras y sustituciones apuntando a él
+func (service *AlimentoService) FusionarAlimentos(fusion dto.FusionAlimentos, usuarioID string) (*dto.Alimento, *utils.AppError) {
+	err := fusion.Validate()
+	if err != nil {
+		return nil, utils.NewAppError("ERR_400", err.Error())
+	}
+	fusionados := []primitive.ObjectID{}
+	for _, id := range fusion.Fusionados {
+		fusionados = append(fusionados, utils.GetObjectIDFromStringID(id))
+	}
+	alimento, err := service.alimentoRepository.FusionarAlimentos(utils.GetObjectIDFromStringID(fusion.AlimentoId), fusionados, usuarioID)
+	if err != nil {
+		if err.Error() == "404" {
+			return nil, utils.NewAppError("ERR_404", "Alguno de los alimentos no existe o no pertenece al usuario")
+		}
+		return nil, utils.NewAppError("ERR_500", "Error al fusionar los alimentos: "+err.Error())
+	}
+	return dto.NewAlimento(*alimento), nil
+}
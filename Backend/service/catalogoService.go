@@ -0,0 +1,199 @@
+package service
+
+import (
+	"gocooking-backend/dto"
+	"gocooking-backend/model"
+	"gocooking-backend/repositories"
+	"gocooking-backend/utils"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type CatalogoInterface interface {
+	GetCatalogo(parametros dto.ParametrosListadoRecetas) ([]*dto.Receta, *utils.AppError)
+	CopiarReceta(id string, usuarioID string, opciones dto.CopiaReceta) (*dto.ResultadoCopia, *utils.AppError)
+}
+
+type CatalogoService struct {
+	recetaRepository    repositories.RecetaRepositoryInterface
+	alimentoRepository  repositories.AlimentoRepositoryInterface
+	categoriaRepository repositories.CategoriaRepositoryInterface
+}
+
+func NewCatalogoService(recetaRepository repositories.RecetaRepositoryInterface, alimentoRepository repositories.AlimentoRepositoryInterface, categoriaRepository repositories.CategoriaRepositoryInterface) *CatalogoService {
+	return &CatalogoService{
+		recetaRepository:    recetaRepository,
+		alimentoRepository:  alimentoRepository,
+		categoriaRepository: categoriaRepository,
+	}
+}
+
+func (service *CatalogoService) GetCatalogo(parametros dto.ParametrosListadoRecetas) ([]*dto.Receta, *utils.AppError) {
+	recetasDB, err := service.recetaRepository.GetRecetasPublicas(parametros)
+	if err != nil {
+		return nil, utils.NewAppError("ERR_500", "Error al obtener el catálogo: "+err.Error())
+	}
+	recetas := []*dto.Receta{}
+	for _, recetaDB := range recetasDB {
+		recetas = append(recetas, dto.NewReceta(recetaDB))
+	}
+	return recetas, nil
+}
+
+// CopiarReceta clona una receta visible para el usuario en su recetario, asociando cada ingrediente
+// al alimento propio con el mismo nombre. Los que no tiene se crean solo si se pide, con stock en cero.
+func (service *CatalogoService) CopiarReceta(id string, usuarioID string, opciones dto.CopiaReceta) (*dto.ResultadoCopia, *utils.AppError) {
+	original, err := service.recetaRepository.GetRecetaById(utils.GetObjectIDFromStringID(id))
+	if err != nil || !original.VisiblePara(usuarioID) {
+		if err == nil || err.Error() == "404" {
+			return nil, utils.NewAppError("ERR_404", "La receta no fue encontrada")
+		}
+		return nil, utils.NewAppError("ERR_500", "Error al obtener la receta: "+err.Error())
+	}
+
+	alimentosDB, err := service.alimentoRepository.GetAlimentos(usuarioID)
+	if err != nil {
+		return nil, utils.NewAppError("ERR_500", "Error al obtener los alimentos: "+err.Error())
+	}
+	alimentos := *alimentosDB
+
+	// Las subrecetas del original son del dueño: la copia lleva directamente sus alimentos
+	originales, err := service.recetaRepository.GetIngredientesBase(*original)
+	if err != nil {
+		return nil, utils.NewAppError("ERR_500", "Error al expandir las subrecetas: "+err.Error())
+	}
+
+	// Primero se resuelven todos los ingredientes, para no crear alimentos si la copia no se puede hacer
+	ingredientes := make([]model.Ingrediente, len(originales))
+	var faltantes []int
+	for i, ingrediente := range originales {
+		ingredientes[i] = ingrediente
+		alimento := buscarAlimentoPorNombre(ingrediente.Nombre, alimentos)
+		if alimento == nil {
+			faltantes = append(faltantes, i)
+			continue
+		}
+		ingredientes[i].AlimentoId = alimento.Id
+		ingredientes[i].Nombre = alimento.Nombre
+	}
+	if len(faltantes) > 0 && !opciones.CrearFaltantes {
+		var nombres []string
+		for _, i := range faltantes {
+			nombres = append(nombres, originales[i].Nombre)
+		}
+		return nil, utils.NewAppError("ERR_400", "No tenés los alimentos: "+strings.Join(nombres, ", ")+". Podés crearlos con crear_faltantes")
+	}
+
+	alimentosCreados := []string{}
+	for _, i := range faltantes {
+		// Si la receta usa el mismo alimento dos veces no se crea de nuevo
+		alimento := buscarAlimentoPorNombre(originales[i].Nombre, alimentos)
+		if alimento == nil {
+			var appErr *utils.AppError
+			alimento, appErr = service.crearAlimentoFaltante(originales[i], usuarioID)
+			if appErr != nil {
+				return nil, appErr
+			}
+			alimentosCreados = append(alimentosCreados, alimento.Nombre)
+			alimentos = append(alimentos, *alimento)
+		}
+		ingredientes[i].AlimentoId = alimento.Id
+		ingredientes[i].Nombre = alimento.Nombre
+	}
+
+	receta := model.Receta{
+		Nombre:            original.Nombre,
+		MomentoDeConsumo:  momentoPrincipal(momentosPredefinidos(original.Momentos())),
+		MomentosDeConsumo: momentosPredefinidos(original.Momentos()),
+		Ingredientes:      ingredientes,
+		Pasos:             original.Pasos,
+		Porciones:         original.Porciones,
+		TiempoPreparacion: original.TiempoPreparacion,
+		TiempoCoccion:     original.TiempoCoccion,
+		Etiquetas:         original.Etiquetas,
+		Equipamiento:      original.Equipamiento,
+		Imagenes:          []model.Imagen{},
+		Visibilidad:       utils.VisibilidadPrivada,
+		CompartidaCon:     []string{},
+		UsuarioID:         usuarioID,
+	}
+	resultado, err := service.recetaRepository.CopiarReceta(receta)
+	if err != nil {
+		return nil, utils.NewAppError("ERR_500", "Error al copiar la receta: "+err.Error())
+	}
+	receta.Id = resultado.InsertedID.(primitive.ObjectID)
+
+	return &dto.ResultadoCopia{
+		Receta:           dto.NewReceta(receta),
+		AlimentosCreados: alimentosCreados,
+	}, nil
+}
+
+// crearAlimentoFaltante crea el alimento del ingrediente copiando los datos del alimento original, sin stock
+func (service *CatalogoService) crearAlimentoFaltante(ingrediente model.Ingrediente, usuarioID string) (*model.Alimento, *utils.AppError) {
+	alimento := model.Alimento{
+		Nombre:    ingrediente.Nombre,
+		UsuarioID: usuarioID,
+	}
+	original, err := service.alimentoRepository.GetAlimentoByID(ingrediente.AlimentoId)
+	if err != nil && err.Error() != "404" {
+		return nil, utils.NewAppError("ERR_500", "Error al obtener el alimento: "+err.Error())
+	}
+	if err == nil {
+		alimento.CategoriaID, err = service.categoriaDelSistema(original.CategoriaID)
+		if err != nil {
+			return nil, utils.NewAppError("ERR_500", "Error al obtener la categoría del alimento: "+err.Error())
+		}
+		alimento.MomentosDeConsumo = momentosPredefinidos(original.MomentosDeConsumo)
+		alimento.PrecioUnitario = original.PrecioUnitario
+		alimento.CantidadMinima = original.CantidadMinima
+	}
+
+	resultado, err := service.alimentoRepository.InsertAlimento(alimento)
+	if err != nil {
+		return nil, utils.NewAppError("ERR_500", "Error al crear el alimento "+alimento.Nombre+": "+err.Error())
+	}
+	alimento.Id = resultado.InsertedID.(primitive.ObjectID)
+	return &alimento, nil
+}
+
+// categoriaDelSistema devuelve la categoría si es del sistema o, si es de otro usuario, la primera del sistema
+// que la contiene (Pollo de otro usuario pasa a Carne). Sin ninguna del sistema, el alimento queda sin categoría.
+func (service *CatalogoService) categoriaDelSistema(id primitive.ObjectID) (primitive.ObjectID, error) {
+	for visitadas := 0; !id.IsZero() && visitadas < 20; visitadas++ {
+		categoria, err := service.categoriaRepository.GetCategoriaByID(id)
+		if err != nil {
+			if err.Error() == "404" {
+				break
+			}
+			return primitive.NilObjectID, err
+		}
+		if categoria.EsDelSistema() {
+			return categoria.Id, nil
+		}
+		if categoria.PadreID == nil {
+			break
+		}
+		id = *categoria.PadreID
+	}
+	return primitive.NilObjectID, nil
+}
+
+// momentosPredefinidos descarta los momentos personalizados, que son del dueño original y no existen para quien copia
+func momentosPredefinidos(momentos []utils.Momento) []utils.Momento {
+	predefinidos := []utils.Momento{}
+	for _, momento := range momentos {
+		if momento.EsPredefinido() {
+			predefinidos = append(predefinidos, momento)
+		}
+	}
+	return predefinidos
+}
+
+func momentoPrincipal(momentos []utils.Momento) utils.Momento {
+	if len(momentos) == 0 {
+		return utils.MomentoDefault
+	}
+	return momentos[0]
+}
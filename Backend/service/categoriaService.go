@@ -0,0 +1,68 @@
+package service
+
+import (
+	"gocooking-backend/dto"
+	"gocooking-backend/repositories"
+	"gocooking-backend/utils"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type CategoriaInterface interface {
+	GetCategorias(usuarioID string) ([]*dto.Categoria, *utils.AppError)
+	InsertCategoria(categoria *dto.Categoria) (*dto.Categoria, *utils.AppError)
+	DeleteCategoria(id string, usuarioID string) (bool, *utils.AppError)
+}
+
+type CategoriaService struct {
+	categoriaRepository repositories.CategoriaRepositoryInterface
+}
+
+func NewCategoriaService(categoriaRepository repositories.CategoriaRepositoryInterface) *CategoriaService {
+	return &CategoriaService{
+		categoriaRepository: categoriaRepository,
+	}
+}
+
+// GetCategorias devuelve las categorías del sistema y las del usuario; la jerarquía se arma con padre_id
+func (service *CategoriaService) GetCategorias(usuarioID string) ([]*dto.Categoria, *utils.AppError) {
+	categoriasDB, err := service.categoriaRepository.GetCategorias(usuarioID)
+	if err != nil {
+		return nil, utils.NewAppError("ERR_500", "Error al obtener las categorías: "+err.Error())
+	}
+	categorias := []*dto.Categoria{}
+	for _, categoriaDB := range categoriasDB {
+		categorias = append(categorias, dto.NewCategoria(categoriaDB))
+	}
+	return categorias, nil
+}
+
+func (service *CategoriaService) InsertCategoria(categoria *dto.Categoria) (*dto.Categoria, *utils.AppError) {
+	err := categoria.Validate()
+	if err != nil {
+		return nil, utils.NewAppError("ERR_400", err.Error())
+	}
+	resultado, err := service.categoriaRepository.InsertCategoria(categoria.GetModel())
+	if err != nil {
+		if err.Error() == "400" {
+			return nil, utils.NewAppError("ERR_400", "La categoría padre no existe o ya hay una categoría con ese nombre en el mismo nivel")
+		}
+		return nil, utils.NewAppError("ERR_500", "Error al insertar la categoría: "+err.Error())
+	}
+	categoria.Id = utils.GetStringIDFromObjectID(resultado.InsertedID.(primitive.ObjectID))
+	return categoria, nil
+}
+
+func (service *CategoriaService) DeleteCategoria(id string, usuarioID string) (bool, *utils.AppError) {
+	_, err := service.categoriaRepository.DeleteCategoria(utils.GetObjectIDFromStringID(id), usuarioID)
+	if err != nil {
+		switch err.Error() {
+		case "404":
+			return false, utils.NewAppError("ERR_404", "La categoría no fue encontrada")
+		case "400":
+			return false, utils.NewAppError("ERR_400", "La categoría tiene subcategorías o alimentos")
+		}
+		return false, utils.NewAppError("ERR_500", "Error al eliminar la categoría: "+err.Error())
+	}
+	return true, nil
+}
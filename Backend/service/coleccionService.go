@@ -0,0 +1,98 @@
+package service
+
+import (
+	"gocooking-backend/dto"
+	"gocooking-backend/repositories"
+	"gocooking-backend/utils"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ColeccionInterface interface {
+	GetColecciones(usuarioID string) ([]*dto.Coleccion, *utils.AppError)
+	GetColeccionByID(id string, usuarioID string) (*dto.Coleccion, *utils.AppError)
+	InsertColeccion(coleccion *dto.Coleccion) (*dto.Coleccion, *utils.AppError)
+	UpdateColeccion(coleccion *dto.Coleccion) (bool, *utils.AppError)
+	DeleteColeccion(id string, usuarioID string) (bool, *utils.AppError)
+}
+
+type ColeccionService struct {
+	coleccionRepository repositories.ColeccionRepositoryInterface
+}
+
+func NewColeccionService(coleccionRepository repositories.ColeccionRepositoryInterface) *ColeccionService {
+	return &ColeccionService{
+		coleccionRepository: coleccionRepository,
+	}
+}
+
+func (service *ColeccionService) GetColecciones(usuarioID string) ([]*dto.Coleccion, *utils.AppError) {
+	coleccionesDB, err := service.coleccionRepository.GetColecciones(usuarioID)
+	if err != nil {
+		return nil, utils.NewAppError("ERR_500", "Error al obtener las colecciones: "+err.Error())
+	}
+	if len(*coleccionesDB) == 0 {
+		return nil, utils.NewAppError("ERR_404", "No se encontraron colecciones")
+	}
+	var colecciones []*dto.Coleccion
+	for _, coleccionDB := range *coleccionesDB {
+		colecciones = append(colecciones, dto.NewColeccion(coleccionDB))
+	}
+	return colecciones, nil
+}
+
+func (service *ColeccionService) GetColeccionByID(id string, usuarioID string) (*dto.Coleccion, *utils.AppError) {
+	coleccionDB, err := service.coleccionRepository.GetColeccionByID(utils.GetObjectIDFromStringID(id), usuarioID)
+	if err != nil {
+		if err.Error() == "404" {
+			return nil, utils.NewAppError("ERR_404", "La colección no fue encontrada")
+		}
+		return nil, utils.NewAppError("ERR_500", "Error al obtener la colección: "+err.Error())
+	}
+	return dto.NewColeccion(*coleccionDB), nil
+}
+
+func (service *ColeccionService) InsertColeccion(coleccion *dto.Coleccion) (*dto.Coleccion, *utils.AppError) {
+	err := coleccion.Validate()
+	if err != nil {
+		return nil, utils.NewAppError("ERR_400", err.Error())
+	}
+	resultado, err := service.coleccionRepository.InsertColeccion(coleccion.GetModel())
+	if err != nil || resultado == nil {
+		if err.Error() == "400" {
+			return nil, utils.NewAppError("ERR_400", "Alguna de las recetas no existe o no pertenece al usuario")
+		}
+		return nil, utils.NewAppError("ERR_500", "Error al insertar la colección: "+err.Error())
+	}
+	coleccion.Id = utils.GetStringIDFromObjectID(resultado.InsertedID.(primitive.ObjectID))
+	return coleccion, nil
+}
+
+func (service *ColeccionService) UpdateColeccion(coleccion *dto.Coleccion) (bool, *utils.AppError) {
+	err := coleccion.Validate()
+	if err != nil {
+		return false, utils.NewAppError("ERR_400", err.Error())
+	}
+	resultado, err := service.coleccionRepository.UpdateColeccion(coleccion.GetModel())
+	if err != nil || resultado == nil {
+		if err.Error() == "400" {
+			return false, utils.NewAppError("ERR_400", "Alguna de las recetas no existe o no pertenece al usuario")
+		}
+		if err.Error() == "404" {
+			return false, utils.NewAppError("ERR_404", "La colección no fue encontrada")
+		}
+		return false, utils.NewAppError("ERR_500", "Error al actualizar la colección: "+err.Error())
+	}
+	return true, nil
+}
+
+func (service *ColeccionService) DeleteColeccion(id string, usuarioID string) (bool, *utils.AppError) {
+	resultado, err := service.coleccionRepository.DeleteColeccion(utils.GetObjectIDFromStringID(id), usuarioID)
+	if err != nil || resultado == nil {
+		if err.Error() == "404" {
+			return false, utils.NewAppError("ERR_404", "La colección no fue encontrada")
+		}
+		return false, utils.NewAppError("ERR_500", "Error al eliminar la colección: "+err.Error())
+	}
+	return true, nil
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"gocooking-backend/dto"
+	"gocooking-backend/repositories"
+	"gocooking-backend/utils"
+)
+
+type EquipamientoInterface interface {
+	GetEquipamiento(usuarioID string) (*dto.Equipamiento, *utils.AppError)
+	UpdateEquipamiento(equipamiento *dto.Equipamiento) (*dto.Equipamiento, *utils.AppError)
+}
+
+type EquipamientoService struct {
+	equipamientoRepository repositories.EquipamientoRepositoryInterface
+}
+
+func NewEquipamientoService(equipamientoRepository repositories.EquipamientoRepositoryInterface) *EquipamientoService {
+	return &EquipamientoService{
+		equipamientoRepository: equipamientoRepository,
+	}
+}
+
+// GetEquipamiento devuelve los equipos del usuario; si nunca los cargó, una lista vacía sin configurar
+func (service *EquipamientoService) GetEquipamiento(usuarioID string) (*dto.Equipamiento, *utils.AppError) {
+	equipamientoDB, err := service.equipamientoRepository.GetEquipamiento(usuarioID)
+	if err != nil {
+		if err.Error() == "404" {
+			return dto.NewEquipamientoSinConfigurar(usuarioID), nil
+		}
+		return nil, utils.NewAppError("ERR_500", "Error al obtener el equipamiento: "+err.Error())
+	}
+	return dto.NewEquipamiento(*equipamientoDB), nil
+}
+
+func (service *EquipamientoService) UpdateEquipamiento(equipamiento *dto.Equipamiento) (*dto.Equipamiento, *utils.AppError) {
+	err := equipamiento.Validate()
+	if err != nil {
+		return nil, utils.NewAppError("ERR_400", err.Error())
+	}
+	actualizado, err := service.equipamientoRepository.UpdateEquipamiento(equipamiento.GetModel())
+	if err != nil {
+		return nil, utils.NewAppError("ERR_500", "Error al actualizar el equipamiento: "+err.Error())
+	}
+	return dto.NewEquipamiento(*actualizado), nil
+}
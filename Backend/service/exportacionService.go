@@ -0,0 +1,175 @@
+package service
+
+import (
+	"gocooking-backend/formatos"
+	"gocooking-backend/repositories"
+	"gocooking-backend/utils"
+	"strings"
+)
+
+// Formatos de exportación soportados
+const (
+	FormatoCooklang = "cooklang"
+	FormatoMarkdown = "markdown"
+	FormatoPDF      = "pdf"
+	FormatoJSONLD   = "jsonld"
+)
+
+// ArchivoExportado es el resultado de exportar una receta, listo para descargar
+type ArchivoExportado struct {
+	Nombre      string
+	ContentType string
+	Contenido   []byte
+}
+
+type ExportacionInterface interface {
+	ExportarReceta(id string, usuarioID string, formato string) (*ArchivoExportado, *utils.AppError)
+	ExportarColeccion(id string, usuarioID string) (*ArchivoExportado, *utils.AppError)
+}
+
+type ExportacionService struct {
+	recetaRepository    repositories.RecetaRepositoryInterface
+	coleccionRepository repositories.ColeccionRepositoryInterface
+	momentoRepository   repositories.MomentoRepositoryInterface
+}
+
+func NewExportacionService(recetaRepository repositories.RecetaRepositoryInterface, coleccionRepository repositories.ColeccionRepositoryInterface, momentoRepository repositories.MomentoRepositoryInterface) *ExportacionService {
+	return &ExportacionService{
+		recetaRepository:    recetaRepository,
+		coleccionRepository: coleccionRepository,
+		momentoRepository:   momentoRepository,
+	}
+}
+
+func (service *ExportacionService) ExportarReceta(id string, usuarioID string, formato string) (*ArchivoExportado, *utils.AppError) {
+	if formato == "" {
+		formato = FormatoMarkdown
+	}
+
+	receta, err := service.recetaRepository.GetRecetaById(utils.GetObjectIDFromStringID(id))
+	if err != nil || !receta.VisiblePara(usuarioID) {
+		if err == nil || err.Error() == "404" {
+			return nil, utils.NewAppError("ERR_404", "La receta no fue encontrada")
+		}
+		return nil, utils.NewAppError("ERR_500", "Error al obtener la receta: "+err.Error())
+	}
+
+	momentos, err := service.nombresDeMomentos(receta.UsuarioID)
+	if err != nil {
+		return nil, utils.NewAppError("ERR_500", "Error al obtener los momentos de consumo: "+err.Error())
+	}
+	// El costo se calcula con los precios del dueño, que es quien tiene los alimentos de la receta
+	costo, err := service.recetaRepository.GetCostoReceta(*receta)
+	if err != nil {
+		return nil, utils.NewAppError("ERR_500", "Error al calcular el costo de la receta: "+err.Error())
+	}
+	imprimible := formatos.RecetaImprimible{
+		Receta:   *receta,
+		Momentos: utils.NombresDeMomentos(receta.Momentos(), momentos),
+		Costo:    costo,
+	}
+	nombre := nombreDeArchivo(receta.Nombre)
+
+	switch formato {
+	case FormatoCooklang:
+		return &ArchivoExportado{
+			Nombre:      nombre + ".cook",
+			ContentType: "text/plain; charset=utf-8",
+			Contenido:   []byte(formatos.GenerarCooklang(imprimible)),
+		}, nil
+	case FormatoMarkdown:
+		return &ArchivoExportado{
+			Nombre:      nombre + ".md",
+			ContentType: "text/markdown; charset=utf-8",
+			Contenido:   []byte(formatos.GenerarMarkdown(imprimible)),
+		}, nil
+	case FormatoPDF:
+		return &ArchivoExportado{
+			Nombre:      nombre + ".pdf",
+			ContentType: "application/pdf",
+			Contenido:   formatos.GenerarPDF(imprimible),
+		}, nil
+	case FormatoJSONLD:
+		contenido, err := formatos.GenerarJSONLD(imprimible)
+		if err != nil {
+			return nil, utils.NewAppError("ERR_500", "Error al generar el JSON-LD: "+err.Error())
+		}
+		return &ArchivoExportado{
+			Nombre:      nombre + ".jsonld",
+			ContentType: "application/ld+json",
+			Contenido:   contenido,
+		}, nil
+	}
+	return nil, utils.NewAppError("ERR_400", "Formato de exportación no soportado: "+formato)
+}
+
+// ExportarColeccion arma un recetario en PDF con las recetas de la colección, en el orden de la colección
+func (service *ExportacionService) ExportarColeccion(id string, usuarioID string) (*ArchivoExportado, *utils.AppError) {
+	coleccion, err := service.coleccionRepository.GetColeccionByID(utils.GetObjectIDFromStringID(id), usuarioID)
+	if err != nil {
+		if err.Error() == "404" {
+			return nil, utils.NewAppError("ERR_404", "La colección no fue encontrada")
+		}
+		return nil, utils.NewAppError("ERR_500", "Error al obtener la colección: "+err.Error())
+	}
+
+	// Cada receta usa los momentos y los precios de su dueño, que pueden ser otros si la receta es compartida o pública
+	momentosPorUsuario := make(map[string]map[utils.Momento]string)
+
+	var recetas []formatos.RecetaImprimible
+	for _, recetaID := range coleccion.Recetas {
+		receta, err := service.recetaRepository.GetRecetaById(recetaID)
+		if err != nil {
+			// Las recetas borradas se quitan de las colecciones, pero se toleran referencias viejas
+			if err.Error() == "404" {
+				continue
+			}
+			return nil, utils.NewAppError("ERR_500", "Error al obtener la receta: "+err.Error())
+		}
+		momentos, existe := momentosPorUsuario[receta.UsuarioID]
+		if !existe {
+			momentos, err = service.nombresDeMomentos(receta.UsuarioID)
+			if err != nil {
+				return nil, utils.NewAppError("ERR_500", "Error al obtener los momentos de consumo: "+err.Error())
+			}
+			momentosPorUsuario[receta.UsuarioID] = momentos
+		}
+		costo, err := service.recetaRepository.GetCostoReceta(*receta)
+		if err != nil {
+			return nil, utils.NewAppError("ERR_500", "Error al calcular el costo de la receta: "+err.Error())
+		}
+		recetas = append(recetas, formatos.RecetaImprimible{
+			Receta:   *receta,
+			Momentos: utils.NombresDeMomentos(receta.Momentos(), momentos),
+			Costo:    costo,
+		})
+	}
+
+	return &ArchivoExportado{
+		Nombre:      nombreDeArchivo(coleccion.Nombre) + ".pdf",
+		ContentType: "application/pdf",
+		Contenido:   formatos.GenerarRecetarioPDF(coleccion.Nombre, coleccion.Descripcion, recetas),
+	}, nil
+}
+
+// nombresDeMomentos devuelve los nombres de los momentos personalizados del usuario
+func (service *ExportacionService) nombresDeMomentos(usuarioID string) (map[utils.Momento]string, error) {
+	momentos, err := service.momentoRepository.GetMomentos(usuarioID)
+	if err != nil {
+		return nil, err
+	}
+	nombres := make(map[utils.Momento]string)
+	for _, momento := range momentos {
+		nombres[momento.Codigo] = momento.Nombre
+	}
+	return nombres, nil
+}
+
+// nombreDeArchivo arma un nombre de archivo seguro a partir del nombre de la receta
+func nombreDeArchivo(nombre string) string {
+	archivo := strings.ReplaceAll(utils.NormalizarTexto(nombre), " ", "-")
+	if archivo == "" {
+		return "receta"
+	}
+	return archivo
+}
@@ -0,0 +1,253 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"gocooking-backend/dto"
+	"gocooking-backend/model"
+	"gocooking-backend/repositories"
+	"gocooking-backend/storage"
+	"gocooking-backend/utils"
+	"image"
+	_ "image/gif" // Registrar el decodificador de GIF
+	"image/jpeg"
+	_ "image/png" // Registrar el decodificador de PNG
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	TamanoMaximoImagen    = 5 << 20 // 5 MB
+	dimensionMaximaImagen = 8000    // Evita decodificar imágenes gigantes que agoten la memoria
+	ladoMiniatura         = 320
+)
+
+// Tipos de contenido aceptados junto con la extensión con la que se guardan
+var extensionesImagen = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+}
+
+type ImagenInterface interface {
+	InsertImagen(recetaID string, usuarioID string, tipo string, paso int, contenido []byte) (*dto.Imagen, *utils.AppError)
+	GetImagen(recetaID string, usuarioID string, imagenID string, miniatura bool) ([]byte, string, *utils.AppError)
+	DeleteImagen(recetaID string, usuarioID string, imagenID string) (bool, *utils.AppError)
+}
+
+type ImagenService struct {
+	imagenRepository repositories.ImagenRepositoryInterface
+	almacenamiento   storage.Almacenamiento
+}
+
+func NewImagenService(imagenRepository repositories.ImagenRepositoryInterface, almacenamiento storage.Almacenamiento) *ImagenService {
+	return &ImagenService{
+		imagenRepository: imagenRepository,
+		almacenamiento:   almacenamiento,
+	}
+}
+
+func (service *ImagenService) InsertImagen(recetaID string, usuarioID string, tipo string, paso int, contenido []byte) (*dto.Imagen, *utils.AppError) {
+	if len(contenido) == 0 {
+		return nil, utils.NewAppError("ERR_400", "La imagen está vacía")
+	}
+	if len(contenido) > TamanoMaximoImagen {
+		return nil, utils.NewAppError("ERR_400", "La imagen supera el tamaño máximo de "+strconv.Itoa(TamanoMaximoImagen>>20)+" MB")
+	}
+
+	// El tipo de contenido se detecta a partir de los bytes y no del header que manda el cliente
+	contentType := http.DetectContentType(contenido)
+	extension, permitido := extensionesImagen[contentType]
+	if !permitido {
+		return nil, utils.NewAppError("ERR_400", "Formato de imagen no soportado: "+contentType)
+	}
+
+	receta, err := service.imagenRepository.GetRecetaDelUsuario(utils.GetObjectIDFromStringID(recetaID), usuarioID)
+	if err != nil {
+		if err.Error() == "404" {
+			return nil, utils.NewAppError("ERR_404", "La receta no fue encontrada")
+		}
+		return nil, utils.NewAppError("ERR_500", "Error al obtener la receta: "+err.Error())
+	}
+
+	switch tipo {
+	case model.ImagenPortada:
+		paso = 0
+	case model.ImagenPaso:
+		if paso < 1 || paso > len(receta.Pasos) {
+			return nil, utils.NewAppError("ERR_400", "El número de paso no existe en la receta")
+		}
+	default:
+		return nil, utils.NewAppError("ERR_400", "El tipo de imagen debe ser portada o paso")
+	}
+
+	miniatura, err := generarMiniatura(contenido)
+	if err != nil {
+		return nil, utils.NewAppError("ERR_400", "No se pudo procesar la imagen: "+err.Error())
+	}
+
+	imagen := model.Imagen{
+		Id:            primitive.NewObjectID(),
+		Tipo:          tipo,
+		Paso:          paso,
+		ContentType:   contentType,
+		Tamano:        len(contenido),
+		FechaCreacion: time.Now(),
+	}
+	imagen.Clave = "recetas/" + utils.GetStringIDFromObjectID(receta.Id) + "/" + utils.GetStringIDFromObjectID(imagen.Id) + extension
+	imagen.ClaveMiniatura = "recetas/" + utils.GetStringIDFromObjectID(receta.Id) + "/" + utils.GetStringIDFromObjectID(imagen.Id) + "_miniatura.jpg"
+
+	err = service.almacenamiento.Guardar(imagen.Clave, contenido)
+	if err != nil {
+		return nil, utils.NewAppError("ERR_500", "Error al guardar la imagen: "+err.Error())
+	}
+	err = service.almacenamiento.Guardar(imagen.ClaveMiniatura, miniatura)
+	if err != nil {
+		service.eliminarArchivos(imagen)
+		return nil, utils.NewAppError("ERR_500", "Error al guardar la miniatura: "+err.Error())
+	}
+
+	_, err = service.imagenRepository.InsertImagen(receta.Id, imagen)
+	if err != nil {
+		service.eliminarArchivos(imagen)
+		return nil, utils.NewAppError("ERR_500", "Error al registrar la imagen: "+err.Error())
+	}
+
+	// La receta tiene una sola portada, así que la nueva reemplaza a la anterior
+	if tipo == model.ImagenPortada {
+		for _, anterior := range receta.Imagenes {
+			if anterior.Tipo == model.ImagenPortada {
+				service.DeleteImagen(recetaID, usuarioID, utils.GetStringIDFromObjectID(anterior.Id))
+			}
+		}
+	}
+
+	return dto.NewImagen(imagen), nil
+}
+
+func (service *ImagenService) GetImagen(recetaID string, usuarioID string, imagenID string, miniatura bool) ([]byte, string, *utils.AppError) {
+	imagen, appErr := service.buscarImagen(recetaID, usuarioID, imagenID)
+	if appErr != nil {
+		return nil, "", appErr
+	}
+
+	clave, contentType := imagen.Clave, imagen.ContentType
+	if miniatura {
+		clave, contentType = imagen.ClaveMiniatura, "image/jpeg"
+	}
+	contenido, err := service.almacenamiento.Obtener(clave)
+	if err != nil {
+		if err == storage.ErrNoEncontrado {
+			return nil, "", utils.NewAppError("ERR_404", "El archivo de la imagen no fue encontrado")
+		}
+		return nil, "", utils.NewAppError("ERR_500", "Error al leer la imagen: "+err.Error())
+	}
+	return contenido, contentType, nil
+}
+
+func (service *ImagenService) DeleteImagen(recetaID string, usuarioID string, imagenID string) (bool, *utils.AppError) {
+	imagen, appErr := service.buscarImagen(recetaID, usuarioID, imagenID)
+	if appErr != nil {
+		return false, appErr
+	}
+
+	_, err := service.imagenRepository.DeleteImagen(utils.GetObjectIDFromStringID(recetaID), imagen.Id)
+	if err != nil {
+		if err.Error() == "404" {
+			return false, utils.NewAppError("ERR_404", "La imagen no fue encontrada")
+		}
+		return false, utils.NewAppError("ERR_500", "Error al eliminar la imagen: "+err.Error())
+	}
+	service.eliminarArchivos(*imagen)
+	return true, nil
+}
+
+func (service *ImagenService) buscarImagen(recetaID string, usuarioID string, imagenID string) (*model.Imagen, *utils.AppError) {
+	receta, err := service.imagenRepository.GetRecetaDelUsuario(utils.GetObjectIDFromStringID(recetaID), usuarioID)
+	if err != nil {
+		if err.Error() == "404" {
+			return nil, utils.NewAppError("ERR_404", "La receta no fue encontrada")
+		}
+		return nil, utils.NewAppError("ERR_500", "Error al obtener la receta: "+err.Error())
+	}
+	for _, imagen := range receta.Imagenes {
+		if utils.GetStringIDFromObjectID(imagen.Id) == imagenID {
+			return &imagen, nil
+		}
+	}
+	return nil, utils.NewAppError("ERR_404", "La imagen no fue encontrada")
+}
+
+// eliminarArchivos borra la imagen y su miniatura del almacenamiento; los errores solo se registran
+func (service *ImagenService) eliminarArchivos(imagen model.Imagen) {
+	for _, clave := range []string{imagen.Clave, imagen.ClaveMiniatura} {
+		if err := service.almacenamiento.Eliminar(clave); err != nil {
+			log.Printf("Error al eliminar el archivo %s del almacenamiento: %v", clave, err)
+		}
+	}
+}
+
+// generarMiniatura reduce la imagen para que su lado mayor no supere ladoMiniatura y la codifica como JPEG
+func generarMiniatura(contenido []byte) ([]byte, error) {
+	configuracion, _, err := image.DecodeConfig(bytes.NewReader(contenido))
+	if err != nil {
+		return nil, err
+	}
+	if configuracion.Width > dimensionMaximaImagen || configuracion.Height > dimensionMaximaImagen {
+		return nil, errors.New("la imagen supera las dimensiones máximas permitidas")
+	}
+
+	original, _, err := image.Decode(bytes.NewReader(contenido))
+	if err != nil {
+		return nil, err
+	}
+
+	limites := original.Bounds()
+	ancho, alto := limites.Dx(), limites.Dy()
+	escala := 1.0
+	if ancho > alto && ancho > ladoMiniatura {
+		escala = float64(ladoMiniatura) / float64(ancho)
+	} else if alto >= ancho && alto > ladoMiniatura {
+		escala = float64(ladoMiniatura) / float64(alto)
+	}
+	nuevoAncho := max(1, int(float64(ancho)*escala))
+	nuevoAlto := max(1, int(float64(alto)*escala))
+
+	// Cada píxel de la miniatura promedia el bloque de píxeles de la original que le corresponde
+	miniatura := image.NewRGBA(image.Rect(0, 0, nuevoAncho, nuevoAlto))
+	for y := 0; y < nuevoAlto; y++ {
+		y0 := limites.Min.Y + y*alto/nuevoAlto
+		y1 := max(y0+1, limites.Min.Y+(y+1)*alto/nuevoAlto)
+		for x := 0; x < nuevoAncho; x++ {
+			x0 := limites.Min.X + x*ancho/nuevoAncho
+			x1 := max(x0+1, limites.Min.X+(x+1)*ancho/nuevoAncho)
+
+			var r, g, b, a, n uint64
+			for yy := y0; yy < y1; yy++ {
+				for xx := x0; xx < x1; xx++ {
+					pr, pg, pb, pa := original.At(xx, yy).RGBA()
+					r, g, b, a = r+uint64(pr), g+uint64(pg), b+uint64(pb), a+uint64(pa)
+					n++
+				}
+			}
+			// JPEG no admite transparencia, así que las zonas transparentes se componen sobre fondo blanco
+			fondo := 0xffff - a/n
+			desplazamiento := miniatura.PixOffset(x, y)
+			miniatura.Pix[desplazamiento] = uint8((r/n + fondo) >> 8)
+			miniatura.Pix[desplazamiento+1] = uint8((g/n + fondo) >> 8)
+			miniatura.Pix[desplazamiento+2] = uint8((b/n + fondo) >> 8)
+			miniatura.Pix[desplazamiento+3] = 0xff
+		}
+	}
+
+	var salida bytes.Buffer
+	err = jpeg.Encode(&salida, miniatura, &jpeg.Options{Quality: 80})
+	if err != nil {
+		return nil, err
+	}
+	return salida.Bytes(), nil
+}
@@ -0,0 +1,234 @@
+package service
+
+import (
+	"gocooking-backend/dto"
+	"gocooking-backend/formatos"
+	"gocooking-backend/model"
+	"gocooking-backend/repositories"
+	"gocooking-backend/utils"
+	"slices"
+	"strings"
+)
+
+// Similitud mínima para asociar automáticamente un ingrediente importado a un alimento del usuario
+const similitudMinimaAlimento = 0.8
+
+type ImportacionInterface interface {
+	ImportarJSONLD(contenido []byte, usuarioID string) (*dto.Receta, *utils.AppError)
+	ImportarCooklang(contenido []byte, nombreArchivo string, usuarioID string) (*dto.Receta, *utils.AppError)
+}
+
+type ImportacionService struct {
+	alimentoRepository repositories.AlimentoRepositoryInterface
+	momentoRepository  repositories.MomentoRepositoryInterface
+}
+
+func NewImportacionService(alimentoRepository repositories.AlimentoRepositoryInterface, momentoRepository repositories.MomentoRepositoryInterface) *ImportacionService {
+	return &ImportacionService{
+		alimentoRepository: alimentoRepository,
+		momentoRepository:  momentoRepository,
+	}
+}
+
+// ImportarJSONLD arma un borrador de receta a partir de un documento schema.org, sin guardarlo
+func (service *ImportacionService) ImportarJSONLD(contenido []byte, usuarioID string) (*dto.Receta, *utils.AppError) {
+	recetaJSONLD, err := formatos.ExtraerRecetaJSONLD(contenido)
+	if err != nil {
+		return nil, utils.NewAppError("ERR_400", err.Error())
+	}
+
+	alimentos, err := service.alimentoRepository.GetAlimentos(usuarioID)
+	if err != nil {
+		return nil, utils.NewAppError("ERR_500", "Error al obtener los alimentos: "+err.Error())
+	}
+	momentos, err := service.momentoRepository.GetMomentos(usuarioID)
+	if err != nil {
+		return nil, utils.NewAppError("ERR_500", "Error al obtener los momentos de consumo: "+err.Error())
+	}
+
+	receta := &dto.Receta{
+		Nombre:            recetaJSONLD.Nombre,
+		MomentosDeConsumo: momentosDesdeCategoria(recetaJSONLD.Categoria, momentos),
+		Ingredientes:      []dto.Ingrediente{},
+		Pasos:             recetaJSONLD.Pasos,
+		Porciones:         recetaJSONLD.Porciones,
+		TiempoPreparacion: recetaJSONLD.TiempoPreparacion,
+		TiempoCoccion:     recetaJSONLD.TiempoCoccion,
+		Etiquetas:         dto.NormalizarEtiquetas(recetaJSONLD.Etiquetas),
+		UsuarioID:         usuarioID,
+	}
+	for _, texto := range recetaJSONLD.Ingredientes {
+		linea := formatos.ParsearLineaIngrediente(texto)
+		ingrediente := resolverIngrediente(linea.Nombre, linea.Cantidad, linea.Unidad, *alimentos)
+		ingrediente.AGusto = ingrediente.AGusto || linea.AGusto
+		ingrediente.Opcional = linea.Opcional
+		receta.Ingredientes = append(receta.Ingredientes, ingrediente)
+	}
+	return receta, nil
+}
+
+// ImportarCooklang arma un borrador de receta a partir de un archivo .cook, sin guardarlo
+func (service *ImportacionService) ImportarCooklang(contenido []byte, nombreArchivo string, usuarioID string) (*dto.Receta, *utils.AppError) {
+	recetaCooklang := formatos.ParsearCooklang(string(contenido))
+	if len(recetaCooklang.Pasos) == 0 && len(recetaCooklang.Ingredientes) == 0 {
+		return nil, utils.NewAppError("ERR_400", "El archivo no contiene una receta Cooklang")
+	}
+
+	alimentos, err := service.alimentoRepository.GetAlimentos(usuarioID)
+	if err != nil {
+		return nil, utils.NewAppError("ERR_500", "Error al obtener los alimentos: "+err.Error())
+	}
+	momentos, err := service.momentoRepository.GetMomentos(usuarioID)
+	if err != nil {
+		return nil, utils.NewAppError("ERR_500", "Error al obtener los momentos de consumo: "+err.Error())
+	}
+
+	// Los metadatos se buscan sin importar mayúsculas, acentos ni separadores
+	metadatos := make(map[string]string)
+	for clave, valor := range recetaCooklang.Metadatos {
+		metadatos[utils.NormalizarTexto(clave)] = valor
+	}
+	metadato := func(claves ...string) string {
+		for _, clave := range claves {
+			if valor, existe := metadatos[clave]; existe {
+				return valor
+			}
+		}
+		return ""
+	}
+
+	nombre := metadato("title", "titulo", "nombre")
+	if nombre == "" {
+		nombre = strings.TrimSuffix(nombreArchivo, ".cook")
+	}
+	etiquetas := strings.Split(strings.Trim(metadato("tags", "etiquetas"), "[]"), ",")
+
+	receta := &dto.Receta{
+		Nombre:            nombre,
+		MomentosDeConsumo: momentosDesdeCategoria(metadato("momento", "course", "meal"), momentos),
+		Ingredientes:      []dto.Ingrediente{},
+		Pasos:             recetaCooklang.Pasos,
+		Porciones:         formatos.ParsearPorciones(metadato("servings", "porciones", "yield")),
+		TiempoPreparacion: formatos.ParsearMinutos(metadato("prep time", "tiempo de preparacion", "tiempo preparacion")),
+		TiempoCoccion:     formatos.ParsearMinutos(metadato("cook time", "tiempo de coccion", "tiempo coccion")),
+		Etiquetas:         dto.NormalizarEtiquetas(etiquetas),
+		Equipamiento:      equiposDesdeUtensilios(recetaCooklang.Utensilios),
+		UsuarioID:         usuarioID,
+	}
+	for _, ingrediente := range recetaCooklang.Ingredientes {
+		resuelto := resolverIngrediente(ingrediente.Nombre, ingrediente.Cantidad, ingrediente.Unidad, *alimentos)
+		resuelto.Opcional = ingrediente.Opcional
+		receta.Ingredientes = append(receta.Ingredientes, resuelto)
+	}
+	return receta, nil
+}
+
+// equiposDesdeUtensilios reconoce el equipamiento entre los utensilios de la receta; los demás, como un bol
+// o una sartén, no se guardan
+func equiposDesdeUtensilios(utensilios []string) []utils.Equipo {
+	equipos := []utils.Equipo{}
+	for _, utensilio := range utensilios {
+		equipo, existe := utils.EquipoDesdeNombre(utensilio)
+		if existe && !slices.Contains(equipos, equipo) {
+			equipos = append(equipos, equipo)
+		}
+	}
+	return equipos
+}
+
+// resolverIngrediente asocia el ingrediente al alimento del usuario con nombre más parecido, o lo marca sin resolver
+func resolverIngrediente(nombre string, cantidad float64, unidad string, alimentos []model.Alimento) dto.Ingrediente {
+	// Sin cantidad, como "@sal{}" en Cooklang, el ingrediente queda a gusto
+	ingrediente := dto.Ingrediente{
+		Nombre:   nombre,
+		Cantidad: cantidad,
+		Unidad:   unidad,
+		AGusto:   cantidad <= 0,
+	}
+	alimento := buscarAlimentoPorNombre(nombre, alimentos)
+	if alimento == nil {
+		ingrediente.SinResolver = true
+		return ingrediente
+	}
+	ingrediente.AlimentoId = utils.GetStringIDFromObjectID(alimento.Id)
+	ingrediente.Nombre = alimento.Nombre
+	return ingrediente
+}
+
+// buscarAlimentoPorNombre devuelve el alimento más parecido al nombre, o nil si ninguno es suficientemente parecido
+func buscarAlimentoPorNombre(nombre string, alimentos []model.Alimento) *model.Alimento {
+	clave := utils.ClaveNombre(nombre)
+	var mejor *model.Alimento
+	mejorPuntaje := 0.0
+	for i, alimento := range alimentos {
+		puntaje := utils.Similitud(nombre, alimento.Nombre)
+
+		// "harina 0000" o "tomate perita" siguen siendo harina y tomate
+		claveAlimento := utils.ClaveNombre(alimento.Nombre)
+		if puntaje < 0.9 && claveAlimento != "" && contienePalabras(clave, claveAlimento) {
+			puntaje = 0.9
+		}
+		if puntaje > mejorPuntaje {
+			mejor, mejorPuntaje = &alimentos[i], puntaje
+		}
+	}
+	if mejorPuntaje < similitudMinimaAlimento {
+		return nil
+	}
+	return mejor
+}
+
+// contienePalabras indica si todas las palabras de buscado aparecen en texto
+func contienePalabras(texto string, buscado string) bool {
+	palabras := make(map[string]bool)
+	for _, palabra := range strings.Fields(texto) {
+		palabras[palabra] = true
+	}
+	for _, palabra := range strings.Fields(buscado) {
+		if !palabras[palabra] {
+			return false
+		}
+	}
+	return true
+}
+
+// momentosDesdeCategoria reconoce los momentos mencionados en la categoría ("Desayuno, Merienda"). Los
+// personalizados del usuario se reconocen por su nombre completo, que es como los escribe la exportación;
+// los predefinidos, por palabras clave en español o en inglés.
+func momentosDesdeCategoria(categoria string, personalizados []model.MomentoPersonalizado) []utils.Momento {
+	palabrasClave := map[utils.Momento][]string{
+		utils.Desayuno: {"desayuno", "breakfast"},
+		utils.Almuerzo: {"almuerzo", "lunch"},
+		utils.Merienda: {"merienda", "snack"},
+		utils.Cena:     {"cena", "dinner"},
+	}
+	momentos := []utils.Momento{}
+	agregar := func(momento utils.Momento) {
+		if !slices.Contains(momentos, momento) {
+			momentos = append(momentos, momento)
+		}
+	}
+	for _, parte := range strings.Split(categoria, ",") {
+		parte = utils.NormalizarTexto(parte)
+		if parte == "" {
+			continue
+		}
+		// Un personalizado como "Cena liviana" no tiene que sumar también la cena
+		indice := slices.IndexFunc(personalizados, func(momento model.MomentoPersonalizado) bool {
+			return utils.NormalizarTexto(momento.Nombre) == parte
+		})
+		if indice >= 0 {
+			agregar(personalizados[indice].Codigo)
+			continue
+		}
+		for _, momento := range utils.MomentosPredefinidos() {
+			for _, palabra := range palabrasClave[momento] {
+				if strings.Contains(parte, palabra) {
+					agregar(momento)
+					break
+				}
+			}
+		}
+	}
+	return momentos
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"gocooking-backend/dto"
+	"gocooking-backend/repositories"
+	"gocooking-backend/utils"
+)
+
+type MomentoInterface interface {
+	GetMomentos(usuarioID string) ([]*dto.Momento, *utils.AppError)
+	InsertMomento(momento *dto.Momento) (*dto.Momento, *utils.AppError)
+	DeleteMomento(id string, usuarioID string) (bool, *utils.AppError)
+}
+
+type MomentoService struct {
+	momentoRepository repositories.MomentoRepositoryInterface
+}
+
+func NewMomentoService(momentoRepository repositories.MomentoRepositoryInterface) *MomentoService {
+	return &MomentoService{
+		momentoRepository: momentoRepository,
+	}
+}
+
+// GetMomentos devuelve los momentos predefinidos seguidos de los que creó el usuario
+func (service *MomentoService) GetMomentos(usuarioID string) ([]*dto.Momento, *utils.AppError) {
+	momentosDB, err := service.momentoRepository.GetMomentos(usuarioID)
+	if err != nil {
+		return nil, utils.NewAppError("ERR_500", "Error al obtener los momentos: "+err.Error())
+	}
+	momentos := []*dto.Momento{}
+	for _, momento := range utils.MomentosPredefinidos() {
+		momentos = append(momentos, dto.NewMomentoPredefinido(momento))
+	}
+	for _, momentoDB := range momentosDB {
+		momentos = append(momentos, dto.NewMomento(momentoDB))
+	}
+	return momentos, nil
+}
+
+func (service *MomentoService) InsertMomento(momento *dto.Momento) (*dto.Momento, *utils.AppError) {
+	err := momento.Validate()
+	if err != nil {
+		return nil, utils.NewAppError("ERR_400", err.Error())
+	}
+	creado, err := service.momentoRepository.InsertMomento(momento.GetModel())
+	if err != nil {
+		if err.Error() == "400" {
+			return nil, utils.NewAppError("ERR_400", "Ya existe un momento con ese nombre")
+		}
+		return nil, utils.NewAppError("ERR_500", "Error al insertar el momento: "+err.Error())
+	}
+	return dto.NewMomento(*creado), nil
+}
+
+func (service *MomentoService) DeleteMomento(id string, usuarioID string) (bool, *utils.AppError) {
+	_, err := service.momentoRepository.DeleteMomento(utils.GetObjectIDFromStringID(id), usuarioID)
+	if err != nil {
+		switch err.Error() {
+		case "404":
+			return false, utils.NewAppError("ERR_404", "El momento no fue encontrado")
+		case "400":
+			return false, utils.NewAppError("ERR_400", "El momento está en uso en alguna receta")
+		}
+		return false, utils.NewAppError("ERR_500", "Error al eliminar el momento: "+err.Error())
+	}
+	return true, nil
+}
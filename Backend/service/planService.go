@@ -0,0 +1,139 @@
+package service
+
+import (
+	"errors"
+	"gocooking-backend/dto"
+	"gocooking-backend/model"
+	"gocooking-backend/repositories"
+	"gocooking-backend/utils"
+	"slices"
+)
+
+type PlanInterface interface {
+	GetPlanes(usuarioID string) ([]*dto.PlanSemanal, *utils.AppError)
+	GetPlanByID(id string, usuarioID string) (*dto.PlanSemanal, *utils.AppError)
+	GenerarPlan(parametros dto.ParametrosPlan) (*dto.PlanSemanal, *utils.AppError)
+	CambiarComida(id string, usuarioID string, cambio dto.CambioComida) (*dto.PlanSemanal, *utils.AppError)
+	AceptarPlan(id string, usuarioID string) (*dto.PlanSemanal, *utils.AppError)
+	DeletePlan(id string, usuarioID string) (bool, *utils.AppError)
+}
+
+type PlanService struct {
+	planRepository repositories.PlanRepositoryInterface
+}
+
+func NewPlanService(planRepository repositories.PlanRepositoryInterface) *PlanService {
+	return &PlanService{
+		planRepository: planRepository,
+	}
+}
+
+func (service *PlanService) GetPlanes(usuarioID string) ([]*dto.PlanSemanal, *utils.AppError) {
+	planesDB, err := service.planRepository.GetPlanes(usuarioID)
+	if err != nil {
+		return nil, utils.NewAppError("ERR_500", "Error al obtener los planes: "+err.Error())
+	}
+	planes := []*dto.PlanSemanal{}
+	for _, planDB := range planesDB {
+		planes = append(planes, dto.NewPlanSemanal(planDB))
+	}
+	return planes, nil
+}
+
+func (service *PlanService) GetPlanByID(id string, usuarioID string) (*dto.PlanSemanal, *utils.AppError) {
+	plan, appErr := service.getPlan(id, usuarioID)
+	if appErr != nil {
+		return nil, appErr
+	}
+	return dto.NewPlanSemanal(*plan), nil
+}
+
+// GenerarPlan arma un menú semanal con las recetas del usuario y lo guarda como borrador
+func (service *PlanService) GenerarPlan(parametros dto.ParametrosPlan) (*dto.PlanSemanal, *utils.AppError) {
+	err := parametros.Validate()
+	if err != nil {
+		return nil, utils.NewAppError("ERR_400", err.Error())
+	}
+	plan, err := service.planRepository.GenerarPlan(parametros.GetModel())
+	if err != nil {
+		var restricciones repositories.ErrorRestriccionesPlan
+		if errors.As(err, &restricciones) {
+			return nil, utils.NewAppError("ERR_400", "No se pudo armar el plan: "+restricciones.Motivo)
+		}
+		return nil, utils.NewAppError("ERR_500", "Error al generar el plan: "+err.Error())
+	}
+	return dto.NewPlanSemanal(*plan), nil
+}
+
+// CambiarComida reemplaza la receta de una comida de un plan que todavía es borrador
+func (service *PlanService) CambiarComida(id string, usuarioID string, cambio dto.CambioComida) (*dto.PlanSemanal, *utils.AppError) {
+	err := cambio.Validate()
+	if err != nil {
+		return nil, utils.NewAppError("ERR_400", err.Error())
+	}
+	plan, appErr := service.getPlan(id, usuarioID)
+	if appErr != nil {
+		return nil, appErr
+	}
+	if plan.Estado != utils.EstadoPlanBorrador {
+		return nil, utils.NewAppError("ERR_400", "El plan ya fue aceptado y no se puede modificar")
+	}
+	existe := slices.ContainsFunc(plan.Comidas, func(comida model.ComidaPlan) bool {
+		return comida.Dia == cambio.Dia && comida.Momento == cambio.Momento && comida.Orden == cambio.Orden
+	})
+	if !existe {
+		return nil, utils.NewAppError("ERR_404", "El plan no tiene esa comida")
+	}
+
+	actualizado, err := service.planRepository.CambiarComida(*plan, cambio.Dia, cambio.Momento, cambio.Orden, utils.GetObjectIDFromStringID(cambio.RecetaId))
+	if err != nil {
+		var restricciones repositories.ErrorRestriccionesPlan
+		switch {
+		case errors.As(err, &restricciones):
+			return nil, utils.NewAppError("ERR_400", "No se pudo cambiar la comida: "+restricciones.Motivo)
+		case err.Error() == "404":
+			return nil, utils.NewAppError("ERR_404", "La receta no fue encontrada")
+		case err.Error() == "400":
+			return nil, utils.NewAppError("ERR_400", "El plan ya fue aceptado y no se puede modificar")
+		}
+		return nil, utils.NewAppError("ERR_500", "Error al cambiar la comida: "+err.Error())
+	}
+	return dto.NewPlanSemanal(*actualizado), nil
+}
+
+// AceptarPlan confirma el borrador; un plan aceptado ya no se puede modificar
+func (service *PlanService) AceptarPlan(id string, usuarioID string) (*dto.PlanSemanal, *utils.AppError) {
+	if _, appErr := service.getPlan(id, usuarioID); appErr != nil {
+		return nil, appErr
+	}
+	plan, err := service.planRepository.AceptarPlan(utils.GetObjectIDFromStringID(id), usuarioID)
+	if err != nil {
+		if err.Error() == "400" {
+			return nil, utils.NewAppError("ERR_400", "El plan ya fue aceptado")
+		}
+		return nil, utils.NewAppError("ERR_500", "Error al aceptar el plan: "+err.Error())
+	}
+	return dto.NewPlanSemanal(*plan), nil
+}
+
+func (service *PlanService) DeletePlan(id string, usuarioID string) (bool, *utils.AppError) {
+	_, err := service.planRepository.DeletePlan(utils.GetObjectIDFromStringID(id), usuarioID)
+	if err != nil {
+		if err.Error() == "404" {
+			return false, utils.NewAppError("ERR_404", "El plan no fue encontrado")
+		}
+		return false, utils.NewAppError("ERR_500", "Error al eliminar el plan: "+err.Error())
+	}
+	return true, nil
+}
+
+func (service *PlanService) getPlan(id string, usuarioID string) (*model.PlanSemanal, *utils.AppError) {
+	plan, err := service.planRepository.GetPlanByID(utils.GetObjectIDFromStringID(id), usuarioID)
+	if err != nil {
+		if err.Error() == "404" {
+			return nil, utils.NewAppError("ERR_404", "El plan no fue encontrado")
+		}
+		return nil, utils.NewAppError("ERR_500", "Error al obtener el plan: "+err.Error())
+	}
+	return plan, nil
+}
@@ -3,31 +3,48 @@ package service
 import (
 	"gocooking-backend/dto"
 	"gocooking-backend/repositories"
+	"gocooking-backend/storage"
 	"gocooking-backend/utils"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type RecetaInterface interface {
-	GetRecetas(usuarioID string) ([]*dto.Receta, *utils.AppError)
-	GetRecetaById(id string) (*dto.Receta, *utils.AppError)
+	GetRecetas(usuarioID string, parametros dto.ParametrosListadoRecetas) ([]*dto.Receta, *utils.AppError)
+	GetRecetaById(id string, usuarioID string) (*dto.Receta, *utils.AppError)
 	InsertReceta(receta *dto.Receta) (bool, *utils.AppError)
 	UpdateReceta(receta *dto.Receta) (bool, *utils.AppError)
-	DeleteReceta(id string) (bool, *utils.AppError)
+	DeleteReceta(id string, usuarioID string) (bool, *utils.AppError)
 	GetRecetasByParameters(parametros dto.ParametrosReceta, usuarioID string) ([]*dto.Receta, *utils.AppError)
 	GetCantidadRecetasPorMomento(usuarioID string) (map[string]int, *utils.AppError)
 	GetCantidadRecetasPorTipoAlimento(usuarioID string) (map[string]int, *utils.AppError)
+	GetRecetasCompartidas(usuarioID string) ([]*dto.Receta, *utils.AppError)
+	BuscarRecetas(parametros dto.ParametrosBusqueda, usuarioID string) ([]*dto.RecetaEncontrada, *utils.AppError)
+	GetCostoReceta(id string, usuarioID string) (*dto.CostoReceta, *utils.AppError)
+	GetIngredientesBase(id string, usuarioID string) ([]*dto.Ingrediente, *utils.AppError)
+	GetRecetasRecomendadas(parametros dto.ParametrosRecomendaciones, usuarioID string) ([]*dto.RecetaRecomendada, *utils.AppError)
+	ValidarReceta(receta *dto.Receta) (*dto.ValidacionReceta, *utils.AppError)
+	GetRecetasSimilares(id string, parametros dto.ParametrosSimilares, usuarioID string) ([]*dto.RecetaSimilar, *utils.AppError)
 }
 
 type RecetaService struct {
 	recetaRepository repositories.RecetaRepositoryInterface
+	almacenamiento   storage.Almacenamiento
 }
 
-func NewRecetaService(recetaRepository repositories.RecetaRepositoryInterface) *RecetaService {
+func NewRecetaService(recetaRepository repositories.RecetaRepositoryInterface, almacenamiento storage.Almacenamiento) *RecetaService {
 	return &RecetaService{
 		recetaRepository: recetaRepository,
+		almacenamiento:   almacenamiento,
 	}
 }
-func (service *RecetaService) GetRecetas(usuarioID string) ([]*dto.Receta, *utils.AppError) {
-	recetasDB, err := service.recetaRepository.GetRecetas(usuarioID)
+func (service *RecetaService) GetRecetas(usuarioID string, parametros dto.ParametrosListadoRecetas) ([]*dto.Receta, *utils.AppError) {
+	err := parametros.Validate()
+	if err != nil {
+		return nil, utils.NewAppError("ERR_400", err.Error())
+	}
+	recetasDB, err := service.recetaRepository.GetRecetas(usuarioID, parametros)
 	if err != nil {
 		return nil, utils.NewAppError("ERR_500", "Error al obtener las recetas")
 	}
@@ -42,7 +59,7 @@ func (service *RecetaService) GetRecetas(usuarioID string) ([]*dto.Receta, *util
 	return recetas, nil
 }
 
-func (service *RecetaService) GetRecetaById(id string) (*dto.Receta, *utils.AppError) {
+func (service *RecetaService) GetRecetaById(id string, usuarioID string) (*dto.Receta, *utils.AppError) {
 	recetaDB, err := service.recetaRepository.GetRecetaById(utils.GetObjectIDFromStringID(id))
 	if err != nil {
 		if err.Error() == "404" {
@@ -50,6 +67,10 @@ func (service *RecetaService) GetRecetaById(id string) (*dto.Receta, *utils.AppE
 		}
 		return nil, utils.NewAppError("ERR_500", "Error al obtener la receta")
 	}
+	// Las recetas que el usuario no puede ver se informan como inexistentes
+	if !recetaDB.VisiblePara(usuarioID) {
+		return nil, utils.NewAppError("ERR_404", "La receta no fue encontrada")
+	}
 	receta := dto.NewReceta(*recetaDB)
 	return receta, nil
 }
@@ -63,6 +84,14 @@ func (service *RecetaService) InsertReceta(receta *dto.Receta) (bool, *utils.App
 	if err != nil || resultado == nil {
 		return false, utils.NewAppError("ERR_500", "Error al insertar la receta: "+err.Error())
 	}
+
+	// Informar con qué sustitutos se cubrieron los ingredientes que faltaban
+	recetaDB, err := service.recetaRepository.GetRecetaById(resultado.InsertedID.(primitive.ObjectID))
+	if err != nil {
+		return false, utils.NewAppError("ERR_500", "Error al obtener la receta insertada: "+err.Error())
+	}
+	receta.Id = utils.GetStringIDFromObjectID(recetaDB.Id)
+	receta.Sustituciones = dto.NewSustitucionesUsadas(recetaDB.SustitucionesConsumidas)
 	return true, nil
 }
 
@@ -71,24 +100,49 @@ func (service *RecetaService) UpdateReceta(receta *dto.Receta) (bool, *utils.App
 	if err != nil {
 		return false, utils.NewAppError("ERR_400", err.Error())
 	}
-	resultado, err := service.recetaRepository.UpdateReceta(receta.GetModel())
-	if err != nil || resultado == nil {
+	sustituciones, err := service.recetaRepository.UpdateReceta(receta.GetModel())
+	if err != nil {
 		if err.Error() == "404" {
 			return false, utils.NewAppError("ERR_404", "La receta no fue encontrada")
 		}
 		return false, utils.NewAppError("ERR_500", "Error al actualizar la receta: "+err.Error())
 	}
+	// Informar con qué sustitutos se resolvió la nueva versión
+	receta.Sustituciones = dto.NewSustitucionesUsadas(sustituciones)
 	return true, nil
 }
 
-func (service *RecetaService) DeleteReceta(id string) (bool, *utils.AppError) {
-	resultado, err := service.recetaRepository.DeleteReceta(utils.GetObjectIDFromStringID(id))
+func (service *RecetaService) DeleteReceta(id string, usuarioID string) (bool, *utils.AppError) {
+	recetaDB, err := service.recetaRepository.GetRecetaById(utils.GetObjectIDFromStringID(id))
+	if err != nil {
+		if err.Error() == "404" {
+			return false, utils.NewAppError("ERR_404", "La receta no fue encontrada")
+		}
+		return false, utils.NewAppError("ERR_500", "Error al eliminar la receta: "+err.Error())
+	}
+	// Solo el dueño puede eliminar la receta, aunque otros usuarios puedan verla
+	if recetaDB.UsuarioID != usuarioID {
+		return false, utils.NewAppError("ERR_404", "La receta no fue encontrada")
+	}
+	resultado, err := service.recetaRepository.DeleteReceta(recetaDB.Id)
 	if err != nil || resultado == nil {
 		if err.Error() == "404" {
 			return false, utils.NewAppError("ERR_404", "La receta no fue encontrada")
 		}
+		if err.Error() == "400" {
+			return false, utils.NewAppError("ERR_400", "La receta se usa como ingrediente de otras recetas")
+		}
 		return false, utils.NewAppError("ERR_500", "Error al eliminar la receta: "+err.Error())
 	}
+
+	// Borrar los archivos de las imágenes que tenía la receta
+	for _, imagen := range recetaDB.Imagenes {
+		for _, clave := range []string{imagen.Clave, imagen.ClaveMiniatura} {
+			if err := service.almacenamiento.Eliminar(clave); err != nil {
+				log.Printf("Error al eliminar el archivo %s del almacenamiento: %v", clave, err)
+			}
+		}
+	}
 	return true, nil
 }
 
@@ -133,3 +187,133 @@ func (service *RecetaService) GetCantidadRecetasPorTipoAlimento(usuarioID string
 	}
 	return cantidadRecetasPorTipoAlimento, nil
 }
+
+func (service *RecetaService) GetRecetasCompartidas(usuarioID string) ([]*dto.Receta, *utils.AppError) {
+	recetasDB, err := service.recetaRepository.GetRecetasCompartidas(usuarioID)
+	if err != nil {
+		return nil, utils.NewAppError("ERR_500", "Error al obtener las recetas compartidas: "+err.Error())
+	}
+	recetas := []*dto.Receta{}
+	for _, recetaDB := range recetasDB {
+		recetas = append(recetas, dto.NewReceta(recetaDB))
+	}
+	return recetas, nil
+}
+
+// BuscarRecetas devuelve las recetas del usuario que coinciden con el texto, de la más a la menos relevante
+func (service *RecetaService) BuscarRecetas(parametros dto.ParametrosBusqueda, usuarioID string) ([]*dto.RecetaEncontrada, *utils.AppError) {
+	err := parametros.Validate()
+	if err != nil {
+		return nil, utils.NewAppError("ERR_400", err.Error())
+	}
+	recetasDB, err := service.recetaRepository.BuscarRecetas(usuarioID, parametros.Texto, parametros.GetLimite())
+	if err != nil {
+		return nil, utils.NewAppError("ERR_500", "Error al buscar las recetas: "+err.Error())
+	}
+	recetas := []*dto.RecetaEncontrada{}
+	for _, recetaDB := range recetasDB {
+		recetas = append(recetas, dto.NewRecetaEncontrada(recetaDB))
+	}
+	return recetas, nil
+}
+
+// GetCostoReceta devuelve el costo total y por porción de una receta visible para el usuario
+func (service *RecetaService) GetCostoReceta(id string, usuarioID string) (*dto.CostoReceta, *utils.AppError) {
+	recetaDB, err := service.recetaRepository.GetRecetaById(utils.GetObjectIDFromStringID(id))
+	if err != nil {
+		if err.Error() == "404" {
+			return nil, utils.NewAppError("ERR_404", "La receta no fue encontrada")
+		}
+		return nil, utils.NewAppError("ERR_500", "Error al obtener la receta")
+	}
+	if !recetaDB.VisiblePara(usuarioID) {
+		return nil, utils.NewAppError("ERR_404", "La receta no fue encontrada")
+	}
+	costo, err := service.recetaRepository.GetCostoReceta(*recetaDB)
+	if err != nil {
+		return nil, utils.NewAppError("ERR_500", "Error al calcular el costo de la receta: "+err.Error())
+	}
+	return dto.NewCostoReceta(costo), nil
+}
+
+// GetIngredientesBase devuelve todos los alimentos que lleva la receta, con las subrecetas expandidas
+func (service *RecetaService) GetIngredientesBase(id string, usuarioID string) ([]*dto.Ingrediente, *utils.AppError) {
+	recetaDB, err := service.recetaRepository.GetRecetaById(utils.GetObjectIDFromStringID(id))
+	if err != nil {
+		if err.Error() == "404" {
+			return nil, utils.NewAppError("ERR_404", "La receta no fue encontrada")
+		}
+		return nil, utils.NewAppError("ERR_500", "Error al obtener la receta")
+	}
+	if !recetaDB.VisiblePara(usuarioID) {
+		return nil, utils.NewAppError("ERR_404", "La receta no fue encontrada")
+	}
+	ingredientesDB, err := service.recetaRepository.GetIngredientesBase(*recetaDB)
+	if err != nil {
+		return nil, utils.NewAppError("ERR_500", "Error al expandir las subrecetas: "+err.Error())
+	}
+	ingredientes := []*dto.Ingrediente{}
+	for _, ingrediente := range ingredientesDB {
+		ingredientes = append(ingredientes, dto.NewIngrediente(ingrediente))
+	}
+	return ingredientes, nil
+}
+
+// GetRecetasRecomendadas devuelve las recetas propias que mejor aprovechan la despensa, de la más recomendada a la menos
+func (service *RecetaService) GetRecetasRecomendadas(parametros dto.ParametrosRecomendaciones, usuarioID string) ([]*dto.RecetaRecomendada, *utils.AppError) {
+	err := parametros.Validate()
+	if err != nil {
+		return nil, utils.NewAppError("ERR_400", err.Error())
+	}
+	recomendadasDB, err := service.recetaRepository.GetRecetasRecomendadas(usuarioID)
+	if err != nil {
+		return nil, utils.NewAppError("ERR_500", "Error al obtener las recomendaciones: "+err.Error())
+	}
+	recomendadas := []*dto.RecetaRecomendada{}
+	for _, recomendadaDB := range recomendadasDB {
+		if len(recomendadas) == parametros.GetLimite() {
+			break
+		}
+		recomendadas = append(recomendadas, dto.NewRecetaRecomendada(recomendadaDB))
+	}
+	return recomendadas, nil
+}
+
+// ValidarReceta informa todos los problemas que impedirían guardar la receta, sin guardarla ni descontar stock.
+// Si hay datos inválidos solo se revisa el stock de los ingredientes que se pueden interpretar.
+func (service *RecetaService) ValidarReceta(receta *dto.Receta) (*dto.ValidacionReceta, *utils.AppError) {
+	problemas := receta.Problemas()
+	validacion, err := service.recetaRepository.ValidarReceta(receta.GetModel())
+	if err != nil {
+		return nil, utils.NewAppError("ERR_500", "Error al validar la receta: "+err.Error())
+	}
+	validacion.Problemas = append(problemas, validacion.Problemas...)
+	return dto.NewValidacionReceta(*validacion), nil
+}
+
+// GetRecetasSimilares devuelve las recetas que más se parecen a una receta visible para el usuario
+func (service *RecetaService) GetRecetasSimilares(id string, parametros dto.ParametrosSimilares, usuarioID string) ([]*dto.RecetaSimilar, *utils.AppError) {
+	err := parametros.Validate()
+	if err != nil {
+		return nil, utils.NewAppError("ERR_400", err.Error())
+	}
+	recetaDB, err := service.recetaRepository.GetRecetaById(utils.GetObjectIDFromStringID(id))
+	if err != nil {
+		if err.Error() == "404" {
+			return nil, utils.NewAppError("ERR_404", "La receta no fue encontrada")
+		}
+		return nil, utils.NewAppError("ERR_500", "Error al obtener la receta")
+	}
+	if !recetaDB.VisiblePara(usuarioID) {
+		return nil, utils.NewAppError("ERR_404", "La receta no fue encontrada")
+	}
+	similaresDB, err := service.recetaRepository.GetRecetasSimilares(*recetaDB, usuarioID, parametros.Catalogo, parametros.GetLimite())
+	if err != nil {
+		return nil, utils.NewAppError("ERR_500", "Error al buscar recetas similares: "+err.Error())
+	}
+	similares := []*dto.RecetaSimilar{}
+	for _, similarDB := range similaresDB {
+		similares = append(similares, dto.NewRecetaSimilar(similarDB))
+	}
+	return similares, nil
+}
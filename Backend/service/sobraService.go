@@ -0,0 +1,97 @@
+package service
+
+import (
+	"gocooking-backend/dto"
+	"gocooking-backend/repositories"
+	"gocooking-backend/utils"
+	"strconv"
+	"time"
+)
+
+type SobraInterface interface {
+	GetSobras(usuarioID string) ([]*dto.Sobra, *utils.AppError)
+	InsertSobra(sobra *dto.Sobra) (*dto.Sobra, *utils.AppError)
+	ComerSobra(id string, usuarioID string, consumo dto.ConsumoSobra) (*dto.Sobra, *utils.AppError)
+	DeleteSobra(id string, usuarioID string) (bool, *utils.AppError)
+}
+
+type SobraService struct {
+	sobraRepository repositories.SobraRepositoryInterface
+}
+
+func NewSobraService(sobraRepository repositories.SobraRepositoryInterface) *SobraService {
+	return &SobraService{
+		sobraRepository: sobraRepository,
+	}
+}
+
+// GetSobras devuelve las sobras del usuario, incluidas las vencidas para que pueda descartarlas
+func (service *SobraService) GetSobras(usuarioID string) ([]*dto.Sobra, *utils.AppError) {
+	sobrasDB, err := service.sobraRepository.GetSobras(usuarioID)
+	if err != nil {
+		return nil, utils.NewAppError("ERR_500", "Error al obtener las sobras: "+err.Error())
+	}
+	sobras := []*dto.Sobra{}
+	for _, sobraDB := range sobrasDB {
+		sobras = append(sobras, dto.NewSobra(sobraDB))
+	}
+	return sobras, nil
+}
+
+// InsertSobra registra las porciones que sobraron de una receta cocinada
+func (service *SobraService) InsertSobra(sobra *dto.Sobra) (*dto.Sobra, *utils.AppError) {
+	err := sobra.Validate()
+	if err != nil {
+		return nil, utils.NewAppError("ERR_400", err.Error())
+	}
+	creada, err := service.sobraRepository.InsertSobra(sobra.GetModel())
+	if err != nil {
+		if err.Error() == "404" {
+			return nil, utils.NewAppError("ERR_404", "La receta no fue encontrada")
+		}
+		return nil, utils.NewAppError("ERR_500", "Error al guardar la sobra: "+err.Error())
+	}
+	return dto.NewSobra(*creada), nil
+}
+
+// ComerSobra descuenta las porciones comidas y devuelve cuántas quedan
+func (service *SobraService) ComerSobra(id string, usuarioID string, consumo dto.ConsumoSobra) (*dto.Sobra, *utils.AppError) {
+	err := consumo.Validate()
+	if err != nil {
+		return nil, utils.NewAppError("ERR_400", err.Error())
+	}
+	sobra, err := service.sobraRepository.GetSobraByID(utils.GetObjectIDFromStringID(id), usuarioID)
+	if err != nil {
+		if err.Error() == "404" {
+			return nil, utils.NewAppError("ERR_404", "La sobra no fue encontrada")
+		}
+		return nil, utils.NewAppError("ERR_500", "Error al obtener la sobra: "+err.Error())
+	}
+	if sobra.Vencida(time.Now()) {
+		return nil, utils.NewAppError("ERR_400", "La sobra está vencida")
+	}
+	if consumo.PorcionesAComer() > sobra.Porciones {
+		return nil, utils.NewAppError("ERR_400", "Solo quedan "+strconv.Itoa(sobra.Porciones)+" porciones")
+	}
+
+	restante, err := service.sobraRepository.ComerSobra(sobra.Id, usuarioID, consumo.PorcionesAComer())
+	if err != nil {
+		if err.Error() == "400" {
+			return nil, utils.NewAppError("ERR_400", "Ya no quedan tantas porciones")
+		}
+		return nil, utils.NewAppError("ERR_500", "Error al comer la sobra: "+err.Error())
+	}
+	return dto.NewSobra(*restante), nil
+}
+
+// DeleteSobra descarta la sobra, por ejemplo porque venció
+func (service *SobraService) DeleteSobra(id string, usuarioID string) (bool, *utils.AppError) {
+	_, err := service.sobraRepository.DeleteSobra(utils.GetObjectIDFromStringID(id), usuarioID)
+	if err != nil {
+		if err.Error() == "404" {
+			return false, utils.NewAppError("ERR_404", "La sobra no fue encontrada")
+		}
+		return false, utils.NewAppError("ERR_500", "Error al eliminar la sobra: "+err.Error())
+	}
+	return true, nil
+}
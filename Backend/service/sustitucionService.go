@@ -0,0 +1,69 @@
+package service
+
+import (
+	"gocooking-backend/dto"
+	"gocooking-backend/repositories"
+	"gocooking-backend/utils"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type SustitucionInterface interface {
+	GetSustituciones(usuarioID string, recetaID string) ([]*dto.Sustitucion, *utils.AppError)
+	InsertSustitucion(sustitucion *dto.Sustitucion) (*dto.Sustitucion, *utils.AppError)
+	DeleteSustitucion(id string, usuarioID string) (bool, *utils.AppError)
+}
+
+type SustitucionService struct {
+	sustitucionRepository repositories.SustitucionRepositoryInterface
+}
+
+func NewSustitucionService(sustitucionRepository repositories.SustitucionRepositoryInterface) *SustitucionService {
+	return &SustitucionService{
+		sustitucionRepository: sustitucionRepository,
+	}
+}
+
+func (service *SustitucionService) GetSustituciones(usuarioID string, recetaID string) ([]*dto.Sustitucion, *utils.AppError) {
+	var filtroReceta *primitive.ObjectID
+	if recetaID != "" {
+		id := utils.GetObjectIDFromStringID(recetaID)
+		filtroReceta = &id
+	}
+	sustitucionesDB, err := service.sustitucionRepository.GetSustituciones(usuarioID, filtroReceta)
+	if err != nil {
+		return nil, utils.NewAppError("ERR_500", "Error al obtener las sustituciones: "+err.Error())
+	}
+	sustituciones := []*dto.Sustitucion{}
+	for _, sustitucionDB := range sustitucionesDB {
+		sustituciones = append(sustituciones, dto.NewSustitucion(sustitucionDB))
+	}
+	return sustituciones, nil
+}
+
+func (service *SustitucionService) InsertSustitucion(sustitucion *dto.Sustitucion) (*dto.Sustitucion, *utils.AppError) {
+	err := sustitucion.Validate()
+	if err != nil {
+		return nil, utils.NewAppError("ERR_400", err.Error())
+	}
+	resultado, err := service.sustitucionRepository.InsertSustitucion(sustitucion.GetModel())
+	if err != nil {
+		if err.Error() == "400" {
+			return nil, utils.NewAppError("ERR_400", "Los alimentos y la receta de la sustitución deben ser del usuario")
+		}
+		return nil, utils.NewAppError("ERR_500", "Error al insertar la sustitución: "+err.Error())
+	}
+	sustitucion.Id = utils.GetStringIDFromObjectID(resultado.InsertedID.(primitive.ObjectID))
+	return sustitucion, nil
+}
+
+func (service *SustitucionService) DeleteSustitucion(id string, usuarioID string) (bool, *utils.AppError) {
+	_, err := service.sustitucionRepository.DeleteSustitucion(utils.GetObjectIDFromStringID(id), usuarioID)
+	if err != nil {
+		if err.Error() == "404" {
+			return false, utils.NewAppError("ERR_404", "La sustitución no fue encontrada")
+		}
+		return false, utils.NewAppError("ERR_500", "Error al eliminar la sustitución: "+err.Error())
+	}
+	return true, nil
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"gocooking-backend/dto"
+	"gocooking-backend/repositories"
+	"gocooking-backend/utils"
+)
+
+type ValoracionInterface interface {
+	GetValoracion(recetaID string, usuarioID string) (*dto.Valoracion, *utils.AppError)
+	UpsertValoracion(valoracion *dto.Valoracion) (bool, *utils.AppError)
+	InsertNota(recetaID string, usuarioID string, nota *dto.Nota) (*dto.Nota, *utils.AppError)
+	DeleteNota(recetaID string, usuarioID string, notaID string) (bool, *utils.AppError)
+	GetRecetasFavoritas(usuarioID string) ([]*dto.Receta, *utils.AppError)
+}
+
+type ValoracionService struct {
+	valoracionRepository repositories.ValoracionRepositoryInterface
+}
+
+func NewValoracionService(valoracionRepository repositories.ValoracionRepositoryInterface) *ValoracionService {
+	return &ValoracionService{
+		valoracionRepository: valoracionRepository,
+	}
+}
+
+func (service *ValoracionService) GetValoracion(recetaID string, usuarioID string) (*dto.Valoracion, *utils.AppError) {
+	valoracionDB, err := service.valoracionRepository.GetValoracion(utils.GetObjectIDFromStringID(recetaID), usuarioID)
+	if err != nil {
+		if err.Error() == "404" {
+			return nil, utils.NewAppError("ERR_404", "La receta no fue encontrada")
+		}
+		return nil, utils.NewAppError("ERR_500", "Error al obtener la valoración: "+err.Error())
+	}
+	return dto.NewValoracion(*valoracionDB), nil
+}
+
+func (service *ValoracionService) UpsertValoracion(valoracion *dto.Valoracion) (bool, *utils.AppError) {
+	err := valoracion.Validate()
+	if err != nil {
+		return false, utils.NewAppError("ERR_400", err.Error())
+	}
+	resultado, err := service.valoracionRepository.UpsertValoracion(valoracion.GetModel())
+	if err != nil || resultado == nil {
+		if err.Error() == "404" {
+			return false, utils.NewAppError("ERR_404", "La receta no fue encontrada")
+		}
+		return false, utils.NewAppError("ERR_500", "Error al guardar la valoración: "+err.Error())
+	}
+	return true, nil
+}
+
+func (service *ValoracionService) InsertNota(recetaID string, usuarioID string, nota *dto.Nota) (*dto.Nota, *utils.AppError) {
+	err := nota.Validate()
+	if err != nil {
+		return nil, utils.NewAppError("ERR_400", err.Error())
+	}
+	notaDB, err := service.valoracionRepository.InsertNota(utils.GetObjectIDFromStringID(recetaID), usuarioID, nota.GetModel())
+	if err != nil {
+		if err.Error() == "404" {
+			return nil, utils.NewAppError("ERR_404", "La receta no fue encontrada")
+		}
+		return nil, utils.NewAppError("ERR_500", "Error al guardar la nota: "+err.Error())
+	}
+	return dto.NewNota(*notaDB), nil
+}
+
+func (service *ValoracionService) DeleteNota(recetaID string, usuarioID string, notaID string) (bool, *utils.AppError) {
+	resultado, err := service.valoracionRepository.DeleteNota(utils.GetObjectIDFromStringID(recetaID), usuarioID, utils.GetObjectIDFromStringID(notaID))
+	if err != nil || resultado == nil {
+		if err.Error() == "404" {
+			return false, utils.NewAppError("ERR_404", "La nota no fue encontrada")
+		}
+		return false, utils.NewAppError("ERR_500", "Error al eliminar la nota: "+err.Error())
+	}
+	return true, nil
+}
+
+func (service *ValoracionService) GetRecetasFavoritas(usuarioID string) ([]*dto.Receta, *utils.AppError) {
+	recetasDB, err := service.valoracionRepository.GetRecetasFavoritas(usuarioID)
+	if err != nil {
+		return nil, utils.NewAppError("ERR_500", "Error al obtener las recetas favoritas: "+err.Error())
+	}
+	if len(*recetasDB) == 0 {
+		return nil, utils.NewAppError("ERR_404", "No se encontraron recetas favoritas")
+	}
+	var recetas []*dto.Receta
+	for _, recetaDB := range *recetasDB {
+		recetas = append(recetas, dto.NewReceta(recetaDB))
+	}
+	return recetas, nil
+}
@@ -0,0 +1,271 @@
+package service
+
+import (
+	"errors"
+	"gocooking-backend/dto"
+	"gocooking-backend/model"
+	"gocooking-backend/repositories"
+	"gocooking-backend/utils"
+	"reflect"
+	"strconv"
+)
+
+type VersionRecetaInterface interface {
+	GetVersiones(id string, usuarioID string) ([]*dto.VersionReceta, *utils.AppError)
+	GetVersion(id string, numero int, usuarioID string) (*dto.VersionReceta, *utils.AppError)
+	GetDiff(id string, parametros dto.ParametrosDiffVersiones, usuarioID string) (*dto.DiffReceta, *utils.AppError)
+	Revertir(id string, numero int, usuarioID string) (*dto.Receta, *utils.AppError)
+}
+
+type VersionRecetaService struct {
+	versionRecetaRepository repositories.VersionRecetaRepositoryInterface
+	recetaRepository        repositories.RecetaRepositoryInterface
+}
+
+func NewVersionRecetaService(versionRecetaRepository repositories.VersionRecetaRepositoryInterface, recetaRepository repositories.RecetaRepositoryInterface) *VersionRecetaService {
+	return &VersionRecetaService{
+		versionRecetaRepository: versionRecetaRepository,
+		recetaRepository:        recetaRepository,
+	}
+}
+
+func (service *VersionRecetaService) GetVersiones(id string, usuarioID string) ([]*dto.VersionReceta, *utils.AppError) {
+	versionesDB, appErr := service.getVersiones(id, usuarioID)
+	if appErr != nil {
+		return nil, appErr
+	}
+	versiones := []*dto.VersionReceta{}
+	for _, versionDB := range versionesDB {
+		versiones = append(versiones, dto.NewVersionReceta(versionDB))
+	}
+	return versiones, nil
+}
+
+func (service *VersionRecetaService) GetVersion(id string, numero int, usuarioID string) (*dto.VersionReceta, *utils.AppError) {
+	version, appErr := service.getVersion(id, numero, usuarioID)
+	if appErr != nil {
+		return nil, appErr
+	}
+	return dto.NewVersionReceta(*version), nil
+}
+
+func (service *VersionRecetaService) GetDiff(id string, parametros dto.ParametrosDiffVersiones, usuarioID string) (*dto.DiffReceta, *utils.AppError) {
+	err := parametros.Validate()
+	if err != nil {
+		return nil, utils.NewAppError("ERR_400", err.Error())
+	}
+	versiones, appErr := service.getVersiones(id, usuarioID)
+	if appErr != nil {
+		return nil, appErr
+	}
+	if len(versiones) == 0 {
+		return nil, utils.NewAppError("ERR_404", "La receta no tiene versiones")
+	}
+
+	hasta := parametros.Hasta
+	if hasta == 0 {
+		hasta = versiones[len(versiones)-1].Numero
+	}
+	desde := parametros.Desde
+	if desde == 0 {
+		desde = hasta - 1
+	}
+
+	anterior := buscarVersion(versiones, desde)
+	nueva := buscarVersion(versiones, hasta)
+	if anterior == nil || nueva == nil {
+		return nil, utils.NewAppError("ERR_404", "La versión "+strconv.Itoa(desde)+" o "+strconv.Itoa(hasta)+" no existe")
+	}
+	return compararVersiones(*anterior, *nueva), nil
+}
+
+// Revertir vuelve la receta al contenido de una versión anterior. Se guarda como una modificación más,
+// con las mismas validaciones, así que queda registrada como una nueva versión.
+func (service *VersionRecetaService) Revertir(id string, numero int, usuarioID string) (*dto.Receta, *utils.AppError) {
+	version, appErr := service.getVersion(id, numero, usuarioID)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	receta := dto.NewReceta(version.Receta)
+	receta.UsuarioID = usuarioID
+	err := receta.Validate()
+	if err != nil {
+		return nil, utils.NewAppError("ERR_400", "La versión no se puede restaurar: "+err.Error())
+	}
+	_, err = service.recetaRepository.UpdateReceta(receta.GetModel())
+	if err != nil {
+		var invalida repositories.ErrorRecetaInvalida
+		switch {
+		case errors.As(err, &invalida):
+			return nil, utils.NewAppError("ERR_400", "La versión no se puede restaurar: "+invalida.Motivo)
+		case err.Error() == "404":
+			return nil, utils.NewAppError("ERR_404", "La receta no fue encontrada")
+		}
+		return nil, utils.NewAppError("ERR_500", "Error al restaurar la versión: "+err.Error())
+	}
+
+	actual, err := service.recetaRepository.GetRecetaById(version.RecetaID)
+	if err != nil {
+		return nil, utils.NewAppError("ERR_500", "Error al obtener la receta: "+err.Error())
+	}
+	return dto.NewReceta(*actual), nil
+}
+
+// getVersiones devuelve el historial, que solo puede ver el dueño de la receta
+func (service *VersionRecetaService) getVersiones(id string, usuarioID string) ([]model.VersionReceta, *utils.AppError) {
+	appErr := service.verificarDueno(id, usuarioID)
+	if appErr != nil {
+		return nil, appErr
+	}
+	versiones, err := service.versionRecetaRepository.GetVersiones(utils.GetObjectIDFromStringID(id))
+	if err != nil {
+		return nil, utils.NewAppError("ERR_500", "Error al obtener las versiones: "+err.Error())
+	}
+	return versiones, nil
+}
+
+func (service *VersionRecetaService) getVersion(id string, numero int, usuarioID string) (*model.VersionReceta, *utils.AppError) {
+	appErr := service.verificarDueno(id, usuarioID)
+	if appErr != nil {
+		return nil, appErr
+	}
+	version, err := service.versionRecetaRepository.GetVersion(utils.GetObjectIDFromStringID(id), numero)
+	if err != nil {
+		if err.Error() == "404" {
+			return nil, utils.NewAppError("ERR_404", "La versión no existe")
+		}
+		return nil, utils.NewAppError("ERR_500", "Error al obtener la versión: "+err.Error())
+	}
+	return version, nil
+}
+
+func (service *VersionRecetaService) verificarDueno(id string, usuarioID string) *utils.AppError {
+	receta, err := service.recetaRepository.GetRecetaById(utils.GetObjectIDFromStringID(id))
+	if err != nil || receta.UsuarioID != usuarioID {
+		if err == nil || err.Error() == "404" {
+			return utils.NewAppError("ERR_404", "La receta no fue encontrada")
+		}
+		return utils.NewAppError("ERR_500", "Error al obtener la receta: "+err.Error())
+	}
+	return nil
+}
+
+func buscarVersion(versiones []model.VersionReceta, numero int) *model.VersionReceta {
+	for i, version := range versiones {
+		if version.Numero == numero {
+			return &versiones[i]
+		}
+	}
+	return nil
+}
+
+// compararVersiones arma el diff campo por campo; los ingredientes se comparan por alimento
+func compararVersiones(anterior model.VersionReceta, nueva model.VersionReceta) *dto.DiffReceta {
+	diff := &dto.DiffReceta{
+		Desde:                   anterior.Numero,
+		Hasta:                   nueva.Numero,
+		Campos:                  []dto.CambioCampo{},
+		IngredientesAgregados:   []dto.Ingrediente{},
+		IngredientesQuitados:    []dto.Ingrediente{},
+		IngredientesModificados: []dto.CambioIngrediente{},
+	}
+
+	a, n := dto.NewReceta(anterior.Receta), dto.NewReceta(nueva.Receta)
+	campos := []struct {
+		nombre          string
+		anterior, nuevo interface{}
+	}{
+		{"nombre", a.Nombre, n.Nombre},
+		{"momentos_consumo", a.MomentosDeConsumo, n.MomentosDeConsumo},
+		{"pasos", a.Pasos, n.Pasos},
+		{"porciones", a.Porciones, n.Porciones},
+		{"tiempo_preparacion", a.TiempoPreparacion, n.TiempoPreparacion},
+		{"tiempo_coccion", a.TiempoCoccion, n.TiempoCoccion},
+		{"etiquetas", a.Etiquetas, n.Etiquetas},
+		{"equipamiento", a.Equipamiento, n.Equipamiento},
+		{"visibilidad", a.Visibilidad, n.Visibilidad},
+		{"compartida_con", a.CompartidaCon, n.CompartidaCon},
+	}
+	for _, campo := range campos {
+		if !reflect.DeepEqual(normalizarVacio(campo.anterior), normalizarVacio(campo.nuevo)) {
+			diff.Campos = append(diff.Campos, dto.CambioCampo{Campo: campo.nombre, Anterior: campo.anterior, Nuevo: campo.nuevo})
+		}
+	}
+
+	// Un alimento o subreceta repetido en la receta se informa una sola vez, con las cantidades sumadas
+	ingredientesAnteriores := agruparPorAlimento(a.Ingredientes)
+	ingredientesNuevos := agruparPorAlimento(n.Ingredientes)
+	informados := make(map[string]bool)
+	for _, ingrediente := range a.Ingredientes {
+		clave := claveIngrediente(ingrediente)
+		if _, sigue := ingredientesNuevos[clave]; !sigue && !informados[clave] {
+			diff.IngredientesQuitados = append(diff.IngredientesQuitados, ingredientesAnteriores[clave])
+			informados[clave] = true
+		}
+	}
+	for _, ingrediente := range n.Ingredientes {
+		clave := claveIngrediente(ingrediente)
+		if informados[clave] {
+			continue
+		}
+		informados[clave] = true
+
+		actual := ingredientesNuevos[clave]
+		previo, existia := ingredientesAnteriores[clave]
+		if !existia {
+			diff.IngredientesAgregados = append(diff.IngredientesAgregados, actual)
+			continue
+		}
+		if previo.Cantidad != actual.Cantidad || previo.Unidad != actual.Unidad || previo.Opcional != actual.Opcional || previo.AGusto != actual.AGusto {
+			diff.IngredientesModificados = append(diff.IngredientesModificados, dto.CambioIngrediente{
+				AlimentoId:       actual.AlimentoId,
+				RecetaId:         actual.RecetaId,
+				Nombre:           actual.Nombre,
+				CantidadAnterior: previo.Cantidad,
+				CantidadNueva:    actual.Cantidad,
+				UnidadAnterior:   previo.Unidad,
+				UnidadNueva:      actual.Unidad,
+				OpcionalAnterior: previo.Opcional,
+				OpcionalNuevo:    actual.Opcional,
+				AGustoAnterior:   previo.AGusto,
+				AGustoNuevo:      actual.AGusto,
+			})
+		}
+	}
+	return diff
+}
+
+// agruparPorAlimento suma las cantidades de los ingredientes que usan el mismo alimento o subreceta
+func agruparPorAlimento(ingredientes []dto.Ingrediente) map[string]dto.Ingrediente {
+	agrupados := make(map[string]dto.Ingrediente)
+	for _, ingrediente := range ingredientes {
+		clave := claveIngrediente(ingrediente)
+		if existente, existe := agrupados[clave]; existe {
+			existente.Cantidad += ingrediente.Cantidad
+			agrupados[clave] = existente
+			continue
+		}
+		agrupados[clave] = ingrediente
+	}
+	return agrupados
+}
+
+// claveIngrediente identifica al ingrediente por su alimento o, si es una subreceta, por la receta
+func claveIngrediente(ingrediente dto.Ingrediente) string {
+	if ingrediente.RecetaId != "" {
+		return "receta:" + ingrediente.RecetaId
+	}
+	return ingrediente.AlimentoId
+}
+
+// normalizarVacio hace que una lista nula y una vacía se consideren iguales
+func normalizarVacio(valor interface{}) interface{} {
+	if lista, esLista := valor.([]string); esLista && len(lista) == 0 {
+		return []string{}
+	}
+	if lista, esLista := valor.([]utils.Equipo); esLista && len(lista) == 0 {
+		return []utils.Equipo{}
+	}
+	return valor
+}
@@ -0,0 +1,13 @@
+package storage
+
+import "errors"
+
+// ErrNoEncontrado se devuelve cuando no existe un archivo para la clave pedida
+var ErrNoEncontrado = errors.New("404")
+
+// Almacenamiento abstrae dónde se guardan los archivos subidos por los usuarios
+type Almacenamiento interface {
+	Guardar(clave string, contenido []byte) error
+	Obtener(clave string) ([]byte, error)
+	Eliminar(clave string) error
+}
@@ -0,0 +1,72 @@
+package storage
+
+import (
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// AlmacenamientoLocal guarda los archivos en un directorio del sistema de archivos
+type AlmacenamientoLocal struct {
+	directorio string
+}
+
+// NewAlmacenamientoLocal crea el almacenamiento en el directorio indicado, creándolo si no existe
+func NewAlmacenamientoLocal(directorio string) (*AlmacenamientoLocal, error) {
+	err := os.MkdirAll(directorio, 0o755)
+	if err != nil {
+		log.Printf("Error al crear el directorio de almacenamiento %s: %v", directorio, err)
+		return nil, err
+	}
+	return &AlmacenamientoLocal{
+		directorio: directorio,
+	}, nil
+}
+
+func (almacenamiento *AlmacenamientoLocal) Guardar(clave string, contenido []byte) error {
+	ruta, err := almacenamiento.ruta(clave)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(ruta), 0o755)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(ruta, contenido, 0o644)
+}
+
+func (almacenamiento *AlmacenamientoLocal) Obtener(clave string) ([]byte, error) {
+	ruta, err := almacenamiento.ruta(clave)
+	if err != nil {
+		return nil, err
+	}
+	contenido, err := os.ReadFile(ruta)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNoEncontrado
+	}
+	return contenido, err
+}
+
+func (almacenamiento *AlmacenamientoLocal) Eliminar(clave string) error {
+	ruta, err := almacenamiento.ruta(clave)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(ruta)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// ruta traduce la clave a una ruta dentro del directorio, rechazando claves que intenten salir de él
+func (almacenamiento *AlmacenamientoLocal) ruta(clave string) (string, error) {
+	ruta := filepath.Join(almacenamiento.directorio, filepath.FromSlash(clave))
+	relativa, err := filepath.Rel(almacenamiento.directorio, ruta)
+	if err != nil || relativa == "." || strings.HasPrefix(relativa, "..") {
+		return "", errors.New("clave de almacenamiento inválida: " + clave)
+	}
+	return ruta, nil
+}
@@ -0,0 +1,41 @@
+package utils
+
+// Dieta es una etiqueta que se deduce de los alimentos de una receta, no la elige el usuario
+type Dieta string
+
+const (
+	DietaVegetariana     Dieta = "vegetariano"
+	DietaVegana          Dieta = "vegano"
+	DietaSinLacteos      Dieta = "sin_lacteos"
+	DietaSinGluten       Dieta = "sin_gluten"
+	DietaAltaEnProteinas Dieta = "alto_en_proteina"
+)
+
+func (dieta Dieta) EsValida() bool {
+	switch dieta {
+	case DietaVegetariana, DietaVegana, DietaSinLacteos, DietaSinGluten, DietaAltaEnProteinas:
+		return true
+	}
+	return false
+}
+
+// AtributoAlimento completa lo que la categoría no dice del alimento, por ejemplo que la harina tiene gluten
+// o que el huevo es de origen animal aunque no sea carne ni lácteo
+type AtributoAlimento string
+
+const (
+	AtributoContieneGluten AtributoAlimento = "contiene_gluten"
+	AtributoOrigenAnimal   AtributoAlimento = "origen_animal" // Huevos, miel: no es vegano pero sí vegetariano
+	AtributoCarne          AtributoAlimento = "carne"         // Gelatina, caldo de carne: no es vegetariano
+	AtributoLacteo         AtributoAlimento = "lacteo"        // Manteca, crema de otra categoría
+	AtributoRicoEnProteina AtributoAlimento = "rico_en_proteina"
+	AtributoSinGluten      AtributoAlimento = "sin_gluten" // Arroz, maíz: un cereal que no tiene gluten
+)
+
+func (atributo AtributoAlimento) EsValido() bool {
+	switch atributo {
+	case AtributoContieneGluten, AtributoOrigenAnimal, AtributoCarne, AtributoLacteo, AtributoRicoEnProteina, AtributoSinGluten:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,50 @@
+package utils
+
+// Equipo es un aparato de cocina que una receta puede requerir y que el usuario puede tener o no
+type Equipo string
+
+const (
+	EquipoHorno       Equipo = "horno"
+	EquipoMicroondas  Equipo = "microondas"
+	EquipoProcesadora Equipo = "procesadora"
+	EquipoAirFryer    Equipo = "air_fryer"
+)
+
+// Otros nombres con los que se reconoce cada equipo al importar, ya normalizados
+var sinonimosEquipos = map[string]Equipo{
+	"horno":               EquipoHorno,
+	"horno electrico":     EquipoHorno,
+	"oven":                EquipoHorno,
+	"microonda":           EquipoMicroondas,
+	"microondas":          EquipoMicroondas,
+	"microwave":           EquipoMicroondas,
+	"procesadora":         EquipoProcesadora,
+	"procesador":          EquipoProcesadora,
+	"food processor":      EquipoProcesadora,
+	"air fryer":           EquipoAirFryer,
+	"airfryer":            EquipoAirFryer,
+	"freidora de aire":    EquipoAirFryer,
+	"freidora sin aceite": EquipoAirFryer,
+}
+
+func (equipo Equipo) EsValido() bool {
+	switch equipo {
+	case EquipoHorno, EquipoMicroondas, EquipoProcesadora, EquipoAirFryer:
+		return true
+	}
+	return false
+}
+
+// Nombre devuelve el equipo como se escribe en una receta
+func (equipo Equipo) Nombre() string {
+	if equipo == EquipoAirFryer {
+		return "air fryer"
+	}
+	return string(equipo)
+}
+
+// EquipoDesdeNombre reconoce el equipo por su nombre o alguno de sus sinónimos, sin importar mayúsculas ni acentos
+func EquipoDesdeNombre(nombre string) (Equipo, bool) {
+	equipo, existe := sinonimosEquipos[NormalizarTexto(nombre)]
+	return equipo, existe
+}
@@ -1,5 +1,7 @@
 package utils
 
+import "strconv"
+
 type Momento int
 
 const (
@@ -10,7 +12,44 @@ const (
 	Cena
 )
 
+// Los momentos que define cada usuario se numeran desde acá, para no chocar con los predefinidos
+const PrimerMomentoPersonalizado Momento = 100
+
+var nombresMomentos = [...]string{"Indefinido", "Desayuno", "Almuerzo", "Merienda", "Cena"}
+
 // Método para convertir los enums en cadenas
 func (momento Momento) String() string {
-	return [...]string{"Indefinido", "Desayuno", "Almuerzo", "Merienda", "Cena"}[momento]
+	switch {
+	case momento.EsPredefinido():
+		return nombresMomentos[momento]
+	case momento.EsPersonalizado():
+		return "Personalizado " + strconv.Itoa(int(momento))
+	}
+	return nombresMomentos[MomentoDefault]
+}
+
+func (momento Momento) EsPredefinido() bool {
+	return momento >= Desayuno && momento <= Cena
+}
+
+func (momento Momento) EsPersonalizado() bool {
+	return momento >= PrimerMomentoPersonalizado
+}
+
+// MomentosPredefinidos devuelve los momentos que existen para todos los usuarios
+func MomentosPredefinidos() []Momento {
+	return []Momento{Desayuno, Almuerzo, Merienda, Cena}
+}
+
+// NombresDeMomentos devuelve el nombre de cada momento, buscando los personalizados entre los del usuario
+func NombresDeMomentos(momentos []Momento, personalizados map[Momento]string) []string {
+	nombres := []string{}
+	for _, momento := range momentos {
+		if nombre, existe := personalizados[momento]; existe {
+			nombres = append(nombres, nombre)
+		} else {
+			nombres = append(nombres, momento.String())
+		}
+	}
+	return nombres
 }
@@ -0,0 +1,8 @@
+package utils
+
+type EstadoPlan string
+
+const (
+	EstadoPlanBorrador EstadoPlan = "borrador" // Recién generado, se puede modificar
+	EstadoPlanAceptado EstadoPlan = "aceptado"
+)
@@ -0,0 +1,170 @@
+package utils
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+var sinDiacriticos = map[rune]rune{
+	'á': 'a', 'à': 'a', 'ä': 'a', 'â': 'a', 'ã': 'a',
+	'é': 'e', 'è': 'e', 'ë': 'e', 'ê': 'e',
+	'í': 'i', 'ì': 'i', 'ï': 'i', 'î': 'i',
+	'ó': 'o', 'ò': 'o', 'ö': 'o', 'ô': 'o', 'õ': 'o',
+	'ú': 'u', 'ù': 'u', 'ü': 'u', 'û': 'u',
+	'ñ': 'n', 'ç': 'c',
+}
+
+// NormalizarTexto pasa el texto a minúsculas, le quita los acentos y deja un solo espacio entre palabras
+func NormalizarTexto(texto string) string {
+	var resultado strings.Builder
+	espacio := false
+	for _, letra := range strings.ToLower(texto) {
+		if reemplazo, existe := sinDiacriticos[letra]; existe {
+			letra = reemplazo
+		}
+		if !unicode.IsLetter(letra) && !unicode.IsDigit(letra) {
+			espacio = resultado.Len() > 0
+			continue
+		}
+		if espacio {
+			resultado.WriteRune(' ')
+			espacio = false
+		}
+		resultado.WriteRune(letra)
+	}
+	return resultado.String()
+}
+
+// Singularizar aplica las reglas básicas del plural en castellano a una palabra ya normalizada
+func Singularizar(palabra string) string {
+	largo := len(palabra)
+	switch {
+	case largo > 4 && strings.HasSuffix(palabra, "ces"):
+		return palabra[:largo-3] + "z"
+	case largo > 4 && strings.HasSuffix(palabra, "es") && strings.ContainsRune("lnrdjy", rune(palabra[largo-3])):
+		return palabra[:largo-2]
+	case largo > 3 && strings.HasSuffix(palabra, "s") && !strings.HasSuffix(palabra, "ss"):
+		return palabra[:largo-1]
+	}
+	return palabra
+}
+
+// ContieneTexto indica si buscado aparece en texto sin importar mayúsculas ni acentos ("pure" encuentra "Puré")
+func ContieneTexto(texto string, buscado string) bool {
+	return strings.Contains(NormalizarTexto(texto), NormalizarTexto(buscado))
+}
+
+// ClaveNombre devuelve una forma canónica del nombre para comparar alimentos ("Tomates" y "tomate" dan lo mismo)
+func ClaveNombre(nombre string) string {
+	palabras := strings.Fields(NormalizarTexto(nombre))
+	for i, palabra := range palabras {
+		palabras[i] = Singularizar(palabra)
+	}
+	return strings.Join(palabras, " ")
+}
+
+// Levenshtein calcula la distancia de edición entre dos textos
+func Levenshtein(a, b string) int {
+	runasA, runasB := []rune(a), []rune(b)
+	anterior := make([]int, len(runasB)+1)
+	actual := make([]int, len(runasB)+1)
+	for j := range anterior {
+		anterior[j] = j
+	}
+	for i := 1; i <= len(runasA); i++ {
+		actual[0] = i
+		for j := 1; j <= len(runasB); j++ {
+			costo := 1
+			if runasA[i-1] == runasB[j-1] {
+				costo = 0
+			}
+			actual[j] = min(anterior[j]+1, actual[j-1]+1, anterior[j-1]+costo)
+		}
+		anterior, actual = actual, anterior
+	}
+	return anterior[len(runasB)]
+}
+
+// Similitud devuelve un valor entre 0 y 1 según lo parecidos que son dos nombres
+func Similitud(a, b string) float64 {
+	a, b = ClaveNombre(a), ClaveNombre(b)
+	if a == "" || b == "" {
+		return 0
+	}
+	if a == b {
+		return 1
+	}
+	largo := max(len([]rune(a)), len([]rune(b)))
+	return 1 - float64(Levenshtein(a, b))/float64(largo)
+}
+
+// PuntajeAutocompletado indica qué tan bien completa nombre lo que se lleva escrito, entre 0 (no sirve) y 1.
+// Primero cuenta que el nombre o alguna de sus palabras empiece con el texto; si no, tolera errores de tipeo
+// comparando contra el comienzo de cada palabra ("tomat" y "tonate" completan "Tomate perita").
+func PuntajeAutocompletado(buscado string, nombre string) float64 {
+	texto, candidato := NormalizarTexto(buscado), NormalizarTexto(nombre)
+	if texto == "" || candidato == "" {
+		return 0
+	}
+	if texto == candidato || ClaveNombre(texto) == ClaveNombre(candidato) {
+		return 1
+	}
+
+	// Entre dos nombres que completan igual de bien, va primero el más corto
+	cobertura := math.Min(1, float64(len([]rune(texto)))/float64(len([]rune(candidato))))
+	if strings.HasPrefix(candidato, texto) {
+		return 0.8 + 0.15*cobertura
+	}
+	palabras := strings.Fields(candidato)
+	for _, palabra := range palabras {
+		if strings.HasPrefix(palabra, texto) {
+			return 0.7 + 0.15*cobertura
+		}
+	}
+
+	tolerancia := erroresTolerados(texto)
+	distancia := tolerancia + 1
+	for _, opcion := range append([]string{candidato}, palabras...) {
+		distancia = min(distancia, distanciaAPrefijo(texto, opcion))
+	}
+	if distancia > tolerancia {
+		return 0
+	}
+	return 0.6 + 0.15*cobertura - 0.1*float64(distancia)
+}
+
+// erroresTolerados devuelve cuántos errores de tipeo se aceptan según el largo de lo escrito
+func erroresTolerados(texto string) int {
+	largo := len([]rune(texto))
+	switch {
+	case largo < 3:
+		return 0
+	case largo < 6:
+		return 1
+	}
+	return 2
+}
+
+// distanciaAPrefijo es la menor distancia de edición entre texto y algún comienzo de candidato
+func distanciaAPrefijo(texto string, candidato string) int {
+	runasTexto, runasCandidato := []rune(texto), []rune(candidato)
+	anterior := make([]int, len(runasCandidato)+1)
+	actual := make([]int, len(runasCandidato)+1)
+	for j := range anterior {
+		anterior[j] = j
+	}
+	for i := 1; i <= len(runasTexto); i++ {
+		actual[0] = i
+		for j := 1; j <= len(runasCandidato); j++ {
+			costo := 1
+			if runasTexto[i-1] == runasCandidato[j-1] {
+				costo = 0
+			}
+			actual[j] = min(anterior[j]+1, actual[j-1]+1, anterior[j-1]+costo)
+		}
+		anterior, actual = actual, anterior
+	}
+	return slices.Min(anterior)
+}
@@ -0,0 +1,13 @@
+package utils
+
+type Visibilidad string
+
+const (
+	VisibilidadPrivada    Visibilidad = "privada"
+	VisibilidadCompartida Visibilidad = "compartida" // Visible para los usuarios de CompartidaCon
+	VisibilidadPublica    Visibilidad = "publica"    // Visible para todos en el catálogo
+)
+
+func (visibilidad Visibilidad) EsValida() bool {
+	return visibilidad == VisibilidadPrivada || visibilidad == VisibilidadCompartida || visibilidad == VisibilidadPublica
+}